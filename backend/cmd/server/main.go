@@ -9,16 +9,22 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/zyntra/backend/internal/auth"
+	"github.com/zyntra/backend/internal/channels"
+	"github.com/zyntra/backend/internal/channels/apichannel"
+	"github.com/zyntra/backend/internal/channels/telegram"
 	"github.com/zyntra/backend/internal/channels/whatsapp"
 	"github.com/zyntra/backend/internal/database"
 	"github.com/zyntra/backend/internal/handlers"
 	"github.com/zyntra/backend/internal/middleware"
+	"github.com/zyntra/backend/internal/ports"
 	"github.com/zyntra/backend/internal/repository"
 	"github.com/zyntra/backend/internal/router"
 	"github.com/zyntra/backend/internal/services"
 	natspkg "github.com/zyntra/backend/pkg/nats"
-	wapkg "github.com/zyntra/backend/pkg/whatsapp"
+	"github.com/zyntra/backend/pkg/storage"
+	tgpkg "github.com/zyntra/backend/pkg/telegram"
 	wspkg "github.com/zyntra/backend/pkg/websocket"
+	wapkg "github.com/zyntra/backend/pkg/whatsapp"
 )
 
 func main() {
@@ -52,28 +58,54 @@ func main() {
 		log.Fatalf("Failed to initialize WhatsApp store: %v", err)
 	}
 
-	// WhatsApp Manager (internal/channels/whatsapp)
-	waManager := whatsapp.NewManager(waStore)
+	// Storage (pkg/storage: local ou S3)
+	attachmentStore, err := storage.New(context.Background(), storage.DefaultConfig())
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
+	// Channel Registry (internal/channels)
+	channelRegistry := channels.NewRegistry()
+	channelRegistry.RegisterFactory(ports.ChannelTypeWhatsApp, whatsapp.NewFactory(waStore, attachmentStore.MaxSize()))
+	channelRegistry.RegisterFactory(ports.ChannelTypeTelegram, telegram.NewFactory(tgpkg.DefaultConfig()))
+	channelRegistry.RegisterFactory(ports.ChannelTypeAPI, apichannel.NewFactory())
 
 	// Repositories
 	inboxRepo := repository.NewInboxRepository(db.DB)
 	waChannelRepo := repository.NewChannelWhatsAppRepository(db.DB)
+	tgChannelRepo := repository.NewChannelTelegramRepository(db.DB)
+	apiChannelRepo := repository.NewChannelAPIRepository(db.DB)
 	memberRepo := repository.NewInboxMemberRepository(db.DB)
 	contactRepo := repository.NewContactRepository(db.DB)
 	contactInboxRepo := repository.NewContactInboxRepository(db.DB)
 	conversationRepo := repository.NewConversationRepository(db.DB)
 	messageRepo := repository.NewMessageRepository(db.DB)
 	labelRepo := repository.NewLabelRepository(db.DB)
+	attachmentRepo := repository.NewAttachmentRepository(db.DB)
+	notificationRepo := repository.NewNotificationRepository(db.DB)
+	reactionRepo := repository.NewReactionRepository(db.DB)
+	groupRepo := repository.NewGroupRepository(db.DB)
+	connEventRepo := repository.NewConnectionEventRepository(db.DB)
+	contactProfileRepo := repository.NewContactProfileRepository(db.DB)
 
 	// Services
-	inboxService := services.NewInboxService(inboxRepo, waChannelRepo, memberRepo, waManager)
+	inboxService := services.NewInboxService(inboxRepo, waChannelRepo, tgChannelRepo, apiChannelRepo, memberRepo, connEventRepo, channelRegistry)
 	contactService := services.NewContactService(contactRepo, contactInboxRepo)
-	conversationService := services.NewConversationService(conversationRepo, contactRepo, labelRepo, inboxRepo, messageRepo)
-	messageService := services.NewMessageService(messageRepo, conversationRepo, contactRepo, contactInboxRepo, inboxRepo, waManager)
+	conversationService := services.NewConversationService(conversationRepo, contactRepo, labelRepo, inboxRepo, messageRepo, groupRepo)
+	messageService := services.NewMessageService(messageRepo, conversationRepo, contactRepo, contactInboxRepo, inboxRepo, attachmentRepo, notificationRepo, reactionRepo, attachmentStore, channelRegistry)
+	groupService := services.NewGroupService(groupRepo, conversationRepo, contactRepo, contactInboxRepo, channelRegistry)
+	messageService.SetGroupService(groupService)
+
+	// Fotos e perfis dos contatos (sincronizados pelo canal)
+	contactProfileService := services.NewContactProfileService(contactRepo, contactProfileRepo, attachmentStore, channelRegistry)
+	contactService.SetProfileService(contactProfileService)
+	conversationService.SetProfileService(contactProfileService)
+	messageService.SetProfileService(contactProfileService)
+	contactProfileService.Start()
 
 	// Event Handler (conecta canal aos services)
 	eventHandler := services.NewChannelEventHandler(inboxService, messageService)
-	waManager.SetEventHandler(eventHandler)
+	channelRegistry.SetEventHandler(eventHandler)
 
 	// Auth
 	jwtService := auth.NewJWTService(nil)
@@ -91,16 +123,52 @@ func main() {
 	messageHandler := handlers.NewMessageHandler(messageService)
 	contactHandler := handlers.NewContactHandler(contactService, conversationService)
 	labelHandler := handlers.NewLabelHandler(labelRepo)
+	notificationHandler := handlers.NewNotificationHandler(notificationRepo)
+	groupHandler := handlers.NewGroupHandler(groupService)
+	telegramHandler := handlers.NewTelegramWebhookHandler(channelRegistry)
+	apiChannelHandler := handlers.NewAPIChannelHandler(channelRegistry)
+	var fileHandler *handlers.FileHandler
+	if local, ok := attachmentStore.Backend().(*storage.LocalBackend); ok {
+		fileHandler = handlers.NewFileHandler(local)
+	}
 
 	// WebSocket Hub (pkg/websocket)
 	wsHub := wspkg.NewHub()
+
+	// Realtime Relay (eventos do hub entre replicas; sem NATS o hub atende so esta instancia)
+	var realtimeRelay *services.RealtimeRelay
+	if natsClient != nil {
+		realtimeRelay = services.NewRealtimeRelay(natsClient, wsHub)
+		if err := realtimeRelay.Start(); err != nil {
+			log.Printf("Warning: Realtime relay not available: %v", err)
+			realtimeRelay = nil
+		} else {
+			wsHub.SetRelay(realtimeRelay)
+		}
+	}
+	wsHub.SetAccessLoader(inboxService.GetUserInboxes)
+	inboxService.SetAccessNotifier(wsHub)
 	go wsHub.Run()
-	wsHandler := handlers.NewWebSocketHandler(wsHub)
+	wsHandler := handlers.NewWebSocketHandler(wsHub, inboxService, conversationService)
+	eventsHandler := handlers.NewEventsHandler(wsHub, inboxService, conversationService)
 
 	// Broadcaster (conecta WebSocket ao MessageService para real-time)
 	broadcaster := services.NewWebSocketBroadcaster(wsHub)
 	messageService.SetBroadcaster(broadcaster)
 
+	// Outbound Queue (entrega assincrona com retry; sem NATS o envio e sincrono)
+	var outboundQueue *services.OutboundQueue
+	if natsClient != nil {
+		outboundQueue = services.NewOutboundQueue(natsClient, messageService)
+		if err := outboundQueue.Start(context.Background()); err != nil {
+			log.Printf("Warning: Outbound queue not available: %v", err)
+			outboundQueue = nil
+		} else {
+			messageService.SetOutboundQueue(outboundQueue)
+			eventHandler.SetOutboundQueue(outboundQueue)
+		}
+	}
+
 	// Echo
 	e := echo.New()
 	e.HideBanner = true
@@ -119,9 +187,15 @@ func main() {
 		Contact:      contactHandler,
 		Label:        labelHandler,
 		WebSocket:    wsHandler,
+		Events:       eventsHandler,
+		Telegram:     telegramHandler,
+		APIChannel:   apiChannelHandler,
+		Files:        fileHandler,
+		Notification: notificationHandler,
+		Group:        groupHandler,
 	})
 
-	// Restore WhatsApp connections
+	// Restore channel connections
 	go func() {
 		ctx := context.Background()
 		if err := inboxService.RestoreConnections(ctx); err != nil {
@@ -149,7 +223,14 @@ func main() {
 
 	log.Println("Shutting down...")
 
-	waManager.Shutdown()
+	if outboundQueue != nil {
+		outboundQueue.Stop()
+	}
+	contactProfileService.Stop()
+	channelRegistry.Shutdown()
+	if realtimeRelay != nil {
+		realtimeRelay.Stop()
+	}
 
 	if natsClient != nil {
 		natsClient.Close()
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// StreamTicketTTL is how long a stream ticket can be redeemed after being issued
+const StreamTicketTTL = 30 * time.Second
+
+// ErrStreamTicketInvalid is returned for unknown, used or expired tickets
+var ErrStreamTicketInvalid = errors.New("stream ticket is invalid or expired")
+
+// StreamTicket is a single-use credential for opening a WebSocket or SSE stream.
+// Browsers cannot send the Authorization header on those requests, and a ticket
+// in the query string is harmless once redeemed, unlike the access token.
+type StreamTicket struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// StreamTicketService issues and redeems stream tickets.
+// Tickets live in the database so any replica can redeem them.
+type StreamTicketService struct {
+	db *sql.DB
+}
+
+// NewStreamTicketService creates a new stream ticket service
+func NewStreamTicketService(db *sql.DB) *StreamTicketService {
+	return &StreamTicketService{db: db}
+}
+
+// Issue creates a ticket for the authenticated user
+func (s *StreamTicketService) Issue(ctx context.Context, userID, email, role string) (*StreamTicket, error) {
+	ticketBytes := make([]byte, 32)
+	if _, err := rand.Read(ticketBytes); err != nil {
+		return nil, err
+	}
+	ticket := hex.EncodeToString(ticketBytes)
+	expiresAt := time.Now().Add(StreamTicketTTL)
+
+	// Drop expired tickets that were never redeemed
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM stream_tickets WHERE expires_at < NOW()`); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO stream_tickets (ticket_hash, user_id, email, role, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := s.db.ExecContext(ctx, query, hashStreamTicket(ticket), userID, email, role, expiresAt); err != nil {
+		return nil, err
+	}
+
+	return &StreamTicket{Ticket: ticket, ExpiresAt: expiresAt}, nil
+}
+
+// Redeem consumes the ticket and returns the user it was issued to
+func (s *StreamTicketService) Redeem(ctx context.Context, ticket string) (*Claims, error) {
+	query := `
+		DELETE FROM stream_tickets
+		WHERE ticket_hash = $1
+		RETURNING user_id, email, role, expires_at
+	`
+
+	var claims Claims
+	var expiresAt time.Time
+	err := s.db.QueryRowContext(ctx, query, hashStreamTicket(ticket)).Scan(
+		&claims.UserID, &claims.Email, &claims.Role, &expiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrStreamTicketInvalid
+		}
+		return nil, err
+	}
+	if time.Now().After(expiresAt) {
+		return nil, ErrStreamTicketInvalid
+	}
+
+	return &claims, nil
+}
+
+func hashStreamTicket(ticket string) string {
+	hash := sha256.Sum256([]byte(ticket))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,376 @@
+package apichannel
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zyntra/backend/internal/ports"
+)
+
+const (
+	// SignatureHeader header com a assinatura HMAC do payload
+	SignatureHeader = "X-Zyntra-Signature"
+	// TimestampHeader header com o timestamp usado na assinatura
+	TimestampHeader = "X-Zyntra-Timestamp"
+)
+
+// Eventos enviados ao webhook_url
+const (
+	EventMessageCreated  = "message.created"
+	EventMessageReaction = "message.reaction"
+	EventMessageUpdated  = "message.updated"
+	EventMessageDeleted  = "message.deleted"
+)
+
+var (
+	// ErrInvalidAPIKey api_key do canal nao confere
+	ErrInvalidAPIKey = errors.New("invalid channel api key")
+	// ErrInboundFailed mensagem valida que nao pode ser gravada
+	ErrInboundFailed = errors.New("failed to process inbound message")
+)
+
+// InboundMessage mensagem recebida pelo endpoint REST do canal
+type InboundMessage struct {
+	SourceID     string     `json:"source_id,omitempty"`
+	ContactID    string     `json:"contact_id"`
+	ContactName  string     `json:"contact_name,omitempty"`
+	ContactPhone string     `json:"contact_phone,omitempty"`
+	Content      string     `json:"content"`
+	ReplyTo      string     `json:"reply_to,omitempty"` // source_id da mensagem respondida
+	Timestamp    *time.Time `json:"timestamp,omitempty"`
+}
+
+// OutboundPayload payload enviado ao webhook_url do canal
+type OutboundPayload struct {
+	Event     string          `json:"event"`
+	InboxID   string          `json:"inbox_id"`
+	Message   OutboundMessage `json:"message"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// OutboundMessage mensagem de agente enviada ao webhook.
+// Em message.created o source_id e estavel entre retentativas da mesma mensagem
+// e deve ser usado pelo receptor para descartar duplicatas.
+// Em message.reaction, message.updated e message.deleted o source_id
+// identifica a mensagem alvo; reaction ausente remove a reacao.
+type OutboundMessage struct {
+	SourceID   string              `json:"source_id"`
+	To         string              `json:"to"`
+	Content    string              `json:"content,omitempty"`
+	ReplyTo    string              `json:"reply_to,omitempty"`
+	Reaction   string              `json:"reaction,omitempty"`
+	Attachment *OutboundAttachment `json:"attachment,omitempty"`
+	Location   *ports.Location     `json:"location,omitempty"`
+	Contact    *ports.ContactCard  `json:"contact,omitempty"`
+}
+
+// OutboundAttachment midia enviada ao webhook
+type OutboundAttachment struct {
+	Type     ports.MediaType `json:"type"`
+	URL      string          `json:"url,omitempty"`
+	Data     []byte          `json:"data,omitempty"`
+	MimeType string          `json:"mime_type,omitempty"`
+	FileName string          `json:"file_name,omitempty"`
+	Voice    bool            `json:"voice,omitempty"`
+}
+
+// Adapter implementa ports.Channel entregando mensagens via webhook HTTP
+type Adapter struct {
+	inboxID       string
+	webhookURL    string
+	apiKey        string
+	webhookSecret string
+	http          *http.Client
+	handler       ports.ChannelEventHandler
+	status        ports.ChannelStatus
+	mu            sync.RWMutex
+}
+
+// NewAdapter cria novo adapter.
+// apiKey autentica mensagens recebidas; webhookSecret assina as enviadas ao webhook_url.
+func NewAdapter(webhookURL, apiKey, webhookSecret string) *Adapter {
+	return &Adapter{
+		webhookURL:    webhookURL,
+		apiKey:        apiKey,
+		webhookSecret: webhookSecret,
+		http:          &http.Client{Timeout: 15 * time.Second},
+		status:        ports.ChannelStatusDisconnected,
+	}
+}
+
+// Type retorna o tipo do canal
+func (a *Adapter) Type() ports.ChannelType {
+	return ports.ChannelTypeAPI
+}
+
+// Connect habilita o canal (nao ha sessao remota)
+func (a *Adapter) Connect(ctx context.Context, inboxID string) error {
+	a.mu.Lock()
+	a.inboxID = inboxID
+	a.status = ports.ChannelStatusConnected
+	a.mu.Unlock()
+
+	if a.handler != nil {
+		a.handler.OnConnected(inboxID, "")
+	}
+	return nil
+}
+
+// Disconnect desabilita o canal
+func (a *Adapter) Disconnect(ctx context.Context) error {
+	a.mu.Lock()
+	a.status = ports.ChannelStatusDisconnected
+	a.mu.Unlock()
+	return nil
+}
+
+// Status retorna o status atual
+func (a *Adapter) Status() ports.ChannelStatus {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.status
+}
+
+// SendText envia mensagem de texto ao webhook
+func (a *Adapter) SendText(ctx context.Context, to, content string, opts ports.SendOptions) (string, error) {
+	return a.deliver(ctx, EventMessageCreated, OutboundMessage{
+		SourceID: sourceID(opts),
+		To:       to,
+		Content:  content,
+		ReplyTo:  replyTo(opts),
+	})
+}
+
+// SendMedia envia mensagem com midia ao webhook
+func (a *Adapter) SendMedia(ctx context.Context, to string, media ports.Media, opts ports.SendOptions) (string, error) {
+	return a.deliver(ctx, EventMessageCreated, OutboundMessage{
+		SourceID: sourceID(opts),
+		To:       to,
+		Content:  media.Caption,
+		ReplyTo:  replyTo(opts),
+		Attachment: &OutboundAttachment{
+			Type:     media.Type,
+			URL:      media.URL,
+			Data:     media.Data,
+			MimeType: media.MimeType,
+			FileName: media.FileName,
+			Voice:    media.Voice,
+		},
+	})
+}
+
+// SendLocation envia localizacao ao webhook
+func (a *Adapter) SendLocation(ctx context.Context, to string, location ports.Location, opts ports.SendOptions) (string, error) {
+	return a.deliver(ctx, EventMessageCreated, OutboundMessage{
+		SourceID: sourceID(opts),
+		To:       to,
+		ReplyTo:  replyTo(opts),
+		Location: &location,
+	})
+}
+
+// SendContact envia cartao de contato ao webhook
+func (a *Adapter) SendContact(ctx context.Context, to string, contact ports.ContactCard, opts ports.SendOptions) (string, error) {
+	return a.deliver(ctx, EventMessageCreated, OutboundMessage{
+		SourceID: sourceID(opts),
+		To:       to,
+		ReplyTo:  replyTo(opts),
+		Contact:  &contact,
+	})
+}
+
+// SendReaction envia reacao ao webhook
+func (a *Adapter) SendReaction(ctx context.Context, to string, target ports.MessageRef, emoji string) error {
+	_, err := a.deliver(ctx, EventMessageReaction, OutboundMessage{
+		SourceID: target.SourceID,
+		To:       to,
+		Reaction: emoji,
+	})
+	return err
+}
+
+// EditMessage envia edicao ao webhook
+func (a *Adapter) EditMessage(ctx context.Context, to string, target ports.MessageRef, content string) error {
+	_, err := a.deliver(ctx, EventMessageUpdated, OutboundMessage{
+		SourceID: target.SourceID,
+		To:       to,
+		Content:  content,
+	})
+	return err
+}
+
+// DeleteMessage envia remocao ao webhook
+func (a *Adapter) DeleteMessage(ctx context.Context, to string, target ports.MessageRef) error {
+	_, err := a.deliver(ctx, EventMessageDeleted, OutboundMessage{
+		SourceID: target.SourceID,
+		To:       to,
+	})
+	return err
+}
+
+// SetEventHandler define o handler de eventos
+func (a *Adapter) SetEventHandler(handler ports.ChannelEventHandler) {
+	a.handler = handler
+}
+
+// GetQRCode canal API nao usa QR code
+func (a *Adapter) GetQRCode() string {
+	return ""
+}
+
+// HandleInbound valida a api_key e repassa a mensagem ao handler
+func (a *Adapter) HandleInbound(apiKey string, msg InboundMessage) error {
+	if a.apiKey == "" || subtle.ConstantTimeCompare([]byte(apiKey), []byte(a.apiKey)) != 1 {
+		return ErrInvalidAPIKey
+	}
+	if msg.ContactID == "" {
+		return fmt.Errorf("contact_id is required")
+	}
+	if msg.Content == "" {
+		return fmt.Errorf("content is required")
+	}
+
+	if msg.SourceID == "" {
+		msg.SourceID = uuid.New().String()
+	}
+	timestamp := time.Now()
+	if msg.Timestamp != nil {
+		timestamp = *msg.Timestamp
+	}
+
+	if a.handler == nil {
+		return nil
+	}
+	event := ports.IncomingEvent{
+		InboxID:      a.inboxID,
+		SourceID:     msg.SourceID,
+		ContactID:    msg.ContactID,
+		ContactName:  msg.ContactName,
+		ContactPhone: msg.ContactPhone,
+		Type:         ports.EventTypeMessage,
+		Content:      msg.Content,
+		ReplyTo:      msg.ReplyTo,
+		Timestamp:    timestamp,
+	}
+
+	// Com receiver a falha volta ao remetente, que pode reenviar o mesmo source_id
+	if receiver, ok := a.handler.(ports.MessageReceiver); ok {
+		if err := receiver.ReceiveMessage(event); err != nil {
+			return fmt.Errorf("%w: %v", ErrInboundFailed, err)
+		}
+		return nil
+	}
+	a.handler.OnMessage(event)
+	return nil
+}
+
+// deliver faz POST assinado no webhook_url.
+// Uma unica tentativa: as retentativas ficam com a fila de saida.
+func (a *Adapter) deliver(ctx context.Context, event string, msg OutboundMessage) (string, error) {
+	if a.Status() != ports.ChannelStatusConnected {
+		return "", fmt.Errorf("inbox %s not connected", a.inboxID)
+	}
+	if a.webhookURL == "" {
+		return "", fmt.Errorf("inbox %s has no webhook_url", a.inboxID)
+	}
+
+	body, err := json.Marshal(OutboundPayload{
+		Event:     event,
+		InboxID:   a.inboxID,
+		Message:   msg,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	if err := a.post(ctx, body); err != nil {
+		return "", fmt.Errorf("failed to deliver to webhook: %w", err)
+	}
+	return msg.SourceID, nil
+}
+
+// post envia o payload assinado com o webhook_secret do canal
+func (a *Adapter) post(ctx context.Context, body []byte) error {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(a.webhookSecret, timestamp, body))
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign calcula HMAC-SHA256 de "timestamp.body" com o webhook_secret do canal
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateAPIKey gera nova api_key para um canal
+func GenerateAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	return "zch_" + hex.EncodeToString(b), nil
+}
+
+// GenerateWebhookSecret gera novo segredo de assinatura do webhook
+func GenerateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// sourceID chave estavel do envio; sem mensagem de origem usa um id novo
+func sourceID(opts ports.SendOptions) string {
+	if key := opts.IdempotencyKey(); key != "" {
+		return key
+	}
+	return uuid.New().String()
+}
+
+// replyTo retorna o source_id da mensagem respondida
+func replyTo(opts ports.SendOptions) string {
+	if opts.ReplyTo == nil {
+		return ""
+	}
+	return opts.ReplyTo.SourceID
+}
+
+// Verify interface implementation
+var _ ports.Channel = (*Adapter)(nil)
@@ -0,0 +1,124 @@
+package apichannel
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/zyntra/backend/internal/ports"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"message.created"}`)
+
+	mac := hmac.New(sha256.New, []byte("whsec_test"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign("whsec_test", "1700000000", body); got != want {
+		t.Fatalf("Sign() = %s, want %s", got, want)
+	}
+	if Sign("other", "1700000000", body) == want {
+		t.Fatal("Sign() must depend on the secret")
+	}
+	if Sign("whsec_test", "1700000001", body) == want {
+		t.Fatal("Sign() must depend on the timestamp")
+	}
+}
+
+func TestSendTextDelivery(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		opts       ports.SendOptions
+		wantSource string
+		wantErr    bool
+	}{
+		{name: "first part uses message id", status: http.StatusOK, opts: ports.SendOptions{MessageID: "msg-1"}, wantSource: "msg-1"},
+		{name: "later part is suffixed", status: http.StatusOK, opts: ports.SendOptions{MessageID: "msg-1", Part: 2}, wantSource: "msg-1.2"},
+		{name: "server error is not retried", status: http.StatusInternalServerError, opts: ports.SendOptions{MessageID: "msg-1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			var payload OutboundPayload
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				body, _ := io.ReadAll(r.Body)
+				if got := r.Header.Get(SignatureHeader); got != "sha256="+Sign("whsec_test", r.Header.Get(TimestampHeader), body) {
+					t.Errorf("signature %q not made with the webhook secret", got)
+				}
+				json.Unmarshal(body, &payload)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			adapter := NewAdapter(srv.URL, "zch_test", "whsec_test")
+			adapter.Connect(context.Background(), "inbox-1")
+
+			sourceID, err := adapter.SendText(context.Background(), "contact-1", "oi", tt.opts)
+			if calls.Load() != 1 {
+				t.Fatalf("webhook called %d times, want 1", calls.Load())
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SendText() error = %v", err)
+			}
+			if sourceID != tt.wantSource || payload.Message.SourceID != tt.wantSource {
+				t.Fatalf("source_id = %q (payload %q), want %q", sourceID, payload.Message.SourceID, tt.wantSource)
+			}
+		})
+	}
+}
+
+// fakeReceiver ChannelEventHandler que tambem implementa ports.MessageReceiver
+type fakeReceiver struct {
+	ports.ChannelEventHandler
+	err error
+}
+
+func (f *fakeReceiver) ReceiveMessage(event ports.IncomingEvent) error {
+	return f.err
+}
+
+func TestHandleInbound(t *testing.T) {
+	tests := []struct {
+		name    string
+		apiKey  string
+		err     error
+		wantErr error
+	}{
+		{name: "accepted", apiKey: "zch_test"},
+		{name: "invalid api key", apiKey: "wrong", wantErr: ErrInvalidAPIKey},
+		{name: "persistence failure is returned", apiKey: "zch_test", err: errors.New("db down"), wantErr: ErrInboundFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := NewAdapter("", "zch_test", "whsec_test")
+			adapter.SetEventHandler(&fakeReceiver{err: tt.err})
+
+			err := adapter.HandleInbound(tt.apiKey, InboundMessage{ContactID: "contact-1", Content: "oi"})
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("HandleInbound() error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("HandleInbound() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package apichannel
+
+import (
+	"fmt"
+
+	"github.com/zyntra/backend/internal/channels"
+	"github.com/zyntra/backend/internal/ports"
+)
+
+// NewFactory retorna factory de adapters do canal API.
+// Config: "webhook_url", "api_key" e "webhook_secret".
+func NewFactory() channels.FactoryFunc {
+	return func(config map[string]interface{}) (ports.Channel, error) {
+		apiKey := channels.ConfigString(config, "api_key")
+		if apiKey == "" {
+			return nil, fmt.Errorf("api_key is required")
+		}
+		webhookSecret := channels.ConfigString(config, "webhook_secret")
+		if webhookSecret == "" {
+			return nil, fmt.Errorf("webhook_secret is required")
+		}
+		return NewAdapter(channels.ConfigString(config, "webhook_url"), apiKey, webhookSecret), nil
+	}
+}
@@ -0,0 +1,241 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/zyntra/backend/internal/ports"
+)
+
+// FactoryFunc cria um canal a partir da configuracao do inbox
+type FactoryFunc func(config map[string]interface{}) (ports.Channel, error)
+
+// Registry implementa ports.ChannelFactory e ports.ChannelManager.
+// Cada tipo de canal registra sua factory; os canais ativos sao indexados por inbox.
+type Registry struct {
+	factories map[ports.ChannelType]FactoryFunc
+	channels  map[string]ports.Channel
+	handler   ports.ChannelEventHandler
+	mu        sync.RWMutex
+}
+
+// NewRegistry cria novo registry
+func NewRegistry() *Registry {
+	return &Registry{
+		factories: make(map[ports.ChannelType]FactoryFunc),
+		channels:  make(map[string]ports.Channel),
+	}
+}
+
+// RegisterFactory registra a factory de um tipo de canal
+func (r *Registry) RegisterFactory(channelType ports.ChannelType, factory FactoryFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[channelType] = factory
+}
+
+// Supports verifica se ha factory para o tipo de canal
+func (r *Registry) Supports(channelType ports.ChannelType) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.factories[channelType]
+	return ok
+}
+
+// SetEventHandler define handler global de eventos
+func (r *Registry) SetEventHandler(handler ports.ChannelEventHandler) {
+	r.handler = handler
+}
+
+// Create cria nova instancia de canal (ports.ChannelFactory)
+func (r *Registry) Create(channelType ports.ChannelType, config map[string]interface{}) (ports.Channel, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[channelType]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("channel type %s not supported", channelType)
+	}
+
+	channel, err := factory(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s channel: %w", channelType, err)
+	}
+	if r.handler != nil {
+		channel.SetEventHandler(r.handler)
+	}
+	return channel, nil
+}
+
+// Get retorna canal de um inbox
+func (r *Registry) Get(inboxID string) (ports.Channel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	channel, exists := r.channels[inboxID]
+	if !exists {
+		return nil, fmt.Errorf("inbox %s not found", inboxID)
+	}
+	return channel, nil
+}
+
+// Register associa um canal a um inbox.
+// Um canal anterior ainda nao conectado (ex.: em backoff de reconexao) e
+// desconectado antes da troca, para que duas sessoes nao disputem o mesmo
+// dispositivo. Se outro Register trocar o canal nesse meio tempo, este falha.
+func (r *Registry) Register(inboxID string, channel ports.Channel) error {
+	r.mu.RLock()
+	existing, exists := r.channels[inboxID]
+	r.mu.RUnlock()
+	if exists && existing.Status() == ports.ChannelStatusConnected {
+		return fmt.Errorf("inbox %s already connected", inboxID)
+	}
+
+	if exists && existing != channel {
+		if err := existing.Disconnect(context.Background()); err != nil {
+			log.Printf("[Channels] Failed to disconnect replaced channel of inbox %s: %v", inboxID, err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if current, ok := r.channels[inboxID]; ok != exists || current != existing {
+		return fmt.Errorf("inbox %s is already being connected", inboxID)
+	}
+	r.channels[inboxID] = channel
+	return nil
+}
+
+// Unregister remove o canal de um inbox
+func (r *Registry) Unregister(inboxID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.channels, inboxID)
+	return nil
+}
+
+// unregisterChannel remove o canal do inbox apenas se ainda for o registrado,
+// preservando um canal mais novo trocado por outro Connect
+func (r *Registry) unregisterChannel(inboxID string, channel ports.Channel) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.channels[inboxID] == channel {
+		delete(r.channels, inboxID)
+	}
+}
+
+// GetAll retorna copia dos canais registrados
+func (r *Registry) GetAll() map[string]ports.Channel {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all := make(map[string]ports.Channel, len(r.channels))
+	for id, channel := range r.channels {
+		all[id] = channel
+	}
+	return all
+}
+
+// Connect cria, registra e conecta o canal de um inbox
+func (r *Registry) Connect(ctx context.Context, inboxID string, channelType ports.ChannelType, config map[string]interface{}) error {
+	channel, err := r.Create(channelType, config)
+	if err != nil {
+		return err
+	}
+
+	// Registrar antes de conectar: eventos de conexao consultam o registry
+	if err := r.Register(inboxID, channel); err != nil {
+		return err
+	}
+
+	if err := channel.Connect(ctx, inboxID); err != nil {
+		r.unregisterChannel(inboxID, channel)
+		return err
+	}
+	return nil
+}
+
+// Disconnect desconecta e remove o canal de um inbox
+func (r *Registry) Disconnect(ctx context.Context, inboxID string) error {
+	channel, err := r.Get(inboxID)
+	if err != nil {
+		return nil
+	}
+
+	if err := channel.Disconnect(ctx); err != nil {
+		return err
+	}
+	r.unregisterChannel(inboxID, channel)
+	return nil
+}
+
+// Status retorna status de um inbox
+func (r *Registry) Status(inboxID string) ports.ChannelStatus {
+	channel, err := r.Get(inboxID)
+	if err != nil {
+		return ports.ChannelStatusDisconnected
+	}
+	return channel.Status()
+}
+
+// GetConnected retorna canal apenas se conectado
+func (r *Registry) GetConnected(inboxID string) (ports.Channel, error) {
+	channel, err := r.Get(inboxID)
+	if err != nil {
+		return nil, err
+	}
+	if channel.Status() != ports.ChannelStatusConnected {
+		return nil, fmt.Errorf("inbox %s not connected", inboxID)
+	}
+	return channel, nil
+}
+
+// Shutdown desconecta todos os canais
+func (r *Registry) Shutdown() {
+	r.mu.Lock()
+	channels := r.channels
+	r.channels = make(map[string]ports.Channel)
+	r.mu.Unlock()
+
+	log.Printf("[Channels] Shutting down %d channels", len(channels))
+
+	ctx := context.Background()
+	for id, channel := range channels {
+		if err := channel.Disconnect(ctx); err != nil {
+			log.Printf("[Channels] Failed to disconnect %s (%s): %v", id, channel.Type(), err)
+		}
+	}
+}
+
+// ConfigString le um valor string da configuracao do canal
+func ConfigString(config map[string]interface{}, key string) string {
+	if v, ok := config[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// ConfigBool le um booleano da configuracao do canal
+func ConfigBool(config map[string]interface{}, key string) bool {
+	v, _ := config[key].(bool)
+	return v
+}
+
+// ConfigInt le um inteiro da configuracao do canal (JSON decodifica numeros como float64)
+func ConfigInt(config map[string]interface{}, key string) int {
+	switch v := config[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// Verify interface implementation
+var (
+	_ ports.ChannelFactory = (*Registry)(nil)
+	_ ports.ChannelManager = (*Registry)(nil)
+)
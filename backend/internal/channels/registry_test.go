@@ -0,0 +1,134 @@
+package channels
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zyntra/backend/internal/ports"
+)
+
+// fakeChannel canal com status fixo que conta as desconexoes
+type fakeChannel struct {
+	ports.Channel
+	status       ports.ChannelStatus
+	disconnects  int
+	onDisconnect func()
+}
+
+func (f *fakeChannel) Status() ports.ChannelStatus { return f.status }
+
+func (f *fakeChannel) Disconnect(ctx context.Context) error {
+	f.disconnects++
+	if f.onDisconnect != nil {
+		f.onDisconnect()
+	}
+	return nil
+}
+
+func TestRegisterDisconnectsReplacedChannel(t *testing.T) {
+	r := NewRegistry()
+	old := &fakeChannel{status: ports.ChannelStatusConnecting}
+	if err := r.Register("inbox", old); err != nil {
+		t.Fatal(err)
+	}
+
+	replacement := &fakeChannel{status: ports.ChannelStatusConnecting}
+	if err := r.Register("inbox", replacement); err != nil {
+		t.Fatal(err)
+	}
+
+	if old.disconnects != 1 {
+		t.Errorf("replaced channel disconnects = %d, want 1", old.disconnects)
+	}
+	if replacement.disconnects != 0 {
+		t.Errorf("new channel disconnects = %d, want 0", replacement.disconnects)
+	}
+	if got, _ := r.Get("inbox"); got != replacement {
+		t.Error("registry does not hold the new channel")
+	}
+}
+
+func TestRegisterKeepsConnectedChannel(t *testing.T) {
+	r := NewRegistry()
+	connected := &fakeChannel{status: ports.ChannelStatusConnected}
+	r.Register("inbox", connected)
+
+	if err := r.Register("inbox", &fakeChannel{}); err == nil {
+		t.Fatal("Register replaced a connected channel")
+	}
+	if connected.disconnects != 0 {
+		t.Errorf("connected channel disconnects = %d, want 0", connected.disconnects)
+	}
+}
+
+func TestRegisterSameChannelTwice(t *testing.T) {
+	r := NewRegistry()
+	channel := &fakeChannel{status: ports.ChannelStatusConnecting}
+	r.Register("inbox", channel)
+	r.Register("inbox", channel)
+
+	if channel.disconnects != 0 {
+		t.Errorf("re-registered channel disconnects = %d, want 0", channel.disconnects)
+	}
+}
+
+func TestRegisterDisconnectsBeforeSwap(t *testing.T) {
+	r := NewRegistry()
+	old := &fakeChannel{status: ports.ChannelStatusConnecting}
+	r.Register("inbox", old)
+
+	old.onDisconnect = func() {
+		if got, _ := r.Get("inbox"); got != old {
+			t.Error("new channel registered before the replaced one was disconnected")
+		}
+	}
+	if err := r.Register("inbox", &fakeChannel{status: ports.ChannelStatusConnecting}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegisterFailsWhenSwappedConcurrently(t *testing.T) {
+	r := NewRegistry()
+	old := &fakeChannel{status: ports.ChannelStatusConnecting}
+	r.Register("inbox", old)
+
+	// Outro Connect troca o canal enquanto o anterior desconecta
+	other := &fakeChannel{status: ports.ChannelStatusConnecting}
+	old.onDisconnect = func() {
+		r.mu.Lock()
+		r.channels["inbox"] = other
+		r.mu.Unlock()
+	}
+	if err := r.Register("inbox", &fakeChannel{status: ports.ChannelStatusConnecting}); err == nil {
+		t.Fatal("Register overwrote a channel swapped in concurrently")
+	}
+	if got, _ := r.Get("inbox"); got != other {
+		t.Error("registry lost the concurrently registered channel")
+	}
+}
+
+func TestUnregisterChannel(t *testing.T) {
+	tests := []struct {
+		name     string
+		remove   func(registered, other *fakeChannel) *fakeChannel
+		wantKept bool
+	}{
+		{name: "registered channel is removed", remove: func(registered, other *fakeChannel) *fakeChannel { return registered }, wantKept: false},
+		{name: "stale channel keeps the newer one", remove: func(registered, other *fakeChannel) *fakeChannel { return other }, wantKept: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			registered := &fakeChannel{status: ports.ChannelStatusConnecting}
+			r.Register("inbox", registered)
+
+			r.unregisterChannel("inbox", tt.remove(registered, &fakeChannel{}))
+
+			_, err := r.Get("inbox")
+			if kept := err == nil; kept != tt.wantKept {
+				t.Fatalf("channel kept = %v, want %v", kept, tt.wantKept)
+			}
+		})
+	}
+}
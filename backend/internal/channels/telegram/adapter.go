@@ -0,0 +1,216 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zyntra/backend/internal/ports"
+	tgpkg "github.com/zyntra/backend/pkg/telegram"
+)
+
+// Adapter implementa ports.Channel usando pkg/telegram
+type Adapter struct {
+	client         *tgpkg.Client
+	inboxID        string
+	webhookBaseURL string
+	handler        ports.ChannelEventHandler
+}
+
+// NewAdapter cria novo adapter
+// webhookBaseURL vazio faz o adapter usar long-polling
+func NewAdapter(client *tgpkg.Client, webhookBaseURL string) *Adapter {
+	adapter := &Adapter{
+		client:         client,
+		webhookBaseURL: webhookBaseURL,
+	}
+
+	// Conectar eventos do cliente ao adapter
+	client.SetEventHandler(adapter)
+
+	return adapter
+}
+
+// Type retorna o tipo do canal
+func (a *Adapter) Type() ports.ChannelType {
+	return ports.ChannelTypeTelegram
+}
+
+// Connect inicia a conexao
+func (a *Adapter) Connect(ctx context.Context, inboxID string) error {
+	a.inboxID = inboxID
+
+	webhookURL := ""
+	if a.webhookBaseURL != "" {
+		webhookURL = a.webhookBaseURL + "/" + inboxID
+	}
+	return a.client.Connect(ctx, webhookURL)
+}
+
+// Disconnect encerra a conexao
+func (a *Adapter) Disconnect(ctx context.Context) error {
+	return a.client.Disconnect(ctx)
+}
+
+// Status retorna o status atual
+func (a *Adapter) Status() ports.ChannelStatus {
+	switch a.client.Status() {
+	case tgpkg.StatusConnected:
+		return ports.ChannelStatusConnected
+	case tgpkg.StatusConnecting:
+		return ports.ChannelStatusConnecting
+	default:
+		return ports.ChannelStatusDisconnected
+	}
+}
+
+// SendText envia mensagem de texto
+func (a *Adapter) SendText(ctx context.Context, to, content string, opts ports.SendOptions) (string, error) {
+	return a.client.SendText(ctx, to, content, replyTo(opts))
+}
+
+// SendMedia envia mensagem com midia
+func (a *Adapter) SendMedia(ctx context.Context, to string, media ports.Media, opts ports.SendOptions) (string, error) {
+	mediaType := tgpkg.MediaType(media.Type)
+	if media.Type == ports.MediaTypeAudio && media.Voice {
+		mediaType = tgpkg.MediaTypeVoice
+	}
+	return a.client.SendMedia(ctx, to, mediaType, media.Data, media.URL, media.FileName, media.Caption, replyTo(opts))
+}
+
+// SendLocation envia localizacao
+func (a *Adapter) SendLocation(ctx context.Context, to string, location ports.Location, opts ports.SendOptions) (string, error) {
+	return a.client.SendLocation(ctx, to, location.Latitude, location.Longitude, location.Name, location.Address, replyTo(opts))
+}
+
+// SendContact envia cartao de contato
+func (a *Adapter) SendContact(ctx context.Context, to string, contact ports.ContactCard, opts ports.SendOptions) (string, error) {
+	if contact.Phone == "" {
+		return "", fmt.Errorf("telegram contacts require a phone number")
+	}
+	return a.client.SendContact(ctx, to, contact.Phone, contact.Name, contact.VCard, replyTo(opts))
+}
+
+// SendReaction reage a uma mensagem
+func (a *Adapter) SendReaction(ctx context.Context, to string, target ports.MessageRef, emoji string) error {
+	return a.client.SetReaction(ctx, to, target.SourceID, emoji)
+}
+
+// EditMessage edita mensagem enviada pelo bot
+func (a *Adapter) EditMessage(ctx context.Context, to string, target ports.MessageRef, content string) error {
+	return a.client.EditText(ctx, to, target.SourceID, content)
+}
+
+// DeleteMessage apaga mensagem do chat
+func (a *Adapter) DeleteMessage(ctx context.Context, to string, target ports.MessageRef) error {
+	return a.client.DeleteMessage(ctx, to, target.SourceID)
+}
+
+// SetEventHandler define o handler de eventos
+func (a *Adapter) SetEventHandler(handler ports.ChannelEventHandler) {
+	a.handler = handler
+}
+
+// GetQRCode Telegram nao usa QR code
+func (a *Adapter) GetQRCode() string {
+	return ""
+}
+
+// GetUsername retorna username do bot
+func (a *Adapter) GetUsername() string {
+	return a.client.Username()
+}
+
+// Mode retorna o modo de recebimento (polling ou webhook)
+func (a *Adapter) Mode() tgpkg.Mode {
+	return a.client.Mode()
+}
+
+// HandleWebhook repassa update recebido via webhook ao cliente
+func (a *Adapter) HandleWebhook(secret string, update tgpkg.Update) error {
+	return a.client.HandleWebhook(secret, update)
+}
+
+// ========== Implementacao de tgpkg.EventHandler ==========
+
+// OnMessage processa mensagem recebida
+func (a *Adapter) OnMessage(event tgpkg.MessageEvent) {
+	if a.handler == nil {
+		return
+	}
+
+	var raw map[string]interface{}
+	if event.FileID != "" {
+		raw = map[string]interface{}{
+			"file_id":   event.FileID,
+			"file_name": event.FileName,
+			"mime_type": event.MimeType,
+		}
+	}
+
+	incoming := ports.IncomingEvent{
+		InboxID:     a.inboxID,
+		SourceID:    event.ID,
+		ContactID:   event.ChatID,
+		ContactName: event.SenderName,
+		Type:        ports.EventTypeMessage,
+		Content:     event.Content,
+		MediaType:   ports.MediaType(event.MediaType),
+		ReplyTo:     event.ReplyToID,
+		Timestamp:   event.Timestamp,
+		RawPayload:  raw,
+	}
+
+	// Telegram mantem o mesmo message_id na edicao
+	if event.Edited {
+		incoming.Type = ports.EventTypeEdit
+		incoming.Target = event.ID
+	}
+
+	a.handler.OnMessage(incoming)
+}
+
+// OnConnected processa evento de conexao
+func (a *Adapter) OnConnected(username string) {
+	if a.handler == nil {
+		return
+	}
+
+	a.handler.OnConnected(a.inboxID, username)
+}
+
+// OnDisconnected processa evento de desconexao
+func (a *Adapter) OnDisconnected() {
+	if a.handler == nil {
+		return
+	}
+
+	a.handler.OnDisconnected(a.inboxID)
+}
+
+// SendChatPresence exibe "digitando..." ao contato.
+// Telegram nao tem estado de pausa: a acao expira sozinha.
+func (a *Adapter) SendChatPresence(ctx context.Context, to string, presence ports.ChatPresence) error {
+	switch presence {
+	case ports.ChatPresenceComposing:
+		return a.client.SendChatAction(ctx, to, "typing")
+	case ports.ChatPresenceRecording:
+		return a.client.SendChatAction(ctx, to, "record_voice")
+	case ports.ChatPresencePaused:
+		return nil
+	}
+	return fmt.Errorf("invalid chat presence: %s", presence)
+}
+
+// replyTo retorna o ID da mensagem respondida
+func replyTo(opts ports.SendOptions) string {
+	if opts.ReplyTo == nil {
+		return ""
+	}
+	return opts.ReplyTo.SourceID
+}
+
+// Verify interface implementation
+var (
+	_ ports.Channel         = (*Adapter)(nil)
+	_ ports.PresenceChannel = (*Adapter)(nil)
+)
@@ -0,0 +1,32 @@
+package telegram
+
+import (
+	"fmt"
+
+	"github.com/zyntra/backend/internal/channels"
+	"github.com/zyntra/backend/internal/ports"
+	tgpkg "github.com/zyntra/backend/pkg/telegram"
+)
+
+// NewFactory retorna factory de adapters Telegram.
+// Config: "bot_token" e "webhook_secret" (obrigatorios).
+func NewFactory(config *tgpkg.Config) channels.FactoryFunc {
+	if config == nil {
+		config = tgpkg.DefaultConfig()
+	}
+	return func(cfg map[string]interface{}) (ports.Channel, error) {
+		token := channels.ConfigString(cfg, "bot_token")
+		if token == "" {
+			return nil, fmt.Errorf("bot_token is required")
+		}
+
+		secret := channels.ConfigString(cfg, "webhook_secret")
+		if secret == "" {
+			return nil, fmt.Errorf("webhook_secret is required")
+		}
+
+		client := tgpkg.NewClient(token, config)
+		client.SetWebhookSecret(secret)
+		return NewAdapter(client, config.WebhookBaseURL), nil
+	}
+}
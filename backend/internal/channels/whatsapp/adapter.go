@@ -2,12 +2,20 @@ package whatsapp
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
-	wapkg "github.com/zyntra/backend/pkg/whatsapp"
 	"github.com/zyntra/backend/internal/ports"
+	wapkg "github.com/zyntra/backend/pkg/whatsapp"
 )
 
+// ErrAlreadyPaired dispositivo ja possui sessao pareada
+var ErrAlreadyPaired = wapkg.ErrAlreadyPaired
+
+// ErrInvalidPhone telefone invalido para pareamento por codigo
+var ErrInvalidPhone = wapkg.ErrInvalidPhone
+
 // Adapter implementa ports.Channel usando pkg/whatsapp
 type Adapter struct {
 	client  *wapkg.Client
@@ -60,22 +68,167 @@ func (a *Adapter) Status() ports.ChannelStatus {
 }
 
 // SendText envia mensagem de texto
-func (a *Adapter) SendText(ctx context.Context, to, content string) (string, error) {
-	return a.client.SendText(ctx, to, content)
+func (a *Adapter) SendText(ctx context.Context, to, content string, opts ports.SendOptions) (string, error) {
+	return a.client.SendText(ctx, to, content, toQuote(opts.ReplyTo))
 }
 
 // SendMedia envia mensagem com midia
-func (a *Adapter) SendMedia(ctx context.Context, to string, media ports.Media) (string, error) {
+func (a *Adapter) SendMedia(ctx context.Context, to string, media ports.Media, opts ports.SendOptions) (string, error) {
+	quote := toQuote(opts.ReplyTo)
 	switch media.Type {
 	case ports.MediaTypeImage:
-		return a.client.SendImage(ctx, to, media.Data, media.Caption, media.MimeType)
+		return a.client.SendImage(ctx, to, media.Data, media.Caption, media.MimeType, quote)
 	case ports.MediaTypeDocument:
-		return a.client.SendDocument(ctx, to, media.Data, media.FileName, media.Caption, media.MimeType)
+		return a.client.SendDocument(ctx, to, media.Data, media.FileName, media.Caption, media.MimeType, quote)
+	case ports.MediaTypeVideo:
+		return a.client.SendVideo(ctx, to, media.Data, media.Caption, media.MimeType, quote)
+	case ports.MediaTypeAudio:
+		return a.client.SendAudio(ctx, to, media.Data, media.MimeType, media.Voice, quote)
+	case ports.MediaTypeSticker:
+		return a.client.SendSticker(ctx, to, media.Data, media.MimeType, quote)
 	default:
 		return "", fmt.Errorf("unsupported media type: %s", media.Type)
 	}
 }
 
+// SendLocation envia localizacao
+func (a *Adapter) SendLocation(ctx context.Context, to string, location ports.Location, opts ports.SendOptions) (string, error) {
+	return a.client.SendLocation(ctx, to, location.Latitude, location.Longitude, location.Name, location.Address, toQuote(opts.ReplyTo))
+}
+
+// SendContact envia cartao de contato (vCard)
+func (a *Adapter) SendContact(ctx context.Context, to string, contact ports.ContactCard, opts ports.SendOptions) (string, error) {
+	vcard := contact.VCard
+	if vcard == "" {
+		vcard = wapkg.BuildVCard(contact.Name, contact.Phone, contact.Email, contact.Organization)
+	}
+	return a.client.SendContact(ctx, to, contact.Name, vcard, toQuote(opts.ReplyTo))
+}
+
+// SendReaction reage a uma mensagem
+func (a *Adapter) SendReaction(ctx context.Context, to string, target ports.MessageRef, emoji string) error {
+	sender := ""
+	if !target.FromMe {
+		sender = target.Sender
+	}
+	return a.client.SendReaction(ctx, to, target.SourceID, sender, emoji)
+}
+
+// EditMessage edita mensagem enviada
+func (a *Adapter) EditMessage(ctx context.Context, to string, target ports.MessageRef, content string) error {
+	return a.client.EditMessage(ctx, to, target.SourceID, content)
+}
+
+// DeleteMessage apaga mensagem para todos
+func (a *Adapter) DeleteMessage(ctx context.Context, to string, target ports.MessageRef) error {
+	return a.client.RevokeMessage(ctx, to, target.SourceID)
+}
+
+// GetGroupInfo busca metadados e participantes do grupo
+func (a *Adapter) GetGroupInfo(ctx context.Context, groupID string) (*ports.GroupInfo, error) {
+	info, err := a.client.GetGroupInfo(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &ports.GroupInfo{
+		ID:          info.JID,
+		Subject:     info.Name,
+		Description: info.Topic,
+		OwnerID:     info.OwnerJID,
+		Announce:    info.Announce,
+	}
+	for _, p := range info.Participants {
+		group.Participants = append(group.Participants, ports.GroupParticipant{
+			ID:           p.JID,
+			Name:         p.Name,
+			Phone:        p.Phone,
+			IsAdmin:      p.IsAdmin,
+			IsSuperAdmin: p.IsSuperAdmin,
+		})
+	}
+	return group, nil
+}
+
+// MarkRead envia confirmacao de leitura agrupando as mensagens por remetente
+func (a *Adapter) MarkRead(ctx context.Context, to string, messages []ports.MessageRef) error {
+	bySender := make(map[string][]string)
+	var senders []string
+	for _, msg := range messages {
+		if msg.FromMe || msg.SourceID == "" {
+			continue
+		}
+		if _, ok := bySender[msg.Sender]; !ok {
+			senders = append(senders, msg.Sender)
+		}
+		bySender[msg.Sender] = append(bySender[msg.Sender], msg.SourceID)
+	}
+
+	for _, sender := range senders {
+		// Em chats individuais o remetente e o proprio chat
+		senderJID := sender
+		if sender == to {
+			senderJID = ""
+		}
+		if err := a.client.MarkRead(ctx, to, bySender[sender], senderJID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendChatPresence exibe "digitando..." ou "gravando audio..." ao contato
+func (a *Adapter) SendChatPresence(ctx context.Context, to string, presence ports.ChatPresence) error {
+	return a.client.SendChatPresence(ctx, to, string(presence))
+}
+
+// ResolveContact verifica o numero no WhatsApp e resolve JID e LID
+func (a *Adapter) ResolveContact(ctx context.Context, phone string) (*ports.ResolvedContact, error) {
+	info, err := a.client.CheckNumber(ctx, phone)
+	if errors.Is(err, wapkg.ErrNotOnWhatsApp) {
+		return nil, fmt.Errorf("%w: %v", ports.ErrContactNotFound, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	contact := &ports.ResolvedContact{
+		ID:    info.JID,
+		Phone: info.Phone,
+		Name:  info.BusinessName,
+	}
+	if info.LID != "" {
+		contact.AltIDs = []string{info.LID}
+	}
+	return contact, nil
+}
+
+// GetContactProfile busca foto e perfil comercial do contato
+func (a *Adapter) GetContactProfile(ctx context.Context, contactID, knownPictureID string) (*ports.ContactProfile, error) {
+	p, err := a.client.GetContactProfile(ctx, contactID, knownPictureID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &ports.ContactProfile{
+		PictureID:      p.PictureID,
+		PictureURL:     p.PictureURL,
+		PictureChanged: p.PictureChanged,
+		About:          p.About,
+	}
+	if b := p.Business; b != nil {
+		profile.Business = &ports.BusinessProfile{
+			Name:       b.Name,
+			Address:    b.Address,
+			Email:      b.Email,
+			Categories: b.Categories,
+			Website:    b.Website,
+			Timezone:   b.Timezone,
+		}
+	}
+	return profile, nil
+}
+
 // SetEventHandler define o handler de eventos
 func (a *Adapter) SetEventHandler(handler ports.ChannelEventHandler) {
 	a.handler = handler
@@ -86,6 +239,31 @@ func (a *Adapter) GetQRCode() string {
 	return a.client.GetQRCode()
 }
 
+// PairPhone gera codigo de pareamento pelo numero de telefone
+func (a *Adapter) PairPhone(ctx context.Context, phone string) (string, error) {
+	return a.client.PairPhone(ctx, phone)
+}
+
+// Health retorna o estado da conexao mantido pelo supervisor
+func (a *Adapter) Health() ports.ChannelHealth {
+	h := a.client.Health()
+	health := ports.ChannelHealth{
+		Status:    a.Status(),
+		Attempt:   h.Attempt,
+		LastError: h.LastError,
+	}
+	if !h.NextRetryAt.IsZero() {
+		health.NextRetryAt = &h.NextRetryAt
+	}
+	if !h.ConnectedSince.IsZero() {
+		health.ConnectedSince = &h.ConnectedSince
+	}
+	if !h.LastDisconnect.IsZero() {
+		health.LastDisconnect = &h.LastDisconnect
+	}
+	return health
+}
+
 // GetJID retorna JID do dispositivo
 func (a *Adapter) GetJID() string {
 	return a.client.GetJID()
@@ -114,17 +292,33 @@ func (a *Adapter) OnMessage(event wapkg.MessageEvent) {
 		return
 	}
 
-	a.handler.OnMessage(ports.IncomingEvent{
-		InboxID:     a.inboxID,
-		SourceID:    event.ID,
-		ContactID:   event.ChatJID,
-		ContactName: event.SenderName,
-		IsFromMe:    event.IsFromMe,
-		Type:        ports.EventTypeMessage,
-		Content:     event.Content,
-		MediaType:   convertMediaType(event.MediaType),
-		Timestamp:   event.Timestamp,
-	})
+	a.handler.OnMessage(a.incomingEvent(event))
+}
+
+// OnHistorySync processa lote de historico recebido apos o pareamento
+func (a *Adapter) OnHistorySync(event wapkg.HistorySyncEvent) {
+	if a.handler == nil {
+		return
+	}
+
+	batch := ports.HistoryBatch{
+		InboxID:       a.inboxID,
+		SyncType:      event.SyncType,
+		Chunk:         event.ChunkOrder,
+		Progress:      event.Progress,
+		Conversations: event.Conversations,
+		Events:        make([]ports.IncomingEvent, 0, len(event.Messages)),
+	}
+	for _, msg := range event.Messages {
+		incoming := a.incomingEvent(msg)
+		// Enviada pelo proprio numero: o nome e o do dispositivo, nao o do contato
+		if msg.IsFromMe && !msg.IsGroup {
+			incoming.ContactName = ""
+		}
+		batch.Events = append(batch.Events, incoming)
+	}
+
+	a.handler.OnHistorySync(batch)
 }
 
 // OnReceipt processa recibo de entrega/leitura
@@ -170,17 +364,168 @@ func (a *Adapter) OnDisconnected() {
 	a.handler.OnDisconnected(a.inboxID)
 }
 
-// OnLoggedOut processa evento de logout
+// OnReconnecting processa tentativa de reconexao agendada
+func (a *Adapter) OnReconnecting(event wapkg.ReconnectEvent) {
+	if a.handler == nil {
+		return
+	}
+
+	a.handler.OnReconnecting(a.inboxID, event.Attempt, event.Delay, event.Error)
+}
+
+// OnLoggedOut processa evento de logout (sessao removida no aparelho)
 func (a *Adapter) OnLoggedOut() {
 	if a.handler == nil {
 		return
 	}
 
-	a.handler.OnDisconnected(a.inboxID)
+	a.handler.OnLoggedOut(a.inboxID)
+}
+
+// OnGroupUpdate processa alteracao de grupo
+func (a *Adapter) OnGroupUpdate(event wapkg.GroupEvent) {
+	if a.handler == nil {
+		return
+	}
+
+	a.handler.OnMessage(ports.IncomingEvent{
+		InboxID:   a.inboxID,
+		ContactID: event.JID,
+		IsGroup:   true,
+		Type:      ports.EventTypeGroupUpdate,
+		Timestamp: event.Timestamp,
+	})
+}
+
+// OnChatPresence processa contato digitando ou gravando audio
+func (a *Adapter) OnChatPresence(event wapkg.ChatPresenceEvent) {
+	if a.handler == nil {
+		return
+	}
+
+	presence := ports.PresenceEvent{
+		InboxID:   a.inboxID,
+		Type:      ports.EventTypeTyping,
+		ContactID: event.ChatJID,
+		State:     event.State,
+		Timestamp: time.Now(),
+	}
+	if event.IsGroup {
+		presence.SenderID = event.SenderJID
+	}
+
+	a.handler.OnPresence(presence)
+}
+
+// OnPresence processa contato online/offline
+func (a *Adapter) OnPresence(event wapkg.PresenceEvent) {
+	if a.handler == nil {
+		return
+	}
+
+	presence := ports.PresenceEvent{
+		InboxID:   a.inboxID,
+		Type:      ports.EventTypePresence,
+		ContactID: event.JID,
+		State:     "offline",
+		Timestamp: time.Now(),
+	}
+	if event.Online {
+		presence.State = "online"
+	}
+	if !event.LastSeen.IsZero() {
+		lastSeen := event.LastSeen
+		presence.LastSeen = &lastSeen
+	}
+
+	a.handler.OnPresence(presence)
 }
 
 // ========== Helpers ==========
 
+// incomingEvent converte mensagem do WhatsApp em evento do canal
+func (a *Adapter) incomingEvent(event wapkg.MessageEvent) ports.IncomingEvent {
+	incoming := ports.IncomingEvent{
+		InboxID:      a.inboxID,
+		SourceID:     event.ID,
+		ContactID:    event.ChatJID,
+		ContactName:  event.SenderName,
+		ContactPhone: wapkg.PhoneFromJID(event.ChatJID),
+		IsFromMe:     event.IsFromMe,
+		Type:         ports.EventTypeMessage,
+		Content:      event.Content,
+		MediaType:    convertMediaType(event.MediaType),
+		ReplyTo:      event.QuotedID,
+		Target:       event.TargetID,
+		Reaction:     event.Reaction,
+		Timestamp:    event.Timestamp,
+	}
+
+	// Em grupos o contato da conversa e o grupo; o remetente e o participante
+	if event.IsGroup {
+		incoming.IsGroup = true
+		incoming.ContactName = ""
+		incoming.SenderID = event.SenderJID
+		incoming.SenderName = event.SenderName
+		incoming.SenderPhone = wapkg.PhoneFromJID(event.SenderJID)
+	}
+
+	switch event.Type {
+	case wapkg.MessageTypeReaction:
+		incoming.Type = ports.EventTypeReaction
+	case wapkg.MessageTypeEdit:
+		incoming.Type = ports.EventTypeEdit
+	case wapkg.MessageTypeRevoke:
+		incoming.Type = ports.EventTypeRevoke
+	}
+
+	incoming.ContentType, incoming.Attributes = structuredContent(event)
+
+	if len(event.MediaData) > 0 {
+		incoming.Media = &ports.Media{
+			Type:     incoming.MediaType,
+			Data:     event.MediaData,
+			MimeType: event.MimeType,
+			FileName: event.FileName,
+		}
+	}
+
+	return incoming
+}
+
+// structuredContent converte dados estruturados da mensagem em content_type e atributos
+func structuredContent(event wapkg.MessageEvent) (string, map[string]interface{}) {
+	switch event.Type {
+	case wapkg.MessageTypeLocation, wapkg.MessageTypeLiveLocation:
+		return "location", map[string]interface{}{"location": event.Location}
+	case wapkg.MessageTypeContact:
+		attrs := map[string]interface{}{"contacts": event.Contacts}
+		if len(event.Contacts) > 0 {
+			attrs["contact"] = event.Contacts[0]
+		}
+		return "contact", attrs
+	case wapkg.MessageTypePoll:
+		return "poll", map[string]interface{}{"poll": event.Poll}
+	case wapkg.MessageTypeInteractiveReply:
+		return "", map[string]interface{}{"interactive_reply": event.Reply}
+	case wapkg.MessageTypeUnsupported:
+		return "unsupported", map[string]interface{}{"unsupported_type": event.UnsupportedType}
+	}
+	return "", nil
+}
+
+// toQuote converte referencia de mensagem em citacao do WhatsApp
+func toQuote(ref *ports.MessageRef) *wapkg.Quote {
+	if ref == nil || ref.SourceID == "" {
+		return nil
+	}
+	quote := &wapkg.Quote{ID: ref.SourceID, Content: ref.Content}
+	if !ref.FromMe {
+		quote.SenderJID = ref.Sender
+	}
+	return quote
+}
+
 func convertMediaType(mt wapkg.MediaType) ports.MediaType {
 	switch mt {
 	case wapkg.MediaTypeImage:
@@ -197,3 +542,14 @@ func convertMediaType(mt wapkg.MediaType) ports.MediaType {
 		return ""
 	}
 }
+
+// Verify interface implementation
+var (
+	_ ports.Channel            = (*Adapter)(nil)
+	_ ports.GroupChannel       = (*Adapter)(nil)
+	_ ports.ReadReceiptChannel = (*Adapter)(nil)
+	_ ports.PresenceChannel    = (*Adapter)(nil)
+	_ ports.HealthChannel      = (*Adapter)(nil)
+	_ ports.ProfileChannel     = (*Adapter)(nil)
+	_ ports.ContactResolver    = (*Adapter)(nil)
+)
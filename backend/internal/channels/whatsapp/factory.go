@@ -0,0 +1,34 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zyntra/backend/internal/channels"
+	"github.com/zyntra/backend/internal/ports"
+	wapkg "github.com/zyntra/backend/pkg/whatsapp"
+)
+
+// NewFactory retorna factory de adapters WhatsApp.
+// Config: "jid" (opcional) identifica a sessao salva no store;
+// "history_import", "history_days" e "history_messages" controlam a importacao de historico;
+// "announce_online" marca o dispositivo como online (ver wapkg.Client.SetAnnounceOnline).
+// Midia recebida maior que mediaMaxSize nao e baixada.
+func NewFactory(store *wapkg.Store, mediaMaxSize int64) channels.FactoryFunc {
+	return func(config map[string]interface{}) (ports.Channel, error) {
+		device, err := store.GetDevice(context.Background(), channels.ConfigString(config, "jid"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get device: %w", err)
+		}
+
+		client := wapkg.NewClient(device)
+		client.SetMediaMaxSize(mediaMaxSize)
+		client.SetAnnounceOnline(channels.ConfigBool(config, "announce_online"))
+		client.SetHistoryConfig(wapkg.HistoryConfig{
+			Enabled:         channels.ConfigBool(config, "history_import"),
+			Days:            channels.ConfigInt(config, "history_days"),
+			MessagesPerChat: channels.ConfigInt(config, "history_messages"),
+		})
+		return NewAdapter(client, ""), nil
+	}
+}
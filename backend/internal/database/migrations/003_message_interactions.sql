@@ -0,0 +1,23 @@
+-- ============================================
+-- RESPOSTAS, EDICOES E REMOCOES DE MENSAGENS
+-- ============================================
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS in_reply_to_id UUID REFERENCES messages(id) ON DELETE SET NULL;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_messages_in_reply_to ON messages(in_reply_to_id);
+
+-- ============================================
+-- REACTIONS (uma por remetente e mensagem)
+-- ============================================
+CREATE TABLE IF NOT EXISTS message_reactions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    message_id UUID NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    sender_type VARCHAR(20) NOT NULL,
+    sender_id VARCHAR(255) NOT NULL DEFAULT '', -- user/contact id; vazio = aparelho do proprio numero
+    emoji VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (message_id, sender_type, sender_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_reactions_message ON message_reactions(message_id);
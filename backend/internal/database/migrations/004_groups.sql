@@ -0,0 +1,39 @@
+-- ============================================
+-- GROUPS (grupos de WhatsApp)
+-- O grupo e representado nas conversas por um contato proprio
+-- ============================================
+CREATE TABLE IF NOT EXISTS chat_groups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    inbox_id UUID NOT NULL REFERENCES inboxes(id) ON DELETE CASCADE,
+    contact_id UUID NOT NULL REFERENCES contacts(id) ON DELETE CASCADE,
+    source_id VARCHAR(255) NOT NULL, -- JID do grupo
+    subject VARCHAR(255),
+    description TEXT,
+    owner_source_id VARCHAR(255),
+    announce BOOLEAN DEFAULT FALSE, -- Apenas admins enviam mensagens
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(inbox_id, source_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_groups_contact ON chat_groups(contact_id);
+
+CREATE TRIGGER update_chat_groups_updated_at
+    BEFORE UPDATE ON chat_groups
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- GROUP PARTICIPANTS
+-- ============================================
+CREATE TABLE IF NOT EXISTS chat_group_participants (
+    group_id UUID NOT NULL REFERENCES chat_groups(id) ON DELETE CASCADE,
+    source_id VARCHAR(255) NOT NULL, -- JID do participante
+    contact_id UUID REFERENCES contacts(id) ON DELETE SET NULL,
+    name VARCHAR(255),
+    phone_number VARCHAR(50),
+    is_admin BOOLEAN DEFAULT FALSE,
+    is_super_admin BOOLEAN DEFAULT FALSE,
+    PRIMARY KEY (group_id, source_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_group_participants_contact ON chat_group_participants(contact_id);
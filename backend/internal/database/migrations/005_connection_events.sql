@@ -0,0 +1,13 @@
+-- ============================================
+-- CONNECTION EVENTS (historico de conexao por inbox)
+-- ============================================
+CREATE TABLE IF NOT EXISTS inbox_connection_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    inbox_id UUID NOT NULL REFERENCES inboxes(id) ON DELETE CASCADE,
+    event VARCHAR(30) NOT NULL,
+    reason TEXT,
+    attempt INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_inbox_connection_events_inbox ON inbox_connection_events(inbox_id, created_at DESC);
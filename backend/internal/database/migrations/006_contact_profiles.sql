@@ -0,0 +1,16 @@
+-- ============================================
+-- CONTACT PROFILES (foto e perfil comercial sincronizados do canal)
+-- A foto fica no storage; contacts.avatar_key guarda a chave
+-- ============================================
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS avatar_key TEXT;
+
+CREATE TABLE IF NOT EXISTS contact_profiles (
+    contact_id UUID PRIMARY KEY REFERENCES contacts(id) ON DELETE CASCADE,
+    inbox_id UUID REFERENCES inboxes(id) ON DELETE SET NULL, -- inbox de onde o perfil foi lido
+    picture_id VARCHAR(255), -- ID da foto no canal, muda quando a foto e trocada
+    about TEXT,
+    business_profile JSONB,
+    synced_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_contact_profiles_synced ON contact_profiles(synced_at);
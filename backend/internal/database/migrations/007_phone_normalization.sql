@@ -0,0 +1,100 @@
+-- ============================================
+-- TELEFONES EM E.164
+-- Regiao por inbox para numeros sem codigo do pais, normalizacao dos
+-- telefones gravados e fusao dos contatos duplicados pelo formato antigo.
+-- Numeros nacionais existentes sao lidos como BR (regiao padrao);
+-- valores que nao parecem telefones ficam como estao.
+-- ============================================
+ALTER TABLE inboxes ADD COLUMN IF NOT EXISTS phone_region VARCHAR(2);
+
+-- Mesmas regras de pkg/phone.Normalize para a regiao BR
+CREATE OR REPLACE FUNCTION pg_temp.normalize_phone(raw TEXT) RETURNS TEXT AS $$
+DECLARE
+    digits TEXT := regexp_replace(raw, '\D', '', 'g');
+    intl BOOLEAN := btrim(raw) LIKE '+%';
+BEGIN
+    IF digits = '' THEN
+        RETURN raw;
+    END IF;
+    IF NOT intl AND digits LIKE '00%' THEN
+        digits := substr(digits, 3);
+        intl := TRUE;
+    END IF;
+
+    IF NOT intl THEN
+        IF length(digits) BETWEEN 10 AND 11 THEN
+            digits := '55' || digits;
+        ELSIF digits LIKE '0%' AND length(digits) BETWEEN 11 AND 12 THEN
+            digits := '55' || substr(digits, 2); -- prefixo nacional 0
+        ELSIF digits LIKE '0%' AND length(digits) BETWEEN 13 AND 14 THEN
+            digits := '55' || substr(digits, 4); -- 0 + operadora
+        ELSIF NOT (digits LIKE '55%' AND length(digits) BETWEEN 12 AND 13) THEN
+            RETURN raw;
+        END IF;
+    END IF;
+
+    IF length(digits) NOT BETWEEN 8 AND 15 OR digits LIKE '0%' THEN
+        RETURN raw;
+    END IF;
+
+    -- Nono digito dos celulares brasileiros
+    IF digits ~ '^55[1-9][1-9][6-9][0-9]{7}$' THEN
+        digits := substr(digits, 1, 4) || '9' || substr(digits, 5);
+    END IF;
+    RETURN '+' || digits;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+UPDATE contacts SET phone_number = pg_temp.normalize_phone(phone_number)
+WHERE phone_number IS NOT NULL AND phone_number <> '';
+
+UPDATE chat_group_participants SET phone_number = pg_temp.normalize_phone(phone_number)
+WHERE phone_number IS NOT NULL AND phone_number <> '';
+
+-- Contatos com o mesmo telefone: mantem o mais antigo
+CREATE TEMP TABLE contact_merges ON COMMIT DROP AS
+SELECT id AS duplicate_id,
+       first_value(id) OVER (PARTITION BY phone_number ORDER BY created_at, id) AS keep_id
+FROM contacts
+WHERE phone_number LIKE '+%';
+
+DELETE FROM contact_merges WHERE duplicate_id = keep_id;
+
+UPDATE contact_inboxes t SET contact_id = m.keep_id
+FROM contact_merges m WHERE t.contact_id = m.duplicate_id;
+
+UPDATE conversations t SET contact_id = m.keep_id
+FROM contact_merges m WHERE t.contact_id = m.duplicate_id;
+
+UPDATE chat_groups t SET contact_id = m.keep_id
+FROM contact_merges m WHERE t.contact_id = m.duplicate_id;
+
+UPDATE chat_group_participants t SET contact_id = m.keep_id
+FROM contact_merges m WHERE t.contact_id = m.duplicate_id;
+
+INSERT INTO contact_tags (contact_id, tag_id)
+SELECT m.keep_id, t.tag_id
+FROM contact_tags t JOIN contact_merges m ON t.contact_id = m.duplicate_id
+ON CONFLICT DO NOTHING;
+
+UPDATE messages t SET sender_id = m.keep_id
+FROM contact_merges m
+WHERE t.sender_type = 'contact' AND t.sender_id = m.duplicate_id;
+
+-- Uma reacao por remetente e mensagem: descarta as que colidiriam apos a fusao
+DELETE FROM message_reactions r
+USING contact_merges m
+WHERE r.sender_type = 'contact' AND r.sender_id = m.duplicate_id::text
+  AND EXISTS (
+      SELECT 1 FROM message_reactions o
+      WHERE o.message_id = r.message_id AND o.sender_type = 'contact' AND o.id <> r.id
+        AND (o.sender_id = m.keep_id::text
+             OR o.sender_id IN (SELECT d.duplicate_id::text FROM contact_merges d
+                                WHERE d.keep_id = m.keep_id AND d.duplicate_id::text < r.sender_id))
+  );
+
+UPDATE message_reactions t SET sender_id = m.keep_id::text
+FROM contact_merges m
+WHERE t.sender_type = 'contact' AND t.sender_id = m.duplicate_id::text;
+
+DELETE FROM contacts c USING contact_merges m WHERE c.id = m.duplicate_id;
@@ -0,0 +1,11 @@
+-- ============================================
+-- SEGREDO DE ASSINATURA DO WEBHOOK DO CANAL API
+-- A api_key autentica mensagens recebidas; o webhook_secret assina as
+-- enviadas ao webhook_url. Canais existentes recebem um segredo novo:
+-- reutilizar a api_key daria a qualquer receptor do webhook a credencial de entrada.
+-- ============================================
+ALTER TABLE channel_api ADD COLUMN IF NOT EXISTS webhook_secret VARCHAR(255);
+
+UPDATE channel_api
+SET webhook_secret = 'whsec_' || replace(uuid_generate_v4()::text || uuid_generate_v4()::text, '-', '')
+WHERE webhook_secret IS NULL;
@@ -0,0 +1,14 @@
+-- ============================================
+-- TICKETS DE STREAM
+-- Credencial de uso unico e curta duracao para abrir WebSocket/SSE,
+-- que nao aceitam header Authorization no navegador. Apenas o hash e gravado.
+-- ============================================
+CREATE TABLE IF NOT EXISTS stream_tickets (
+    ticket_hash VARCHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    role VARCHAR(50) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_stream_tickets_expires_at ON stream_tickets(expires_at);
@@ -0,0 +1,6 @@
+-- ============================================
+-- MENSAGENS DE SAIDA PENDENTES
+-- A fila de envio marca como failed as mensagens pendentes ha muito tempo
+-- (canal desconectado ou nunca conectado); o indice parcial mantem a varredura barata.
+-- ============================================
+CREATE INDEX IF NOT EXISTS idx_messages_pending ON messages(created_at) WHERE status = 'pending';
@@ -0,0 +1,16 @@
+-- ============================================
+-- SOURCE_ID UNICO POR INBOX
+-- Reentregas simultaneas do canal (retry do cliente da API, do Telegram...)
+-- passavam pela verificacao antes do INSERT e duplicavam a mensagem.
+-- Duplicatas antigas perdem o source_id; a mensagem mais antiga fica com ele.
+-- ============================================
+UPDATE messages m
+SET source_id = NULL
+FROM messages d
+WHERE m.inbox_id = d.inbox_id
+  AND m.source_id = d.source_id
+  AND m.source_id <> ''
+  AND (m.created_at, m.id) > (d.created_at, d.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_inbox_source_unique
+    ON messages(inbox_id, source_id) WHERE source_id <> '';
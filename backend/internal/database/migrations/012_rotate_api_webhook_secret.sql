@@ -0,0 +1,8 @@
+-- ============================================
+-- ROTACAO DO WEBHOOK_SECRET IGUAL A API_KEY
+-- Versoes anteriores da migracao 008 copiavam a api_key para o webhook_secret.
+-- Cada canal nessa situacao recebe um segredo novo (visivel na config do inbox).
+-- ============================================
+UPDATE channel_api
+SET webhook_secret = 'whsec_' || replace(uuid_generate_v4()::text || uuid_generate_v4()::text, '-', '')
+WHERE webhook_secret = api_key;
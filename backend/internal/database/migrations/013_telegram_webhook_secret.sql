@@ -0,0 +1,10 @@
+-- ============================================
+-- SECRET DO WEBHOOK DO TELEGRAM
+-- Persistido para que reinicios e todas as replicas registrem o mesmo
+-- secret_token no setWebhook e aceitem os updates recebidos.
+-- ============================================
+ALTER TABLE channel_telegram ADD COLUMN IF NOT EXISTS webhook_secret VARCHAR(255);
+
+UPDATE channel_telegram
+SET webhook_secret = replace(uuid_generate_v4()::text || uuid_generate_v4()::text, '-', '')
+WHERE webhook_secret IS NULL;
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"time"
+
+	"github.com/zyntra/backend/internal/ports"
 )
 
 // Contact contato global unificado
@@ -11,6 +13,7 @@ type Contact struct {
 	Email            string                 `json:"email,omitempty" db:"email"`
 	PhoneNumber      string                 `json:"phone_number,omitempty" db:"phone_number"`
 	AvatarURL        string                 `json:"avatar_url,omitempty" db:"avatar_url"`
+	AvatarKey        string                 `json:"-" db:"avatar_key"` // Foto sincronizada do canal (chave no storage)
 	CustomAttributes map[string]interface{} `json:"custom_attributes,omitempty" db:"custom_attributes"`
 	CreatedAt        time.Time              `json:"created_at" db:"created_at"`
 	UpdatedAt        time.Time              `json:"updated_at" db:"updated_at"`
@@ -29,7 +32,26 @@ type ContactInbox struct {
 // ContactWithInboxes contato com suas identidades por canal
 type ContactWithInboxes struct {
 	Contact
-	ContactInboxes []ContactInbox `json:"contact_inboxes,omitempty"`
+	ContactInboxes []ContactInbox  `json:"contact_inboxes,omitempty"`
+	Profile        *ContactProfile `json:"profile,omitempty"`
+}
+
+// ContactProfile perfil do contato sincronizado do canal
+type ContactProfile struct {
+	ContactID string                 `json:"contact_id" db:"contact_id"`
+	InboxID   string                 `json:"inbox_id,omitempty" db:"inbox_id"`
+	PictureID string                 `json:"-" db:"picture_id"`
+	About     string                 `json:"about,omitempty" db:"about"`
+	Business  *ports.BusinessProfile `json:"business,omitempty" db:"business_profile"`
+	SyncedAt  time.Time              `json:"synced_at" db:"synced_at"`
+}
+
+// ProfileSyncTarget identidade de contato cujo perfil deve ser sincronizado
+type ProfileSyncTarget struct {
+	ContactID string
+	InboxID   string
+	SourceID  string
+	PictureID string
 }
 
 // Tag tag para contatos
@@ -62,3 +84,20 @@ type UpdateContactRequest struct {
 	AvatarURL        *string                `json:"avatar_url,omitempty"`
 	CustomAttributes map[string]interface{} `json:"custom_attributes,omitempty"`
 }
+
+// TypingIndicator contato digitando em uma conversa (evento em tempo real, nao persistido)
+type TypingIndicator struct {
+	ConversationID string    `json:"conversation_id"`
+	ContactID      string    `json:"contact_id"`
+	State          string    `json:"state"` // composing, recording, paused
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// ContactPresence contato online/offline (evento em tempo real, nao persistido)
+type ContactPresence struct {
+	ContactID      string     `json:"contact_id"`
+	ConversationID string     `json:"conversation_id,omitempty"`
+	Online         bool       `json:"online"`
+	LastSeen       *time.Time `json:"last_seen,omitempty"`
+	Timestamp      time.Time  `json:"timestamp"`
+}
@@ -48,6 +48,7 @@ type ConversationWithDetails struct {
 	Inbox        *Inbox   `json:"inbox,omitempty"`
 	Assignee     *User    `json:"assignee,omitempty"`
 	LastMessage  *Message `json:"last_message,omitempty"`
+	Group        *Group   `json:"group,omitempty"`
 	MessagesCount int     `json:"messages_count,omitempty"`
 }
 
@@ -81,6 +82,14 @@ type ConversationFilter struct {
 	Offset     int                 `json:"offset,omitempty"`
 }
 
+// StartConversationRequest request para iniciar conversa com um telefone
+type StartConversationRequest struct {
+	InboxID     string `json:"inbox_id"`
+	PhoneNumber string `json:"phone_number"`
+	Name        string `json:"name,omitempty"`
+	Content     string `json:"content,omitempty"` // Primeira mensagem (opcional)
+}
+
 // UpdateConversationRequest request para atualizar conversa
 type UpdateConversationRequest struct {
 	Status     *ConversationStatus   `json:"status,omitempty"`
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+)
+
+// Group grupo de um canal (ex: grupo de WhatsApp).
+// Nas conversas o grupo e representado pelo contato ContactID.
+type Group struct {
+	ID            string              `json:"id" db:"id"`
+	InboxID       string              `json:"inbox_id" db:"inbox_id"`
+	ContactID     string              `json:"contact_id" db:"contact_id"`
+	SourceID      string              `json:"source_id" db:"source_id"` // JID do grupo
+	Subject       string              `json:"subject" db:"subject"`
+	Description   string              `json:"description,omitempty" db:"description"`
+	OwnerSourceID string              `json:"owner_source_id,omitempty" db:"owner_source_id"`
+	Announce      bool                `json:"announce" db:"announce"` // Apenas admins enviam mensagens
+	Participants  []*GroupParticipant `json:"participants,omitempty" db:"-"`
+	CreatedAt     time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at" db:"updated_at"`
+}
+
+// GroupParticipant participante de um grupo
+type GroupParticipant struct {
+	GroupID      string  `json:"-" db:"group_id"`
+	SourceID     string  `json:"source_id" db:"source_id"`             // JID do participante
+	ContactID    *string `json:"contact_id,omitempty" db:"contact_id"` // Contato conhecido no inbox
+	Name         string  `json:"name,omitempty" db:"name"`
+	PhoneNumber  string  `json:"phone_number,omitempty" db:"phone_number"`
+	IsAdmin      bool    `json:"is_admin" db:"is_admin"`
+	IsSuperAdmin bool    `json:"is_super_admin" db:"is_super_admin"`
+}
@@ -16,6 +16,7 @@ type Inbox struct {
 	QRCode          string              `json:"qrcode,omitempty" db:"qrcode"`
 	GreetingMessage string              `json:"greeting_message,omitempty" db:"greeting_message"`
 	AutoAssignment  bool                `json:"auto_assignment" db:"auto_assignment"`
+	PhoneRegion     string              `json:"phone_region,omitempty" db:"phone_region"` // Regiao dos telefones sem codigo do pais (ex: BR)
 	CreatedAt       time.Time           `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time           `json:"updated_at" db:"updated_at"`
 }
@@ -33,20 +34,22 @@ type ChannelWhatsApp struct {
 
 // ChannelTelegram configuracao do canal Telegram
 type ChannelTelegram struct {
-	ID          string    `json:"id" db:"id"`
-	BotToken    string    `json:"bot_token" db:"bot_token"`
-	BotUsername string    `json:"bot_username" db:"bot_username"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID            string    `json:"id" db:"id"`
+	BotToken      string    `json:"bot_token" db:"bot_token"`
+	BotUsername   string    `json:"bot_username" db:"bot_username"`
+	WebhookSecret string    `json:"-" db:"webhook_secret"` // secret_token enviado pelo Telegram no webhook
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // ChannelAPI configuracao do canal API
 type ChannelAPI struct {
-	ID         string    `json:"id" db:"id"`
-	WebhookURL string    `json:"webhook_url" db:"webhook_url"`
-	APIKey     string    `json:"api_key" db:"api_key"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	ID            string    `json:"id" db:"id"`
+	WebhookURL    string    `json:"webhook_url" db:"webhook_url"`
+	APIKey        string    `json:"api_key" db:"api_key"`
+	WebhookSecret string    `json:"webhook_secret" db:"webhook_secret"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // InboxMember associacao inbox-usuario
@@ -68,5 +71,45 @@ type CreateInboxRequest struct {
 	ChannelType     ports.ChannelType `json:"channel_type" validate:"required"`
 	GreetingMessage string            `json:"greeting_message,omitempty"`
 	AutoAssignment  bool              `json:"auto_assignment"`
+	PhoneRegion     string            `json:"phone_region,omitempty"`
 	ChannelConfig   map[string]string `json:"channel_config,omitempty"`
 }
+
+// HistoryImportProgress andamento da importacao de historico de um inbox
+type HistoryImportProgress struct {
+	InboxID       string `json:"inbox_id"`
+	SyncType      string `json:"sync_type"`
+	Chunk         uint32 `json:"chunk"`
+	Progress      uint32 `json:"progress"` // Percentual informado pelo canal (0-100)
+	Conversations int    `json:"conversations"`
+	Imported      int    `json:"imported"`
+	Skipped       int    `json:"skipped"` // Ja existentes (mesmo source_id) ou com falha
+	Done          bool   `json:"done"`    // Lote processado
+}
+
+// ConnectionEventType tipo de evento de conexao
+type ConnectionEventType string
+
+const (
+	ConnectionEventConnected    ConnectionEventType = "connected"
+	ConnectionEventDisconnected ConnectionEventType = "disconnected"
+	ConnectionEventReconnecting ConnectionEventType = "reconnecting"
+	ConnectionEventLoggedOut    ConnectionEventType = "logged_out"
+)
+
+// ConnectionEvent registro do historico de conexao de um inbox
+type ConnectionEvent struct {
+	ID        string              `json:"id" db:"id"`
+	InboxID   string              `json:"inbox_id" db:"inbox_id"`
+	Event     ConnectionEventType `json:"event" db:"event"`
+	Reason    string              `json:"reason,omitempty" db:"reason"`
+	Attempt   int                 `json:"attempt" db:"attempt"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+}
+
+// InboxHealth saude da conexao de um inbox com historico recente
+type InboxHealth struct {
+	InboxID string `json:"inbox_id"`
+	ports.ChannelHealth
+	History []*ConnectionEvent `json:"history"`
+}
@@ -27,6 +27,11 @@ const (
 	ContentTypeDocument ContentType = "document"
 	ContentTypeSticker  ContentType = "sticker"
 	ContentTypeLocation ContentType = "location"
+	ContentTypeContact  ContentType = "contact"
+	ContentTypePoll     ContentType = "poll"
+
+	// ContentTypeUnsupported mensagem de tipo nao suportado (registrada para nao se perder)
+	ContentTypeUnsupported ContentType = "unsupported"
 )
 
 // Message mensagem
@@ -42,14 +47,29 @@ type Message struct {
 	SourceID          string                 `json:"source_id,omitempty" db:"source_id"`
 	Status            ports.MessageStatus    `json:"status" db:"status"`
 	Private           bool                   `json:"private" db:"private"`
+	InReplyToID       *string                `json:"in_reply_to_id,omitempty" db:"in_reply_to_id"`
+	EditedAt          *time.Time             `json:"edited_at,omitempty" db:"edited_at"`
+	DeletedAt         *time.Time             `json:"deleted_at,omitempty" db:"deleted_at"`
 	CreatedAt         time.Time              `json:"created_at" db:"created_at"`
+	Attachments       []*Attachment          `json:"attachments,omitempty" db:"-"`
+	Reactions         []*Reaction            `json:"reactions,omitempty" db:"-"`
+}
+
+// Reaction reacao (emoji) a uma mensagem
+type Reaction struct {
+	ID             string     `json:"id" db:"id"`
+	MessageID      string     `json:"message_id" db:"message_id"`
+	ConversationID string     `json:"conversation_id,omitempty" db:"-"`
+	SenderType     SenderType `json:"sender_type" db:"sender_type"`
+	SenderID       string     `json:"sender_id,omitempty" db:"sender_id"`
+	Emoji          string     `json:"emoji" db:"emoji"` // Vazio em eventos de remocao
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
 }
 
 // MessageWithSender mensagem com dados do remetente
 type MessageWithSender struct {
 	Message
-	Sender      interface{}  `json:"sender,omitempty"`
-	Attachments []Attachment `json:"attachments,omitempty"`
+	Sender interface{} `json:"sender,omitempty"`
 }
 
 // Attachment anexo de mensagem
@@ -62,6 +82,9 @@ type Attachment struct {
 	FileSize  int64     `json:"file_size,omitempty" db:"file_size"`
 	MimeType  string    `json:"mime_type,omitempty" db:"mime_type"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// DownloadURL URL assinada e expiravel para download (nao persistida)
+	DownloadURL string `json:"download_url,omitempty" db:"-"`
 }
 
 // SendMessageRequest request para enviar mensagem
@@ -69,7 +92,12 @@ type SendMessageRequest struct {
 	Content     string                 `json:"content" validate:"required"`
 	ContentType ContentType            `json:"content_type,omitempty"`
 	Private     bool                   `json:"private,omitempty"`
+	InReplyToID string                 `json:"in_reply_to_id,omitempty"` // Mensagem citada (resposta)
+	Mentions    []string               `json:"mentions,omitempty"`       // IDs de usuarios mencionados (apenas notas privadas)
 	Attachments []AttachmentRequest    `json:"attachments,omitempty"`
+	Voice       bool                   `json:"voice,omitempty"` // Audios enviados como mensagem de voz
+	Location    *ports.Location        `json:"location,omitempty"`
+	Contact     *ports.ContactCard     `json:"contact,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -84,12 +112,12 @@ type AttachmentRequest struct {
 
 // MessageFilter filtros para busca de mensagens
 type MessageFilter struct {
-	ConversationID string    `json:"conversation_id"`
-	Before         *time.Time `json:"before,omitempty"`
-	After          *time.Time `json:"after,omitempty"`
+	ConversationID string      `json:"conversation_id"`
+	Before         *time.Time  `json:"before,omitempty"`
+	After          *time.Time  `json:"after,omitempty"`
 	SenderType     *SenderType `json:"sender_type,omitempty"`
-	Limit          int        `json:"limit,omitempty"`
-	Offset         int        `json:"offset,omitempty"`
+	Limit          int         `json:"limit,omitempty"`
+	Offset         int         `json:"offset,omitempty"`
 }
 
 // IncomingMessageEvent evento de mensagem recebida (do canal)
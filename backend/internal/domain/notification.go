@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+)
+
+// NotificationType tipo de notificacao
+type NotificationType string
+
+const (
+	// NotificationTypeMention usuario mencionado em nota privada
+	NotificationTypeMention NotificationType = "mention"
+)
+
+// Notification notificacao de um usuario
+type Notification struct {
+	ID             string           `json:"id" db:"id"`
+	UserID         string           `json:"user_id" db:"user_id"`
+	Type           NotificationType `json:"type" db:"type"`
+	ConversationID string           `json:"conversation_id,omitempty" db:"conversation_id"`
+	MessageID      string           `json:"message_id,omitempty" db:"message_id"`
+	ActorID        *string          `json:"actor_id,omitempty" db:"actor_id"`
+	ReadAt         *time.Time       `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/zyntra/backend/internal/api"
+	"github.com/zyntra/backend/internal/channels/apichannel"
+	"github.com/zyntra/backend/internal/ports"
+)
+
+// APIChannelHandler recebe mensagens de fontes externas do canal API
+type APIChannelHandler struct {
+	channels ports.ChannelManager
+}
+
+// NewAPIChannelHandler cria novo handler
+func NewAPIChannelHandler(channels ports.ChannelManager) *APIChannelHandler {
+	return &APIChannelHandler{channels: channels}
+}
+
+// Inbound processa mensagem enviada por uma fonte externa
+// Autenticado pela api_key do canal no header X-API-Key
+func (h *APIChannelHandler) Inbound(c echo.Context) error {
+	inboxID := c.Param("inbox_id")
+	apiKey := c.Request().Header.Get("X-API-Key")
+	if apiKey == "" {
+		return api.Unauthorized(c, "API key required")
+	}
+
+	var req apichannel.InboundMessage
+	if err := c.Bind(&req); err != nil {
+		return api.BadRequest(c, "Invalid request body")
+	}
+
+	if req.ContactID == "" {
+		return api.ValidationError(c, "contact_id is required")
+	}
+	if req.Content == "" {
+		return api.ValidationError(c, "content is required")
+	}
+
+	channel, err := h.channels.Get(inboxID)
+	if err != nil {
+		log.Printf("[APIChannel] Inbound for inbox %s rejected: %v", inboxID, err)
+		return api.NotFound(c, "Inbox not found")
+	}
+	adapter, ok := channel.(*apichannel.Adapter)
+	if !ok {
+		return api.NotFound(c, "Inbox not found")
+	}
+
+	if err := adapter.HandleInbound(apiKey, req); err != nil {
+		if errors.Is(err, apichannel.ErrInvalidAPIKey) {
+			return api.Error(c, http.StatusUnauthorized, api.ErrCodeInvalidAPIKey, "Invalid API key")
+		}
+		if errors.Is(err, apichannel.ErrInboundFailed) {
+			log.Printf("[APIChannel] Inbound for inbox %s failed: %v", inboxID, err)
+			return api.InternalError(c, "Failed to process message")
+		}
+		log.Printf("[APIChannel] Inbound for inbox %s rejected: %v", inboxID, err)
+		return api.BadRequest(c, err.Error())
+	}
+
+	return c.JSON(http.StatusAccepted, api.APIResponse{Success: true})
+}
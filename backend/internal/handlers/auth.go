@@ -14,15 +14,17 @@ import (
 
 // AuthHandler handles authentication endpoints
 type AuthHandler struct {
-	db         *sql.DB
-	jwtService *auth.JWTService
+	db            *sql.DB
+	jwtService    *auth.JWTService
+	ticketService *auth.StreamTicketService
 }
 
 // NewAuthHandler creates a new auth handler
 func NewAuthHandler(db *sql.DB, jwtService *auth.JWTService) *AuthHandler {
 	return &AuthHandler{
-		db:         db,
-		jwtService: jwtService,
+		db:            db,
+		jwtService:    jwtService,
+		ticketService: auth.NewStreamTicketService(db),
 	}
 }
 
@@ -189,6 +191,24 @@ func (h *AuthHandler) RefreshToken(c echo.Context) error {
 	})
 }
 
+// StreamTicket issues a single-use ticket for opening /ws or /events with ?ticket=
+func (h *AuthHandler) StreamTicket(c echo.Context) error {
+	user := middleware.GetUser(c)
+	if user == nil {
+		return api.Unauthorized(c, "Not authenticated")
+	}
+	if middleware.GetAuthType(c) != middleware.AuthTypeJWT {
+		return api.Forbidden(c, "Stream tickets require a user session")
+	}
+
+	ticket, err := h.ticketService.Issue(c.Request().Context(), user.UserID, user.Email, user.Role)
+	if err != nil {
+		return api.InternalError(c, "Failed to issue stream ticket")
+	}
+
+	return api.Created(c, ticket)
+}
+
 // GetProfile returns the current user's profile
 func (h *AuthHandler) GetProfile(c echo.Context) error {
 	user := middleware.GetUser(c)
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
 	"github.com/zyntra/backend/internal/api"
 	"github.com/zyntra/backend/internal/domain"
 	"github.com/zyntra/backend/internal/services"
+	"github.com/zyntra/backend/pkg/phone"
 )
 
 // ContactHandler handler de contatos
@@ -80,6 +82,9 @@ func (h *ContactHandler) Create(c echo.Context) error {
 		PhoneNumber:      req.PhoneNumber,
 		CustomAttributes: req.CustomAttributes,
 	})
+	if errors.Is(err, phone.ErrInvalid) {
+		return api.ValidationError(c, "Invalid phone number")
+	}
 	if err != nil {
 		return api.InternalError(c, err.Error())
 	}
@@ -111,6 +116,9 @@ func (h *ContactHandler) Update(c echo.Context) error {
 		AvatarURL:        req.AvatarURL,
 		CustomAttributes: req.CustomAttributes,
 	})
+	if errors.Is(err, phone.ErrInvalid) {
+		return api.ValidationError(c, "Invalid phone number")
+	}
 	if err != nil {
 		return api.InternalError(c, err.Error())
 	}
@@ -111,15 +111,6 @@ func (h *ConversationHandler) Delete(c echo.Context) error {
 	return api.NoContent(c)
 }
 
-// MarkAsRead marca conversa como lida
-func (h *ConversationHandler) MarkAsRead(c echo.Context) error {
-	id := c.Param("id")
-	if err := h.service.MarkAsRead(c.Request().Context(), id); err != nil {
-		return api.InternalError(c, err.Error())
-	}
-	return api.Success(c, map[string]string{"message": "Marked as read"})
-}
-
 // AssignRequest request para atribuir conversa
 type AssignRequest struct {
 	AssigneeID string `json:"assignee_id"`
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/zyntra/backend/internal/api"
+	"github.com/zyntra/backend/internal/middleware"
+	"github.com/zyntra/backend/internal/services"
+	wspkg "github.com/zyntra/backend/pkg/websocket"
+)
+
+// sseHeartbeat intervalo dos comentarios que mantem a conexao aberta em proxies
+const sseHeartbeat = 25 * time.Second
+
+// EventsHandler stream de eventos em tempo real via Server-Sent Events
+// (alternativa ao WebSocket para redes que o bloqueiam)
+type EventsHandler struct {
+	realtimeAccess
+}
+
+// NewEventsHandler cria novo handler
+func NewEventsHandler(hub *wspkg.Hub, inboxService *services.InboxService, conversationService *services.ConversationService) *EventsHandler {
+	return &EventsHandler{
+		realtimeAccess: realtimeAccess{
+			hub:                 hub,
+			inboxService:        inboxService,
+			conversationService: conversationService,
+		},
+	}
+}
+
+// Stream envia os eventos do hub como SSE (event: tipo, id: ID do evento, data: Event).
+// Assinaturas opcionais por ?inbox_id= e ?conversation_id= (repetidos ou separados por virgula);
+// a retomada usa o header Last-Event-ID (ou ?last_event_id=).
+func (h *EventsHandler) Stream(c echo.Context) error {
+	user := middleware.GetUser(c)
+	if user == nil {
+		return api.Unauthorized(c, "Authentication required")
+	}
+
+	ctx := c.Request().Context()
+	client := wspkg.NewStreamClient(user.UserID)
+	if err := h.loadAccess(ctx, client, user); err != nil {
+		log.Printf("[SSE] Failed to load inboxes of user %s: %v", user.UserID, err)
+		return api.InternalError(c, "Failed to load inbox access")
+	}
+	if denied := h.subscribe(ctx, client, queryList(c, "inbox_id"), queryList(c, "conversation_id")); len(denied) > 0 {
+		return api.Forbidden(c, "No access to: "+strings.Join(denied, ", "))
+	}
+
+	lastEventID := c.Request().Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.QueryParam("last_event_id")
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no") // nginx: sem buffer de resposta
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	if id, err := strconv.ParseUint(lastEventID, 10, 64); err == nil && id > 0 {
+		h.hub.Resume(client, id)
+	} else {
+		h.hub.Register(client)
+	}
+	defer h.hub.Unregister(client)
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-client.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case msg := <-client.Messages():
+			if err := writeSSE(res, msg); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+// writeSSE escreve uma mensagem do hub no formato SSE
+func writeSSE(w *echo.Response, msg interface{}) error {
+	m, ok := msg.(wspkg.Message)
+	if !ok {
+		return nil
+	}
+
+	var id uint64
+	switch payload := m.Payload.(type) {
+	case wspkg.Event:
+		id = payload.ID
+	case wspkg.StreamPosition:
+		id = payload.LastEventID
+	}
+
+	data, err := json.Marshal(m.Payload)
+	if err != nil {
+		log.Printf("[SSE] Failed to encode %s event: %v", m.Type, err)
+		return nil
+	}
+	if id > 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", m.Type, data)
+	return err
+}
+
+// queryList le um parametro repetido ou separado por virgulas
+func queryList(c echo.Context, name string) []string {
+	var values []string
+	for _, param := range c.QueryParams()[name] {
+		for _, v := range strings.Split(param, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
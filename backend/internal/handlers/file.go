@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/zyntra/backend/internal/api"
+	"github.com/zyntra/backend/pkg/storage"
+)
+
+// FileHandler serve arquivos do storage local por URL assinada
+// (no backend S3 as URLs assinadas apontam direto para o bucket)
+type FileHandler struct {
+	backend *storage.LocalBackend
+}
+
+// NewFileHandler cria novo handler
+func NewFileHandler(backend *storage.LocalBackend) *FileHandler {
+	return &FileHandler{backend: backend}
+}
+
+// Serve valida a assinatura e retorna o arquivo
+func (h *FileHandler) Serve(c echo.Context) error {
+	key := c.Param("*")
+	name := c.QueryParam("name")
+
+	if err := h.backend.VerifySignature(key, name, c.QueryParam("expires"), c.QueryParam("signature")); err != nil {
+		return api.Error(c, http.StatusForbidden, api.ErrCodeForbidden, "Invalid or expired link")
+	}
+
+	data, err := h.backend.Get(c.Request().Context(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return api.NotFound(c, "File not found")
+	}
+	if err != nil {
+		return api.InternalError(c, err.Error())
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	return serveFile(c, data, contentType, name, false)
+}
+
+// inlineImageTypes imagens que o navegador exibe sem executar scripts (SVG fica de fora)
+var inlineImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+	"image/bmp":  true,
+}
+
+// isInlineSafe verifica se o tipo pode ser exibido inline na origem da API
+func isInlineSafe(mimeType string) bool {
+	return inlineImageTypes[mimeType] ||
+		strings.HasPrefix(mimeType, "audio/") ||
+		strings.HasPrefix(mimeType, "video/")
+}
+
+// serveFile responde com um arquivo armazenado. O tipo vem do remetente, entao
+// apenas imagens, audio e video sao servidos com seu tipo (e inline, se pedido);
+// o resto vai como application/octet-stream para download. nosniff e CSP sandbox
+// impedem que o conteudo rode como pagina na origem da API.
+func serveFile(c echo.Context, data []byte, mimeType, fileName string, inline bool) error {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil || !isInlineSafe(mediaType) {
+		mimeType = echo.MIMEOctetStream
+		inline = false
+	}
+
+	disposition := "attachment"
+	if inline {
+		disposition = "inline"
+	}
+
+	if fileName != "" {
+		disposition = mime.FormatMediaType(disposition, map[string]string{"filename": fileName})
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, disposition)
+	header.Set(echo.HeaderXContentTypeOptions, "nosniff")
+	header.Set(echo.HeaderContentSecurityPolicy, "sandbox")
+	header.Set("Cache-Control", "private, max-age=3600")
+
+	return c.Blob(http.StatusOK, mimeType, data)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestServeFile(t *testing.T) {
+	tests := []struct {
+		name            string
+		mimeType        string
+		inline          bool
+		wantType        string
+		wantDisposition string
+	}{
+		{"image inline", "image/png", true, "image/png", "inline"},
+		{"video inline", "video/mp4", true, "video/mp4", "inline"},
+		{"audio with params", "audio/ogg; codecs=opus", true, "audio/ogg; codecs=opus", "inline"},
+		{"image download", "image/jpeg", false, "image/jpeg", "attachment"},
+		{"html inline", "text/html", true, echo.MIMEOctetStream, "attachment"},
+		{"svg inline", "image/svg+xml", true, echo.MIMEOctetStream, "attachment"},
+		{"pdf inline", "application/pdf", true, echo.MIMEOctetStream, "attachment"},
+		{"empty type", "", true, echo.MIMEOctetStream, "attachment"},
+	}
+
+	e := echo.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+			if err := serveFile(c, []byte("data"), tt.mimeType, "file.bin", tt.inline); err != nil {
+				t.Fatal(err)
+			}
+
+			header := rec.Header()
+			if got := header.Get(echo.HeaderContentType); got != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+			if got := header.Get(echo.HeaderContentDisposition); !strings.HasPrefix(got, tt.wantDisposition+";") {
+				t.Errorf("Content-Disposition = %q, want %s", got, tt.wantDisposition)
+			}
+			if got := header.Get(echo.HeaderXContentTypeOptions); got != "nosniff" {
+				t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+			}
+			if got := header.Get(echo.HeaderContentSecurityPolicy); got != "sandbox" {
+				t.Errorf("Content-Security-Policy = %q, want sandbox", got)
+			}
+		})
+	}
+}
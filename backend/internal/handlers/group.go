@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/zyntra/backend/internal/api"
+	"github.com/zyntra/backend/internal/services"
+)
+
+// GroupHandler handler de grupos
+type GroupHandler struct {
+	service *services.GroupService
+}
+
+// NewGroupHandler cria novo handler
+func NewGroupHandler(service *services.GroupService) *GroupHandler {
+	return &GroupHandler{service: service}
+}
+
+// Get retorna metadados e participantes do grupo da conversa.
+// refresh=true busca os dados atualizados no canal.
+func (h *GroupHandler) Get(c echo.Context) error {
+	refresh, _ := strconv.ParseBool(c.QueryParam("refresh"))
+
+	group, err := h.service.GetByConversation(c.Request().Context(), c.Param("id"), refresh)
+	if errors.Is(err, services.ErrNotGroup) {
+		return api.NotFound(c, err.Error())
+	}
+	if err != nil {
+		return api.InternalError(c, err.Error())
+	}
+
+	return api.Success(c, group)
+}
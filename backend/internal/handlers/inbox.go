@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/labstack/echo/v4"
 	"github.com/zyntra/backend/internal/api"
+	"github.com/zyntra/backend/internal/channels/whatsapp"
 	"github.com/zyntra/backend/internal/domain"
 	"github.com/zyntra/backend/internal/ports"
 	"github.com/zyntra/backend/internal/services"
+	"github.com/zyntra/backend/pkg/phone"
 )
 
 // InboxHandler handler de inboxes
@@ -30,7 +35,7 @@ func (h *InboxHandler) List(c echo.Context) error {
 // Get retorna um inbox por ID
 func (h *InboxHandler) Get(c echo.Context) error {
 	id := c.Param("id")
-	inbox, err := h.service.GetByID(c.Request().Context(), id)
+	inbox, err := h.service.GetWithChannel(c.Request().Context(), id)
 	if err != nil {
 		return api.NotFound(c, err.Error())
 	}
@@ -39,10 +44,12 @@ func (h *InboxHandler) Get(c echo.Context) error {
 
 // CreateInboxRequest request para criar inbox
 type CreateInboxRequest struct {
-	Name            string `json:"name" validate:"required"`
-	ChannelType     string `json:"channel_type" validate:"required"`
-	GreetingMessage string `json:"greeting_message,omitempty"`
-	AutoAssignment  bool   `json:"auto_assignment"`
+	Name            string            `json:"name" validate:"required"`
+	ChannelType     string            `json:"channel_type" validate:"required"`
+	GreetingMessage string            `json:"greeting_message,omitempty"`
+	AutoAssignment  bool              `json:"auto_assignment"`
+	PhoneRegion     string            `json:"phone_region,omitempty"`
+	ChannelConfig   map[string]string `json:"channel_config,omitempty"`
 }
 
 // Create cria um novo inbox
@@ -64,11 +71,21 @@ func (h *InboxHandler) Create(c echo.Context) error {
 		ChannelType:     ports.ChannelType(req.ChannelType),
 		GreetingMessage: req.GreetingMessage,
 		AutoAssignment:  req.AutoAssignment,
+		PhoneRegion:     req.PhoneRegion,
+		ChannelConfig:   req.ChannelConfig,
 	})
 	if err != nil {
+		if errors.Is(err, phone.ErrUnknownRegion) {
+			return api.ValidationError(c, err.Error())
+		}
 		return api.InternalError(c, err.Error())
 	}
 
+	// Retornar com o canal para expor credenciais geradas (ex: api_key)
+	if withChannel, err := h.service.GetWithChannel(c.Request().Context(), inbox.ID); err == nil {
+		return api.Created(c, withChannel)
+	}
+
 	return api.Created(c, inbox)
 }
 
@@ -77,6 +94,7 @@ type UpdateInboxRequest struct {
 	Name            *string `json:"name,omitempty"`
 	GreetingMessage *string `json:"greeting_message,omitempty"`
 	AutoAssignment  *bool   `json:"auto_assignment,omitempty"`
+	PhoneRegion     *string `json:"phone_region,omitempty"`
 }
 
 // Update atualiza um inbox
@@ -101,6 +119,16 @@ func (h *InboxHandler) Update(c echo.Context) error {
 	if req.AutoAssignment != nil {
 		inbox.AutoAssignment = *req.AutoAssignment
 	}
+	if req.PhoneRegion != nil {
+		inbox.PhoneRegion = *req.PhoneRegion
+	}
+
+	if err := h.service.Update(c.Request().Context(), inbox); err != nil {
+		if errors.Is(err, phone.ErrUnknownRegion) {
+			return api.ValidationError(c, err.Error())
+		}
+		return api.InternalError(c, err.Error())
+	}
 
 	return api.Success(c, inbox)
 }
@@ -123,7 +151,7 @@ func (h *InboxHandler) Connect(c echo.Context) error {
 	return api.Success(c, map[string]interface{}{
 		"id":      id,
 		"status":  "connecting",
-		"message": "Connection initiated. QR code will be available via /qrcode endpoint or WebSocket.",
+		"message": "Connection initiated. QR code will be available via /qrcode endpoint or WebSocket (or use /pair-code).",
 	})
 }
 
@@ -151,3 +179,49 @@ func (h *InboxHandler) GetQRCode(c echo.Context) error {
 		"status":  status,
 	})
 }
+
+// GetHealth retorna a saude da conexao do inbox com o historico recente
+func (h *InboxHandler) GetHealth(c echo.Context) error {
+	health, err := h.service.GetHealth(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return api.NotFound(c, err.Error())
+	}
+	return api.Success(c, health)
+}
+
+// PairCodeRequest request para pareamento por codigo
+type PairCodeRequest struct {
+	Phone string `json:"phone" validate:"required"`
+}
+
+// PairCode gera codigo de pareamento pelo telefone (alternativa ao QR code)
+func (h *InboxHandler) PairCode(c echo.Context) error {
+	id := c.Param("id")
+
+	var req PairCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return api.BadRequest(c, "Invalid request body")
+	}
+	if req.Phone == "" {
+		return api.ValidationError(c, "phone is required")
+	}
+
+	code, err := h.service.PairPhone(c.Request().Context(), id, req.Phone)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPairingNotSupported):
+			return api.BadRequest(c, err.Error())
+		case errors.Is(err, whatsapp.ErrAlreadyPaired):
+			return api.Conflict(c, err.Error())
+		case errors.Is(err, whatsapp.ErrInvalidPhone):
+			return api.ValidationError(c, fmt.Sprintf("Invalid phone number: %s", req.Phone))
+		}
+		return api.InternalError(c, err.Error())
+	}
+
+	return api.Success(c, map[string]interface{}{
+		"id":        id,
+		"pair_code": code,
+		"status":    h.service.GetStatus(id),
+	})
+}
@@ -1,13 +1,20 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/zyntra/backend/internal/api"
 	"github.com/zyntra/backend/internal/domain"
 	"github.com/zyntra/backend/internal/middleware"
+	"github.com/zyntra/backend/internal/ports"
 	"github.com/zyntra/backend/internal/services"
+	"github.com/zyntra/backend/pkg/phone"
+	"github.com/zyntra/backend/pkg/storage"
 )
 
 // MessageHandler handler de mensagens
@@ -40,22 +47,37 @@ func (h *MessageHandler) List(c echo.Context) error {
 
 // SendMessageRequest request para enviar mensagem
 type SendMessageRequest struct {
-	Content     string `json:"content" validate:"required"`
-	ContentType string `json:"content_type,omitempty"`
-	Private     bool   `json:"private,omitempty"`
+	Content     string                     `json:"content"`
+	ContentType string                     `json:"content_type,omitempty"`
+	Private     bool                       `json:"private,omitempty"`
+	Mentions    []string                   `json:"mentions,omitempty"`
+	Attachments []domain.AttachmentRequest `json:"attachments,omitempty"`
+	Voice       bool                       `json:"voice,omitempty"`
+	Location    *ports.Location            `json:"location,omitempty"`
+	Contact     *ports.ContactCard         `json:"contact,omitempty"`
+	InReplyToID string                     `json:"in_reply_to_id,omitempty"`
 }
 
 // Send envia uma mensagem
+// Aceita JSON ou multipart/form-data (campos content, content_type, private, mentions, voice, in_reply_to_id e arquivos em attachments).
+// Localizacao (location) e cartao de contato (contact) sao aceitos apenas em JSON.
+// voice=true envia anexos de audio como mensagem de voz.
+// Mensagens private sao notas internas: nunca sao enviadas ao contato.
+// in_reply_to_id cita outra mensagem da conversa.
 func (h *MessageHandler) Send(c echo.Context) error {
 	conversationID := c.Param("id")
 
 	var req SendMessageRequest
-	if err := c.Bind(&req); err != nil {
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		if err := bindMultipartMessage(c, &req, h.service.MaxAttachmentSize()); err != nil {
+			return api.BadRequest(c, err.Error())
+		}
+	} else if err := c.Bind(&req); err != nil {
 		return api.BadRequest(c, "Invalid request body")
 	}
 
-	if req.Content == "" {
-		return api.ValidationError(c, "Content is required")
+	if req.Content == "" && len(req.Attachments) == 0 && req.Location == nil && req.Contact == nil {
+		return api.ValidationError(c, "Content, attachments, location or contact required")
 	}
 
 	// Obter usuario autenticado
@@ -74,10 +96,248 @@ func (h *MessageHandler) Send(c echo.Context) error {
 		Content:     req.Content,
 		ContentType: contentType,
 		Private:     req.Private,
+		Mentions:    req.Mentions,
+		Attachments: req.Attachments,
+		Voice:       req.Voice,
+		Location:    req.Location,
+		Contact:     req.Contact,
+		InReplyToID: req.InReplyToID,
 	}, senderID)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidAttachment) || errors.Is(err, services.ErrInvalidMessage) {
+			return api.ValidationError(c, err.Error())
+		}
 		return api.InternalError(c, err.Error())
 	}
 
 	return api.Created(c, msg)
 }
+
+// EditMessageRequest request para editar mensagem
+type EditMessageRequest struct {
+	Content string `json:"content"`
+}
+
+// Edit altera o texto de uma mensagem enviada pelo agente
+func (h *MessageHandler) Edit(c echo.Context) error {
+	user := middleware.GetUser(c)
+	if user == nil {
+		return api.Unauthorized(c, "User not authenticated")
+	}
+
+	var req EditMessageRequest
+	if err := c.Bind(&req); err != nil {
+		return api.BadRequest(c, "Invalid request body")
+	}
+
+	msg, err := h.service.EditMessage(c.Request().Context(), c.Param("id"), user.UserID, req.Content)
+	if err != nil {
+		return messageError(c, err)
+	}
+
+	return api.Success(c, msg)
+}
+
+// Delete apaga mensagem para todos (autor ou admin)
+func (h *MessageHandler) Delete(c echo.Context) error {
+	user := middleware.GetUser(c)
+	if user == nil {
+		return api.Unauthorized(c, "User not authenticated")
+	}
+
+	admin := user.Role == string(domain.UserRoleAdmin)
+	msg, err := h.service.DeleteMessage(c.Request().Context(), c.Param("id"), user.UserID, admin)
+	if err != nil {
+		return messageError(c, err)
+	}
+
+	return api.Success(c, msg)
+}
+
+// ReactionRequest request para reagir a mensagem
+type ReactionRequest struct {
+	Emoji string `json:"emoji"`
+}
+
+// React define a reacao do agente na mensagem
+func (h *MessageHandler) React(c echo.Context) error {
+	user := middleware.GetUser(c)
+	if user == nil {
+		return api.Unauthorized(c, "User not authenticated")
+	}
+
+	var req ReactionRequest
+	if err := c.Bind(&req); err != nil {
+		return api.BadRequest(c, "Invalid request body")
+	}
+	if strings.TrimSpace(req.Emoji) == "" {
+		return api.ValidationError(c, "Emoji is required")
+	}
+
+	reaction, err := h.service.ReactToMessage(c.Request().Context(), c.Param("id"), user.UserID, req.Emoji)
+	if err != nil {
+		return messageError(c, err)
+	}
+
+	return api.Success(c, reaction)
+}
+
+// RemoveReaction remove a reacao do agente na mensagem
+func (h *MessageHandler) RemoveReaction(c echo.Context) error {
+	user := middleware.GetUser(c)
+	if user == nil {
+		return api.Unauthorized(c, "User not authenticated")
+	}
+
+	if _, err := h.service.ReactToMessage(c.Request().Context(), c.Param("id"), user.UserID, ""); err != nil {
+		return messageError(c, err)
+	}
+
+	return api.NoContent(c)
+}
+
+// MarkAsRead marca conversa como lida e confirma a leitura ao contato
+func (h *MessageHandler) MarkAsRead(c echo.Context) error {
+	if err := h.service.MarkAsRead(c.Request().Context(), c.Param("id")); err != nil {
+		return api.InternalError(c, err.Error())
+	}
+	return api.Success(c, map[string]string{"message": "Marked as read"})
+}
+
+// PresenceRequest request para exibir digitacao ao contato
+type PresenceRequest struct {
+	Presence ports.ChatPresence `json:"presence"` // composing, recording ou paused
+}
+
+// SendPresence exibe ao contato que o agente esta digitando
+func (h *MessageHandler) SendPresence(c echo.Context) error {
+	var req PresenceRequest
+	if err := c.Bind(&req); err != nil {
+		return api.BadRequest(c, "Invalid request body")
+	}
+	if req.Presence == "" {
+		req.Presence = ports.ChatPresenceComposing
+	}
+
+	if err := h.service.SendChatPresence(c.Request().Context(), c.Param("id"), req.Presence); err != nil {
+		if errors.Is(err, services.ErrPresenceNotSupported) {
+			return api.BadRequest(c, err.Error())
+		}
+		return messageError(c, err)
+	}
+	return api.NoContent(c)
+}
+
+// StartConversationRequest request para iniciar conversa com um telefone
+type StartConversationRequest struct {
+	InboxID     string `json:"inbox_id"`
+	PhoneNumber string `json:"phone_number"`
+	Name        string `json:"name,omitempty"`
+	Content     string `json:"content,omitempty"` // Primeira mensagem (opcional)
+}
+
+// StartConversation cria contato e conversa a partir de um telefone verificado no canal
+func (h *MessageHandler) StartConversation(c echo.Context) error {
+	var req StartConversationRequest
+	if err := c.Bind(&req); err != nil {
+		return api.BadRequest(c, "Invalid request body")
+	}
+	if req.InboxID == "" || req.PhoneNumber == "" {
+		return api.ValidationError(c, "inbox_id and phone_number are required")
+	}
+
+	user := middleware.GetUser(c)
+	senderID := ""
+	if user != nil {
+		senderID = user.UserID
+	}
+
+	conv, err := h.service.StartConversation(c.Request().Context(), domain.StartConversationRequest{
+		InboxID:     req.InboxID,
+		PhoneNumber: req.PhoneNumber,
+		Name:        req.Name,
+		Content:     req.Content,
+	}, senderID)
+	if err != nil {
+		switch {
+		case errors.Is(err, phone.ErrInvalid):
+			return api.ValidationError(c, fmt.Sprintf("Invalid phone number: %s", req.PhoneNumber))
+		case errors.Is(err, ports.ErrContactNotFound):
+			return api.ValidationError(c, fmt.Sprintf("Phone number %s is not registered on this channel", req.PhoneNumber))
+		case errors.Is(err, services.ErrStartNotSupported):
+			return api.BadRequest(c, err.Error())
+		}
+		return messageError(c, err)
+	}
+	return api.Created(c, conv)
+}
+
+// messageError converte erros do servico de mensagens em respostas HTTP
+func messageError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrMessageNotFound):
+		return api.NotFound(c, "Message not found")
+	case errors.Is(err, services.ErrInvalidMessage):
+		return api.ValidationError(c, err.Error())
+	case errors.Is(err, services.ErrMessageForbidden):
+		return api.Forbidden(c, err.Error())
+	default:
+		return api.InternalError(c, err.Error())
+	}
+}
+
+// bindMultipartMessage le mensagem enviada como multipart/form-data
+func bindMultipartMessage(c echo.Context, req *SendMessageRequest, maxSize int64) error {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return fmt.Errorf("invalid multipart form")
+	}
+
+	req.Content = c.FormValue("content")
+	req.ContentType = c.FormValue("content_type")
+	req.Private, _ = strconv.ParseBool(c.FormValue("private"))
+	req.Mentions = append(form.Value["mentions"], form.Value["mentions[]"]...)
+	req.Voice, _ = strconv.ParseBool(c.FormValue("voice"))
+	req.InReplyToID = c.FormValue("in_reply_to_id")
+
+	files := append(form.File["attachments"], form.File["attachments[]"]...)
+	for _, fh := range files {
+		if fh.Size > maxSize {
+			return fmt.Errorf("attachment %s exceeds %d bytes", fh.Filename, maxSize)
+		}
+
+		f, err := fh.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read attachment %s", fh.Filename)
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read attachment %s", fh.Filename)
+		}
+
+		req.Attachments = append(req.Attachments, domain.AttachmentRequest{
+			FileName: fh.Filename,
+			MimeType: fh.Header.Get(echo.HeaderContentType),
+			Data:     data,
+		})
+	}
+	return nil
+}
+
+// DownloadAttachment retorna o conteudo de um anexo
+func (h *MessageHandler) DownloadAttachment(c echo.Context) error {
+	att, data, err := h.service.GetAttachment(c.Request().Context(), c.Param("id"))
+	if errors.Is(err, storage.ErrNotFound) {
+		return api.NotFound(c, "Attachment content not found")
+	}
+	if err != nil {
+		return api.InternalError(c, err.Error())
+	}
+	if att == nil {
+		return api.NotFound(c, "Attachment not found")
+	}
+
+	// inline=true so vale para imagens, audio e video
+	return serveFile(c, data, att.MimeType, att.FileName, c.QueryParam("inline") == "true")
+}
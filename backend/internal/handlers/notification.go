@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/zyntra/backend/internal/api"
+	"github.com/zyntra/backend/internal/middleware"
+	"github.com/zyntra/backend/internal/repository"
+)
+
+// NotificationHandler handler de notificacoes do usuario autenticado
+type NotificationHandler struct {
+	repo *repository.NotificationRepository
+}
+
+// NewNotificationHandler cria novo handler
+func NewNotificationHandler(repo *repository.NotificationRepository) *NotificationHandler {
+	return &NotificationHandler{repo: repo}
+}
+
+// List lista notificacoes do usuario (?unread=true apenas nao lidas)
+func (h *NotificationHandler) List(c echo.Context) error {
+	user := middleware.GetUser(c)
+	if user == nil {
+		return api.Unauthorized(c, "User not authenticated")
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	unreadOnly, _ := strconv.ParseBool(c.QueryParam("unread"))
+
+	ctx := c.Request().Context()
+	notifications, err := h.repo.ListByUser(ctx, user.UserID, unreadOnly, limit, offset)
+	if err != nil {
+		return api.InternalError(c, err.Error())
+	}
+	unread, err := h.repo.CountUnread(ctx, user.UserID)
+	if err != nil {
+		return api.InternalError(c, err.Error())
+	}
+
+	return api.Success(c, map[string]interface{}{
+		"notifications": notifications,
+		"unread_count":  unread,
+	})
+}
+
+// MarkRead marca uma notificacao como lida
+func (h *NotificationHandler) MarkRead(c echo.Context) error {
+	user := middleware.GetUser(c)
+	if user == nil {
+		return api.Unauthorized(c, "User not authenticated")
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return api.NotFound(c, "Notification not found")
+	}
+
+	found, err := h.repo.MarkRead(c.Request().Context(), id, user.UserID)
+	if err != nil {
+		return api.InternalError(c, err.Error())
+	}
+	if !found {
+		return api.NotFound(c, "Notification not found")
+	}
+	return api.NoContent(c)
+}
+
+// MarkAllRead marca todas as notificacoes do usuario como lidas
+func (h *NotificationHandler) MarkAllRead(c echo.Context) error {
+	user := middleware.GetUser(c)
+	if user == nil {
+		return api.Unauthorized(c, "User not authenticated")
+	}
+
+	if err := h.repo.MarkAllRead(c.Request().Context(), user.UserID); err != nil {
+		return api.InternalError(c, err.Error())
+	}
+	return api.NoContent(c)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/zyntra/backend/internal/domain"
+	"github.com/zyntra/backend/internal/middleware"
+	"github.com/zyntra/backend/internal/services"
+	wspkg "github.com/zyntra/backend/pkg/websocket"
+)
+
+// realtimeAccess acessos e assinaturas dos clientes do hub (WebSocket e SSE)
+type realtimeAccess struct {
+	hub                 *wspkg.Hub
+	inboxService        *services.InboxService
+	conversationService *services.ConversationService
+}
+
+// loadAccess define os inboxes visiveis ao usuario: todos para admin, os de inbox_members para agentes
+func (a realtimeAccess) loadAccess(ctx context.Context, client *wspkg.Client, user *middleware.UserContext) error {
+	if user.Role == string(domain.UserRoleAdmin) {
+		a.hub.SetAccess(client, true, nil)
+		return nil
+	}
+	inboxIDs, err := a.inboxService.GetUserInboxes(ctx, user.UserID)
+	if err != nil {
+		return err
+	}
+	a.hub.SetAccess(client, false, inboxIDs)
+	return nil
+}
+
+// subscribe assina inboxes e conversas permitidos, retornando os IDs recusados
+func (a realtimeAccess) subscribe(ctx context.Context, client *wspkg.Client, inboxIDs, conversationIDs []string) []string {
+	var denied []string
+	for _, inboxID := range inboxIDs {
+		if !client.SubscribeInbox(inboxID) {
+			denied = append(denied, inboxID)
+		}
+	}
+	for _, conversationID := range conversationIDs {
+		conv, err := a.conversationService.GetByID(ctx, conversationID)
+		if err != nil || !client.SubscribeConversation(conv.ID, conv.InboxID) {
+			denied = append(denied, conversationID)
+		}
+	}
+	return denied
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/zyntra/backend/internal/api"
+	"github.com/zyntra/backend/internal/channels/telegram"
+	"github.com/zyntra/backend/internal/ports"
+	tgpkg "github.com/zyntra/backend/pkg/telegram"
+)
+
+// TelegramWebhookHandler recebe updates da Bot API em modo webhook
+type TelegramWebhookHandler struct {
+	channels ports.ChannelManager
+}
+
+// NewTelegramWebhookHandler cria novo handler
+func NewTelegramWebhookHandler(channels ports.ChannelManager) *TelegramWebhookHandler {
+	return &TelegramWebhookHandler{channels: channels}
+}
+
+// Handle processa um update enviado pelo Telegram
+func (h *TelegramWebhookHandler) Handle(c echo.Context) error {
+	inboxID := c.Param("inbox_id")
+	secret := c.Request().Header.Get("X-Telegram-Bot-Api-Secret-Token")
+
+	var update tgpkg.Update
+	if err := c.Bind(&update); err != nil {
+		return api.BadRequest(c, "Invalid update")
+	}
+
+	channel, err := h.channels.Get(inboxID)
+	if err != nil {
+		log.Printf("[Telegram] Webhook for inbox %s rejected: %v", inboxID, err)
+		return api.NotFound(c, "Inbox not found")
+	}
+	adapter, ok := channel.(*telegram.Adapter)
+	if !ok {
+		return api.NotFound(c, "Inbox not found")
+	}
+
+	if err := adapter.HandleWebhook(secret, update); err != nil {
+		if errors.Is(err, tgpkg.ErrInvalidSecret) {
+			return api.Unauthorized(c, "Invalid secret token")
+		}
+		log.Printf("[Telegram] Webhook for inbox %s rejected: %v", inboxID, err)
+		return api.BadRequest(c, err.Error())
+	}
+
+	return c.NoContent(http.StatusOK)
+}
@@ -1,11 +1,17 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
+	"github.com/zyntra/backend/internal/api"
+	"github.com/zyntra/backend/internal/middleware"
+	"github.com/zyntra/backend/internal/services"
 	wspkg "github.com/zyntra/backend/pkg/websocket"
 )
 
@@ -21,31 +27,77 @@ type WebSocketMessage struct {
 	Payload interface{} `json:"payload"`
 }
 
+// webSocketCommand comando recebido do cliente (payload decodificado conforme o tipo)
+type webSocketCommand struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// SubscriptionRequest payload de subscribe/unsubscribe
+type SubscriptionRequest struct {
+	InboxIDs        []string `json:"inbox_ids"`
+	ConversationIDs []string `json:"conversation_ids"`
+}
+
+// SubscriptionResponse assinaturas atuais do cliente e itens recusados por falta de acesso
+type SubscriptionResponse struct {
+	InboxIDs        []string `json:"inbox_ids"`
+	ConversationIDs []string `json:"conversation_ids"`
+	Denied          []string `json:"denied,omitempty"`
+}
+
 // WebSocketHandler handler de WebSocket
 type WebSocketHandler struct {
-	hub *wspkg.Hub
+	realtimeAccess
 }
 
 // NewWebSocketHandler cria novo handler
-func NewWebSocketHandler(hub *wspkg.Hub) *WebSocketHandler {
-	return &WebSocketHandler{hub: hub}
+func NewWebSocketHandler(hub *wspkg.Hub, inboxService *services.InboxService, conversationService *services.ConversationService) *WebSocketHandler {
+	return &WebSocketHandler{
+		realtimeAccess: realtimeAccess{
+			hub:                 hub,
+			inboxService:        inboxService,
+			conversationService: conversationService,
+		},
+	}
 }
 
-// Handle faz upgrade da conexao
+// Handle faz upgrade da conexao do usuario autenticado.
+// Todo evento tem um ID crescente; para retomar apos queda o cliente reconecta
+// com ?last_event_id=<ultimo ID recebido>.
 func (h *WebSocketHandler) Handle(c echo.Context) error {
+	user := middleware.GetUser(c)
+	if user == nil {
+		return api.Unauthorized(c, "Authentication required")
+	}
+
 	ws, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
 	if err != nil {
 		return err
 	}
 
-	h.hub.Register(ws)
+	ctx := c.Request().Context()
+	client := wspkg.NewClient(ws, user.UserID)
+	if err := h.loadAccess(ctx, client, user); err != nil {
+		log.Printf("[WebSocket] Failed to load inboxes of user %s: %v", user.UserID, err)
+		ws.Close()
+		return nil
+	}
+
+	// Reconexao: last_event_id reenvia os eventos perdidos desde o ultimo recebido
+	if lastEventID, err := strconv.ParseUint(c.QueryParam("last_event_id"), 10, 64); err == nil && lastEventID > 0 {
+		h.hub.Resume(client, lastEventID)
+	} else {
+		h.hub.Register(client)
+	}
+	go client.WritePump()
 
 	defer func() {
-		h.hub.Unregister(ws)
+		h.hub.Unregister(client)
 	}()
 
 	for {
-		var msg WebSocketMessage
+		var msg webSocketCommand
 		err := ws.ReadJSON(&msg)
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
@@ -56,15 +108,71 @@ func (h *WebSocketHandler) Handle(c echo.Context) error {
 
 		switch msg.Type {
 		case "ping":
-			ws.WriteJSON(WebSocketMessage{Type: "pong"})
+			client.Send(WebSocketMessage{Type: "pong"})
 		case "subscribe":
-			ws.WriteJSON(WebSocketMessage{Type: "subscribed", Payload: msg.Payload})
+			h.subscribe(ctx, client, user, msg.Payload)
+		case "unsubscribe":
+			h.unsubscribe(client, msg.Payload)
 		}
 	}
 
 	return nil
 }
 
+// subscribe assina inboxes e conversas, recarregando os acessos do usuario
+func (h *WebSocketHandler) subscribe(ctx context.Context, client *wspkg.Client, user *middleware.UserContext, payload json.RawMessage) {
+	var req SubscriptionRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		client.Send(WebSocketMessage{Type: "error", Payload: "invalid subscription payload"})
+		return
+	}
+
+	// Membros podem ter sido adicionados ou removidos desde a conexao
+	if err := h.loadAccess(ctx, client, user); err != nil {
+		log.Printf("[WebSocket] Failed to load inboxes of user %s: %v", user.UserID, err)
+		client.Send(WebSocketMessage{Type: "error", Payload: "failed to load inbox access"})
+		return
+	}
+
+	denied := h.realtimeAccess.subscribe(ctx, client, req.InboxIDs, req.ConversationIDs)
+	h.writeSubscriptions(client, "subscribed", denied)
+}
+
+// unsubscribe cancela assinaturas de inboxes e conversas
+func (h *WebSocketHandler) unsubscribe(client *wspkg.Client, payload json.RawMessage) {
+	var req SubscriptionRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		client.Send(WebSocketMessage{Type: "error", Payload: "invalid subscription payload"})
+		return
+	}
+
+	for _, inboxID := range req.InboxIDs {
+		client.UnsubscribeInbox(inboxID)
+	}
+	for _, conversationID := range req.ConversationIDs {
+		client.UnsubscribeConversation(conversationID)
+	}
+
+	h.writeSubscriptions(client, "unsubscribed", nil)
+}
+
+func (h *WebSocketHandler) writeSubscriptions(client *wspkg.Client, msgType string, denied []string) {
+	inboxIDs, conversationIDs := client.Subscriptions()
+	client.Send(WebSocketMessage{
+		Type: msgType,
+		Payload: SubscriptionResponse{
+			InboxIDs:        inboxIDs,
+			ConversationIDs: conversationIDs,
+			Denied:          denied,
+		},
+	})
+}
+
+// Stats retorna contadores de entrega do hub (clientes, descartes, desconexoes por lentidao)
+func (h *WebSocketHandler) Stats(c echo.Context) error {
+	return api.Success(c, h.hub.Stats())
+}
+
 // Hub retorna o hub subjacente
 func (h *WebSocketHandler) Hub() *wspkg.Hub {
 	return h.hub
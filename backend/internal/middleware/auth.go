@@ -41,6 +41,7 @@ type UserContext struct {
 type AuthMiddleware struct {
 	jwtService    *auth.JWTService
 	apiKeyService *auth.APIKeyService
+	ticketService *auth.StreamTicketService
 }
 
 // NewAuthMiddleware creates a new auth middleware
@@ -48,6 +49,7 @@ func NewAuthMiddleware(jwtService *auth.JWTService, db *sql.DB) *AuthMiddleware
 	return &AuthMiddleware{
 		jwtService:    jwtService,
 		apiKeyService: auth.NewAPIKeyService(db),
+		ticketService: auth.NewStreamTicketService(db),
 	}
 }
 
@@ -68,10 +70,23 @@ func (m *AuthMiddleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 			return m.authenticateJWT(c, next, token)
 		}
 
+		// Navegadores nao enviam headers no WebSocket nem no EventSource: ticket de uso unico
+		// via query string (o access token nunca vai na URL, que aparece em logs)
+		if ticket := c.QueryParam("ticket"); ticket != "" && isStreamRequest(c) {
+			return m.authenticateTicket(c, next, ticket)
+		}
+
 		return api.Unauthorized(c, "Authentication required")
 	}
 }
 
+// isStreamRequest checks if the request is a WebSocket handshake or an SSE stream
+func isStreamRequest(c echo.Context) bool {
+	req := c.Request()
+	return strings.EqualFold(req.Header.Get("Upgrade"), "websocket") ||
+		strings.Contains(req.Header.Get(echo.HeaderAccept), "text/event-stream")
+}
+
 // authenticateJWT validates JWT token
 func (m *AuthMiddleware) authenticateJWT(c echo.Context, next echo.HandlerFunc, token string) error {
 	claims, err := m.jwtService.ValidateAccessToken(token)
@@ -96,6 +111,29 @@ func (m *AuthMiddleware) authenticateJWT(c echo.Context, next echo.HandlerFunc,
 	return next(c)
 }
 
+// authenticateTicket redeems a single-use stream ticket
+func (m *AuthMiddleware) authenticateTicket(c echo.Context, next echo.HandlerFunc, ticket string) error {
+	claims, err := m.ticketService.Redeem(c.Request().Context(), ticket)
+	if err != nil {
+		if err == auth.ErrStreamTicketInvalid {
+			return api.Error(c, 401, api.ErrCodeInvalidToken, "Invalid or expired stream ticket")
+		}
+		return api.InternalError(c, "Authentication error")
+	}
+
+	userCtx := &UserContext{
+		UserID: claims.UserID,
+		Email:  claims.Email,
+		Role:   claims.Role,
+	}
+
+	ctx := context.WithValue(c.Request().Context(), ContextKeyUser, userCtx)
+	ctx = context.WithValue(ctx, ContextKeyAuthType, AuthTypeJWT)
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	return next(c)
+}
+
 // authenticateAPIKey validates API key
 func (m *AuthMiddleware) authenticateAPIKey(c echo.Context, next echo.HandlerFunc, key string) error {
 	apiKey, err := m.apiKeyService.ValidateAPIKey(c.Request().Context(), key)
@@ -2,6 +2,8 @@ package ports
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
@@ -39,10 +41,25 @@ type Channel interface {
 	Status() ChannelStatus
 
 	// SendText envia mensagem de texto
-	SendText(ctx context.Context, to, content string) (sourceID string, err error)
+	SendText(ctx context.Context, to, content string, opts SendOptions) (sourceID string, err error)
 
 	// SendMedia envia mensagem com midia
-	SendMedia(ctx context.Context, to string, media Media) (sourceID string, err error)
+	SendMedia(ctx context.Context, to string, media Media, opts SendOptions) (sourceID string, err error)
+
+	// SendLocation envia localizacao
+	SendLocation(ctx context.Context, to string, location Location, opts SendOptions) (sourceID string, err error)
+
+	// SendContact envia cartao de contato
+	SendContact(ctx context.Context, to string, contact ContactCard, opts SendOptions) (sourceID string, err error)
+
+	// SendReaction reage a uma mensagem (emoji vazio remove a reacao)
+	SendReaction(ctx context.Context, to string, target MessageRef, emoji string) error
+
+	// EditMessage altera o texto de uma mensagem enviada
+	EditMessage(ctx context.Context, to string, target MessageRef, content string) error
+
+	// DeleteMessage apaga uma mensagem enviada para todos
+	DeleteMessage(ctx context.Context, to string, target MessageRef) error
 
 	// SetEventHandler define o handler de eventos
 	SetEventHandler(handler ChannelEventHandler)
@@ -51,6 +68,110 @@ type Channel interface {
 	GetQRCode() string
 }
 
+// GroupChannel canal com suporte a grupos (implementacao opcional)
+type GroupChannel interface {
+	// GetGroupInfo busca metadados e participantes do grupo
+	GetGroupInfo(ctx context.Context, groupID string) (*GroupInfo, error)
+}
+
+// ReadReceiptChannel canal que confirma leitura ao contato (implementacao opcional)
+type ReadReceiptChannel interface {
+	// MarkRead envia confirmacao de leitura das mensagens recebidas na conversa to
+	MarkRead(ctx context.Context, to string, messages []MessageRef) error
+}
+
+// PresenceChannel canal que exibe "digitando..." ao contato (implementacao opcional)
+type PresenceChannel interface {
+	// SendChatPresence envia o estado do agente na conversa to
+	SendChatPresence(ctx context.Context, to string, presence ChatPresence) error
+}
+
+// ChatPresence estado do agente na conversa
+type ChatPresence string
+
+const (
+	ChatPresenceComposing ChatPresence = "composing"
+	ChatPresenceRecording ChatPresence = "recording"
+	ChatPresencePaused    ChatPresence = "paused"
+)
+
+// HealthChannel canal que reporta a saude da conexao (implementacao opcional)
+type HealthChannel interface {
+	Health() ChannelHealth
+}
+
+// ChannelHealth estado da conexao de um canal
+type ChannelHealth struct {
+	Status         ChannelStatus `json:"status"`
+	Attempt        int           `json:"attempt"` // Tentativas de reconexao consecutivas
+	LastError      string        `json:"last_error,omitempty"`
+	NextRetryAt    *time.Time    `json:"next_retry_at,omitempty"`
+	ConnectedSince *time.Time    `json:"connected_since,omitempty"`
+	LastDisconnect *time.Time    `json:"last_disconnect,omitempty"`
+}
+
+// ErrContactNotFound telefone sem conta no canal (retornado por ContactResolver)
+var ErrContactNotFound = errors.New("contact not found on channel")
+
+// ContactResolver canal que verifica numeros antes de iniciar conversa (implementacao opcional)
+type ContactResolver interface {
+	// ResolveContact verifica se o telefone existe no canal e retorna sua identidade.
+	// Telefones sem conta retornam erro que envolve ErrContactNotFound.
+	ResolveContact(ctx context.Context, phone string) (*ResolvedContact, error)
+}
+
+// ResolvedContact identidade de um telefone no canal
+type ResolvedContact struct {
+	ID     string   // ID canonico no canal (JID)
+	AltIDs []string // Outros IDs do mesmo contato (LID)
+	Phone  string
+	Name   string // Nome verificado, se houver
+}
+
+// ProfileChannel canal que expoe foto e perfil dos contatos (implementacao opcional)
+type ProfileChannel interface {
+	// GetContactProfile busca o perfil do contato; knownPictureID evita baixar foto inalterada
+	GetContactProfile(ctx context.Context, contactID, knownPictureID string) (*ContactProfile, error)
+}
+
+// ContactProfile perfil publico de um contato no canal
+type ContactProfile struct {
+	PictureID      string // Vazio se o contato nao tem foto ou a esconde
+	PictureURL     string // URL temporaria para baixar a foto, quando mudou
+	PictureChanged bool
+	About          string
+	Business       *BusinessProfile
+}
+
+// BusinessProfile perfil comercial do contato
+type BusinessProfile struct {
+	Name       string   `json:"name,omitempty"`
+	Address    string   `json:"address,omitempty"`
+	Email      string   `json:"email,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	Website    string   `json:"website,omitempty"`
+	Timezone   string   `json:"timezone,omitempty"`
+}
+
+// GroupInfo metadados de um grupo no canal
+type GroupInfo struct {
+	ID           string // ID do grupo no canal (JID)
+	Subject      string
+	Description  string
+	OwnerID      string
+	Announce     bool // Apenas admins enviam mensagens
+	Participants []GroupParticipant
+}
+
+// GroupParticipant participante de um grupo no canal
+type GroupParticipant struct {
+	ID           string // ID do participante no canal (JID)
+	Name         string
+	Phone        string
+	IsAdmin      bool
+	IsSuperAdmin bool
+}
+
 // ChannelEventHandler interface para processar eventos do canal
 type ChannelEventHandler interface {
 	OnMessage(event IncomingEvent)
@@ -58,21 +179,63 @@ type ChannelEventHandler interface {
 	OnQRCode(inboxID, qrCode, base64Image string)
 	OnConnected(inboxID, phone string)
 	OnDisconnected(inboxID string)
+	OnReconnecting(inboxID string, attempt int, retryIn time.Duration, err error)
+	OnLoggedOut(inboxID string)
+	OnHistorySync(batch HistoryBatch)
+	OnPresence(event PresenceEvent)
+}
+
+// MessageReceiver handler que informa se a mensagem recebida foi persistida (implementacao opcional).
+// Usado por canais sincronos, como o canal API, para devolver a falha ao remetente.
+type MessageReceiver interface {
+	ReceiveMessage(event IncomingEvent) error
+}
+
+// PresenceEvent contato digitando (EventTypeTyping) ou online/offline (EventTypePresence)
+type PresenceEvent struct {
+	InboxID   string
+	Type      EventType
+	ContactID string // ID da conversa no canal (contato ou grupo)
+	SenderID  string // Participante que esta digitando (grupos)
+	State     string // Typing: composing, recording, paused; presence: online, offline
+	LastSeen  *time.Time
+	Timestamp time.Time
+}
+
+// HistoryBatch lote de mensagens antigas importadas do canal
+type HistoryBatch struct {
+	InboxID       string
+	SyncType      string
+	Chunk         uint32
+	Progress      uint32 // Percentual informado pelo canal (0-100)
+	Conversations int
+	Events        []IncomingEvent
 }
 
 // IncomingEvent evento recebido de qualquer canal
 type IncomingEvent struct {
-	InboxID     string
-	SourceID    string
-	ContactID   string
-	ContactName string
-	IsFromMe    bool
-	Type        EventType
-	Content     string
-	MediaURL    string
-	MediaType   MediaType
-	Timestamp   time.Time
-	RawPayload  map[string]interface{}
+	InboxID      string
+	SourceID     string
+	ContactID    string
+	ContactName  string
+	ContactPhone string
+	IsFromMe     bool
+	IsGroup      bool   // ContactID e um grupo; Sender* identificam o participante
+	SenderID     string // ID do participante no canal (grupos)
+	SenderName   string
+	SenderPhone  string
+	Type         EventType
+	Content      string
+	MediaURL     string
+	MediaType    MediaType
+	Media        *Media                 // Midia ja baixada pelo canal (opcional)
+	ContentType  string                 // Tipo quando nao e texto/midia (location, contact, poll, unsupported)
+	Attributes   map[string]interface{} // Dados estruturados da mensagem (content_attributes)
+	ReplyTo      string                 // source_id da mensagem citada (resposta)
+	Target       string                 // source_id da mensagem alvo (reacao, edicao ou remocao)
+	Reaction     string                 // Emoji da reacao (vazio remove)
+	Timestamp    time.Time
+	RawPayload   map[string]interface{}
 }
 
 // EventType tipo de evento
@@ -85,8 +248,45 @@ const (
 	EventTypeQRCode       EventType = "qr_code"
 	EventTypeConnected    EventType = "connected"
 	EventTypeDisconnected EventType = "disconnected"
+	EventTypeReaction     EventType = "reaction"
+	EventTypeEdit         EventType = "edit"
+	EventTypeRevoke       EventType = "revoke"
+	EventTypeGroupUpdate  EventType = "group_update"
+	EventTypePresence     EventType = "presence"
 )
 
+// MessageRef referencia a uma mensagem ja trocada no canal
+type MessageRef struct {
+	SourceID string // ID da mensagem no canal
+	FromMe   bool   // Enviada pelo proprio canal (agente)
+	Sender   string // Remetente quando FromMe e false (ID do contato no canal)
+	Content  string // Texto da mensagem (usado na citacao)
+}
+
+// SendOptions opcoes de envio
+type SendOptions struct {
+	ReplyTo   *MessageRef // Mensagem citada
+	MessageID string      // ID interno da mensagem sendo entregue
+	Part      int         // Indice da parte quando a mensagem gera varios envios
+}
+
+// IdempotencyKey chave estavel do envio: a mesma em toda retentativa da mesma parte
+func (o SendOptions) IdempotencyKey() string {
+	if o.MessageID == "" {
+		return ""
+	}
+	if o.Part == 0 {
+		return o.MessageID
+	}
+	return fmt.Sprintf("%s.%d", o.MessageID, o.Part)
+}
+
+// Next opcoes da proxima parte: a citacao vai apenas na primeira
+func (o *SendOptions) Next() {
+	o.ReplyTo = nil
+	o.Part++
+}
+
 // Media midia a ser enviada
 type Media struct {
 	Type     MediaType
@@ -95,6 +295,24 @@ type Media struct {
 	MimeType string
 	Caption  string
 	FileName string
+	Voice    bool // Audio enviado como mensagem de voz (push-to-talk)
+}
+
+// Location localizacao a ser enviada
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Name      string  `json:"name,omitempty"`
+	Address   string  `json:"address,omitempty"`
+}
+
+// ContactCard cartao de contato a ser enviado
+type ContactCard struct {
+	Name         string `json:"name"`
+	Phone        string `json:"phone,omitempty"`
+	Email        string `json:"email,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	VCard        string `json:"vcard,omitempty"` // vCard pronto (opcional, senao e gerado dos campos)
 }
 
 // MediaType tipo de midia
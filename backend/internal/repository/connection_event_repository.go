@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/zyntra/backend/internal/domain"
+)
+
+// ConnectionEventRepository repositorio do historico de conexao dos inboxes
+type ConnectionEventRepository struct {
+	db *sql.DB
+}
+
+// NewConnectionEventRepository cria novo repositorio
+func NewConnectionEventRepository(db *sql.DB) *ConnectionEventRepository {
+	return &ConnectionEventRepository{db: db}
+}
+
+// Create registra um evento de conexao
+func (r *ConnectionEventRepository) Create(ctx context.Context, event *domain.ConnectionEvent) error {
+	query := `
+		INSERT INTO inbox_connection_events (inbox_id, event, reason, attempt)
+		VALUES ($1, $2, NULLIF($3, ''), $4)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRowContext(ctx, query,
+		event.InboxID, event.Event, event.Reason, event.Attempt,
+	).Scan(&event.ID, &event.CreatedAt)
+}
+
+// ListByInbox lista eventos de conexao de um inbox (mais recentes primeiro)
+func (r *ConnectionEventRepository) ListByInbox(ctx context.Context, inboxID string, limit int) ([]*domain.ConnectionEvent, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	query := `
+		SELECT id, inbox_id, event, COALESCE(reason, ''), attempt, created_at
+		FROM inbox_connection_events
+		WHERE inbox_id = $1
+		ORDER BY created_at DESC LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, inboxID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*domain.ConnectionEvent{}
+	for rows.Next() {
+		event := &domain.ConnectionEvent{}
+		if err := rows.Scan(
+			&event.ID, &event.InboxID, &event.Event, &event.Reason, &event.Attempt, &event.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
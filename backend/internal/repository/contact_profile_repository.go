@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/zyntra/backend/internal/domain"
+)
+
+// ContactProfileRepository repositorio dos perfis sincronizados dos contatos
+type ContactProfileRepository struct {
+	db *sql.DB
+}
+
+// NewContactProfileRepository cria novo repositorio
+func NewContactProfileRepository(db *sql.DB) *ContactProfileRepository {
+	return &ContactProfileRepository{db: db}
+}
+
+// Upsert grava o perfil do contato
+func (r *ContactProfileRepository) Upsert(ctx context.Context, profile *domain.ContactProfile) error {
+	var business []byte
+	if profile.Business != nil {
+		business, _ = json.Marshal(profile.Business)
+	}
+	query := `
+		INSERT INTO contact_profiles (contact_id, inbox_id, picture_id, about, business_profile, synced_at)
+		VALUES ($1, NULLIF($2, '')::uuid, NULLIF($3, ''), NULLIF($4, ''), $5, $6)
+		ON CONFLICT (contact_id) DO UPDATE SET
+			inbox_id = EXCLUDED.inbox_id,
+			picture_id = EXCLUDED.picture_id,
+			about = EXCLUDED.about,
+			business_profile = EXCLUDED.business_profile,
+			synced_at = EXCLUDED.synced_at
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		profile.ContactID, profile.InboxID, profile.PictureID, profile.About, business, profile.SyncedAt,
+	)
+	return err
+}
+
+// Touch marca o perfil como sincronizado sem alterar os dados
+func (r *ContactProfileRepository) Touch(ctx context.Context, contactID, inboxID string) error {
+	query := `
+		INSERT INTO contact_profiles (contact_id, inbox_id, synced_at)
+		VALUES ($1, NULLIF($2, '')::uuid, NOW())
+		ON CONFLICT (contact_id) DO UPDATE SET synced_at = NOW()
+	`
+	_, err := r.db.ExecContext(ctx, query, contactID, inboxID)
+	return err
+}
+
+// GetByContactID busca perfil do contato
+func (r *ContactProfileRepository) GetByContactID(ctx context.Context, contactID string) (*domain.ContactProfile, error) {
+	query := `
+		SELECT contact_id, COALESCE(inbox_id::text, ''), COALESCE(picture_id, ''), COALESCE(about, ''),
+		       business_profile, synced_at
+		FROM contact_profiles WHERE contact_id = $1
+	`
+	profile := &domain.ContactProfile{}
+	var business []byte
+	err := r.db.QueryRowContext(ctx, query, contactID).Scan(
+		&profile.ContactID, &profile.InboxID, &profile.PictureID, &profile.About,
+		&business, &profile.SyncedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(business) > 0 {
+		json.Unmarshal(business, &profile.Business)
+	}
+	return profile, nil
+}
+
+// ListStale lista contatos do inbox sem perfil ou sincronizados antes de before
+// (os nunca sincronizados primeiro)
+func (r *ContactProfileRepository) ListStale(ctx context.Context, inboxID string, before time.Time, limit int) ([]*domain.ProfileSyncTarget, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	query := `
+		SELECT ci.contact_id, ci.inbox_id, ci.source_id, COALESCE(p.picture_id, '')
+		FROM contact_inboxes ci
+		LEFT JOIN contact_profiles p ON p.contact_id = ci.contact_id
+		WHERE ci.inbox_id = $1 AND (p.synced_at IS NULL OR p.synced_at < $2)
+		ORDER BY p.synced_at ASC NULLS FIRST
+		LIMIT $3
+	`
+	rows, err := r.db.QueryContext(ctx, query, inboxID, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var targets []*domain.ProfileSyncTarget
+	for rows.Next() {
+		t := &domain.ProfileSyncTarget{}
+		if err := rows.Scan(&t.ContactID, &t.InboxID, &t.SourceID, &t.PictureID); err != nil {
+			return nil, err
+		}
+		targets = append(targets, t)
+	}
+	return targets, rows.Err()
+}
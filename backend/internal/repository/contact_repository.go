@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"strings"
 
 	"github.com/zyntra/backend/internal/domain"
 )
@@ -36,14 +37,14 @@ func (r *ContactRepository) Create(ctx context.Context, contact *domain.Contact)
 func (r *ContactRepository) GetByID(ctx context.Context, id string) (*domain.Contact, error) {
 	query := `
 		SELECT id, COALESCE(name, ''), COALESCE(email, ''), COALESCE(phone_number, ''),
-		       COALESCE(avatar_url, ''), COALESCE(custom_attributes, '{}'), created_at, updated_at
+		       COALESCE(avatar_url, ''), COALESCE(avatar_key, ''), COALESCE(custom_attributes, '{}'), created_at, updated_at
 		FROM contacts WHERE id = $1
 	`
 	contact := &domain.Contact{}
 	var attrsJSON []byte
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&contact.ID, &contact.Name, &contact.Email, &contact.PhoneNumber,
-		&contact.AvatarURL, &attrsJSON, &contact.CreatedAt, &contact.UpdatedAt,
+		&contact.AvatarURL, &contact.AvatarKey, &attrsJSON, &contact.CreatedAt, &contact.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -59,14 +60,14 @@ func (r *ContactRepository) GetByID(ctx context.Context, id string) (*domain.Con
 func (r *ContactRepository) GetByPhone(ctx context.Context, phone string) (*domain.Contact, error) {
 	query := `
 		SELECT id, COALESCE(name, ''), COALESCE(email, ''), COALESCE(phone_number, ''),
-		       COALESCE(avatar_url, ''), COALESCE(custom_attributes, '{}'), created_at, updated_at
+		       COALESCE(avatar_url, ''), COALESCE(avatar_key, ''), COALESCE(custom_attributes, '{}'), created_at, updated_at
 		FROM contacts WHERE phone_number = $1
 	`
 	contact := &domain.Contact{}
 	var attrsJSON []byte
 	err := r.db.QueryRowContext(ctx, query, phone).Scan(
 		&contact.ID, &contact.Name, &contact.Email, &contact.PhoneNumber,
-		&contact.AvatarURL, &attrsJSON, &contact.CreatedAt, &contact.UpdatedAt,
+		&contact.AvatarURL, &contact.AvatarKey, &attrsJSON, &contact.CreatedAt, &contact.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -85,7 +86,7 @@ func (r *ContactRepository) GetAll(ctx context.Context, limit, offset int) ([]*d
 	}
 	query := `
 		SELECT id, COALESCE(name, ''), COALESCE(email, ''), COALESCE(phone_number, ''),
-		       COALESCE(avatar_url, ''), COALESCE(custom_attributes, '{}'), created_at, updated_at
+		       COALESCE(avatar_url, ''), COALESCE(avatar_key, ''), COALESCE(custom_attributes, '{}'), created_at, updated_at
 		FROM contacts ORDER BY created_at DESC LIMIT $1 OFFSET $2
 	`
 	rows, err := r.db.QueryContext(ctx, query, limit, offset)
@@ -100,7 +101,7 @@ func (r *ContactRepository) GetAll(ctx context.Context, limit, offset int) ([]*d
 		var attrsJSON []byte
 		if err := rows.Scan(
 			&contact.ID, &contact.Name, &contact.Email, &contact.PhoneNumber,
-			&contact.AvatarURL, &attrsJSON, &contact.CreatedAt, &contact.UpdatedAt,
+			&contact.AvatarURL, &contact.AvatarKey, &attrsJSON, &contact.CreatedAt, &contact.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -115,16 +116,23 @@ func (r *ContactRepository) Update(ctx context.Context, contact *domain.Contact)
 	attrsJSON, _ := json.Marshal(contact.CustomAttributes)
 	query := `
 		UPDATE contacts SET name = $2, email = $3, phone_number = $4, 
-		       avatar_url = $5, custom_attributes = $6, updated_at = NOW()
+		       avatar_url = $5, avatar_key = NULLIF($6, ''), custom_attributes = $7, updated_at = NOW()
 		WHERE id = $1
 	`
 	_, err := r.db.ExecContext(ctx, query,
 		contact.ID, contact.Name, contact.Email, contact.PhoneNumber,
-		contact.AvatarURL, attrsJSON,
+		contact.AvatarURL, contact.AvatarKey, attrsJSON,
 	)
 	return err
 }
 
+// UpdateAvatarKey grava a chave no storage da foto sincronizada do canal
+func (r *ContactRepository) UpdateAvatarKey(ctx context.Context, id, key string) error {
+	query := `UPDATE contacts SET avatar_key = NULLIF($2, ''), updated_at = NOW() WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, key)
+	return err
+}
+
 // Delete remove um contato
 func (r *ContactRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM contacts WHERE id = $1`
@@ -139,12 +147,23 @@ func (r *ContactRepository) Search(ctx context.Context, term string, limit int)
 	}
 	query := `
 		SELECT id, COALESCE(name, ''), COALESCE(email, ''), COALESCE(phone_number, ''),
-		       COALESCE(avatar_url, ''), COALESCE(custom_attributes, '{}'), created_at, updated_at
+		       COALESCE(avatar_url, ''), COALESCE(avatar_key, ''), COALESCE(custom_attributes, '{}'), created_at, updated_at
 		FROM contacts 
 		WHERE name ILIKE $1 OR email ILIKE $1 OR phone_number ILIKE $1
+		   OR ($3 <> '' AND phone_number LIKE '%' || $3 || '%')
 		ORDER BY name LIMIT $2
 	`
-	rows, err := r.db.QueryContext(ctx, query, "%"+term+"%", limit)
+	// Telefones sao gravados em E.164: busca tambem pelos digitos do termo ("(11) 9999-")
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, term)
+	if len(digits) < 4 {
+		digits = ""
+	}
+	rows, err := r.db.QueryContext(ctx, query, "%"+term+"%", limit, digits)
 	if err != nil {
 		return nil, err
 	}
@@ -156,7 +175,7 @@ func (r *ContactRepository) Search(ctx context.Context, term string, limit int)
 		var attrsJSON []byte
 		if err := rows.Scan(
 			&contact.ID, &contact.Name, &contact.Email, &contact.PhoneNumber,
-			&contact.AvatarURL, &attrsJSON, &contact.CreatedAt, &contact.UpdatedAt,
+			&contact.AvatarURL, &contact.AvatarKey, &attrsJSON, &contact.CreatedAt, &contact.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/zyntra/backend/internal/domain"
+)
+
+// GroupRepository repositorio de grupos
+type GroupRepository struct {
+	db *sql.DB
+}
+
+// NewGroupRepository cria novo repositorio
+func NewGroupRepository(db *sql.DB) *GroupRepository {
+	return &GroupRepository{db: db}
+}
+
+const groupColumns = `
+	id, inbox_id, contact_id, source_id, COALESCE(subject, ''), COALESCE(description, ''),
+	COALESCE(owner_source_id, ''), COALESCE(announce, false), created_at, updated_at
+`
+
+func scanGroup(row rowScanner) (*domain.Group, error) {
+	group := &domain.Group{}
+	err := row.Scan(
+		&group.ID, &group.InboxID, &group.ContactID, &group.SourceID, &group.Subject, &group.Description,
+		&group.OwnerSourceID, &group.Announce, &group.CreatedAt, &group.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// Save grava o grupo (inserindo ou atualizando pelo source_id) e substitui os participantes.
+// Participantes com contact_inbox no inbox sao vinculados ao contato.
+func (r *GroupRepository) Save(ctx context.Context, group *domain.Group) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO chat_groups (id, inbox_id, contact_id, source_id, subject, description, owner_source_id, announce)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (inbox_id, source_id) DO UPDATE SET
+			contact_id = EXCLUDED.contact_id,
+			subject = EXCLUDED.subject,
+			description = EXCLUDED.description,
+			owner_source_id = EXCLUDED.owner_source_id,
+			announce = EXCLUDED.announce
+		RETURNING id, created_at, updated_at
+	`
+	if err := tx.QueryRowContext(ctx, query,
+		group.ID, group.InboxID, group.ContactID, group.SourceID, group.Subject,
+		group.Description, group.OwnerSourceID, group.Announce,
+	).Scan(&group.ID, &group.CreatedAt, &group.UpdatedAt); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM chat_group_participants WHERE group_id = $1`, group.ID); err != nil {
+		return err
+	}
+
+	insert := `
+		INSERT INTO chat_group_participants (group_id, source_id, contact_id, name, phone_number, is_admin, is_super_admin)
+		VALUES ($1, $2, (SELECT contact_id FROM contact_inboxes WHERE inbox_id = $3 AND source_id = $2), $4, $5, $6, $7)
+		ON CONFLICT (group_id, source_id) DO NOTHING
+		RETURNING contact_id
+	`
+	for _, p := range group.Participants {
+		p.GroupID = group.ID
+		err := tx.QueryRowContext(ctx, insert,
+			group.ID, p.SourceID, group.InboxID, p.Name, p.PhoneNumber, p.IsAdmin, p.IsSuperAdmin,
+		).Scan(&p.ContactID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetBySourceID busca grupo pelo JID no inbox
+func (r *GroupRepository) GetBySourceID(ctx context.Context, inboxID, sourceID string) (*domain.Group, error) {
+	query := `SELECT ` + groupColumns + ` FROM chat_groups WHERE inbox_id = $1 AND source_id = $2`
+	return scanGroup(r.db.QueryRowContext(ctx, query, inboxID, sourceID))
+}
+
+// GetByContactID busca o grupo representado pelo contato no inbox
+func (r *GroupRepository) GetByContactID(ctx context.Context, inboxID, contactID string) (*domain.Group, error) {
+	query := `SELECT ` + groupColumns + ` FROM chat_groups WHERE inbox_id = $1 AND contact_id = $2`
+	return scanGroup(r.db.QueryRowContext(ctx, query, inboxID, contactID))
+}
+
+// ListParticipants lista participantes do grupo (admins primeiro)
+func (r *GroupRepository) ListParticipants(ctx context.Context, groupID string) ([]*domain.GroupParticipant, error) {
+	query := `
+		SELECT group_id, source_id, contact_id, COALESCE(name, ''), COALESCE(phone_number, ''),
+		       COALESCE(is_admin, false), COALESCE(is_super_admin, false)
+		FROM chat_group_participants WHERE group_id = $1
+		ORDER BY is_super_admin DESC, is_admin DESC, name ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var participants []*domain.GroupParticipant
+	for rows.Next() {
+		p := &domain.GroupParticipant{}
+		if err := rows.Scan(
+			&p.GroupID, &p.SourceID, &p.ContactID, &p.Name, &p.PhoneNumber, &p.IsAdmin, &p.IsSuperAdmin,
+		); err != nil {
+			return nil, err
+		}
+		participants = append(participants, p)
+	}
+	return participants, rows.Err()
+}
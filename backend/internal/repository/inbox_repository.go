@@ -22,12 +22,12 @@ func NewInboxRepository(db *sql.DB) *InboxRepository {
 // Create cria um inbox
 func (r *InboxRepository) Create(ctx context.Context, inbox *domain.Inbox) error {
 	query := `
-		INSERT INTO inboxes (id, name, channel_type, channel_id, status, greeting_message, auto_assignment, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		INSERT INTO inboxes (id, name, channel_type, channel_id, status, greeting_message, auto_assignment, phone_region, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NOW(), NOW())
 	`
 	_, err := r.db.ExecContext(ctx, query,
 		inbox.ID, inbox.Name, inbox.ChannelType, inbox.ChannelID,
-		inbox.Status, inbox.GreetingMessage, inbox.AutoAssignment,
+		inbox.Status, inbox.GreetingMessage, inbox.AutoAssignment, inbox.PhoneRegion,
 	)
 	return err
 }
@@ -36,14 +36,14 @@ func (r *InboxRepository) Create(ctx context.Context, inbox *domain.Inbox) error
 func (r *InboxRepository) GetByID(ctx context.Context, id string) (*domain.Inbox, error) {
 	query := `
 		SELECT id, name, channel_type, channel_id, status, COALESCE(qrcode, ''), 
-		       COALESCE(greeting_message, ''), auto_assignment, created_at, updated_at
+		       COALESCE(greeting_message, ''), auto_assignment, COALESCE(phone_region, ''), created_at, updated_at
 		FROM inboxes WHERE id = $1
 	`
 	inbox := &domain.Inbox{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&inbox.ID, &inbox.Name, &inbox.ChannelType, &inbox.ChannelID,
 		&inbox.Status, &inbox.QRCode, &inbox.GreetingMessage,
-		&inbox.AutoAssignment, &inbox.CreatedAt, &inbox.UpdatedAt,
+		&inbox.AutoAssignment, &inbox.PhoneRegion, &inbox.CreatedAt, &inbox.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -55,7 +55,7 @@ func (r *InboxRepository) GetByID(ctx context.Context, id string) (*domain.Inbox
 func (r *InboxRepository) GetAll(ctx context.Context) ([]*domain.Inbox, error) {
 	query := `
 		SELECT id, name, channel_type, channel_id, status, COALESCE(qrcode, ''),
-		       COALESCE(greeting_message, ''), auto_assignment, created_at, updated_at
+		       COALESCE(greeting_message, ''), auto_assignment, COALESCE(phone_region, ''), created_at, updated_at
 		FROM inboxes ORDER BY created_at DESC
 	`
 	rows, err := r.db.QueryContext(ctx, query)
@@ -70,7 +70,7 @@ func (r *InboxRepository) GetAll(ctx context.Context) ([]*domain.Inbox, error) {
 		if err := rows.Scan(
 			&inbox.ID, &inbox.Name, &inbox.ChannelType, &inbox.ChannelID,
 			&inbox.Status, &inbox.QRCode, &inbox.GreetingMessage,
-			&inbox.AutoAssignment, &inbox.CreatedAt, &inbox.UpdatedAt,
+			&inbox.AutoAssignment, &inbox.PhoneRegion, &inbox.CreatedAt, &inbox.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -83,7 +83,7 @@ func (r *InboxRepository) GetAll(ctx context.Context) ([]*domain.Inbox, error) {
 func (r *InboxRepository) GetByChannelType(ctx context.Context, channelType ports.ChannelType) ([]*domain.Inbox, error) {
 	query := `
 		SELECT id, name, channel_type, channel_id, status, COALESCE(qrcode, ''),
-		       COALESCE(greeting_message, ''), auto_assignment, created_at, updated_at
+		       COALESCE(greeting_message, ''), auto_assignment, COALESCE(phone_region, ''), created_at, updated_at
 		FROM inboxes WHERE channel_type = $1 ORDER BY created_at DESC
 	`
 	rows, err := r.db.QueryContext(ctx, query, channelType)
@@ -98,7 +98,7 @@ func (r *InboxRepository) GetByChannelType(ctx context.Context, channelType port
 		if err := rows.Scan(
 			&inbox.ID, &inbox.Name, &inbox.ChannelType, &inbox.ChannelID,
 			&inbox.Status, &inbox.QRCode, &inbox.GreetingMessage,
-			&inbox.AutoAssignment, &inbox.CreatedAt, &inbox.UpdatedAt,
+			&inbox.AutoAssignment, &inbox.PhoneRegion, &inbox.CreatedAt, &inbox.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -111,11 +111,11 @@ func (r *InboxRepository) GetByChannelType(ctx context.Context, channelType port
 func (r *InboxRepository) Update(ctx context.Context, inbox *domain.Inbox) error {
 	query := `
 		UPDATE inboxes SET name = $2, status = $3, greeting_message = $4, 
-		       auto_assignment = $5, updated_at = NOW()
+		       auto_assignment = $5, phone_region = NULLIF($6, ''), updated_at = NOW()
 		WHERE id = $1
 	`
 	_, err := r.db.ExecContext(ctx, query,
-		inbox.ID, inbox.Name, inbox.Status, inbox.GreetingMessage, inbox.AutoAssignment,
+		inbox.ID, inbox.Name, inbox.Status, inbox.GreetingMessage, inbox.AutoAssignment, inbox.PhoneRegion,
 	)
 	return err
 }
@@ -246,7 +246,7 @@ func (r *ChannelWhatsAppRepository) GetByJID(ctx context.Context, jid string) (*
 func (r *InboxRepository) GetByChannelJID(ctx context.Context, jid string) (*domain.Inbox, error) {
 	query := `
 		SELECT i.id, i.name, i.channel_type, i.channel_id, i.status, COALESCE(i.qrcode, ''),
-		       COALESCE(i.greeting_message, ''), i.auto_assignment, i.created_at, i.updated_at
+		       COALESCE(i.greeting_message, ''), i.auto_assignment, COALESCE(i.phone_region, ''), i.created_at, i.updated_at
 		FROM inboxes i
 		JOIN channel_whatsapp cw ON i.channel_id = cw.id
 		WHERE cw.jid = $1 AND i.channel_type = 'whatsapp'
@@ -255,7 +255,7 @@ func (r *InboxRepository) GetByChannelJID(ctx context.Context, jid string) (*dom
 	err := r.db.QueryRowContext(ctx, query, jid).Scan(
 		&inbox.ID, &inbox.Name, &inbox.ChannelType, &inbox.ChannelID,
 		&inbox.Status, &inbox.QRCode, &inbox.GreetingMessage,
-		&inbox.AutoAssignment, &inbox.CreatedAt, &inbox.UpdatedAt,
+		&inbox.AutoAssignment, &inbox.PhoneRegion, &inbox.CreatedAt, &inbox.UpdatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -263,6 +263,99 @@ func (r *InboxRepository) GetByChannelJID(ctx context.Context, jid string) (*dom
 	return inbox, err
 }
 
+// ChannelTelegramRepository repositorio de canais Telegram
+type ChannelTelegramRepository struct {
+	db *sql.DB
+}
+
+// NewChannelTelegramRepository cria novo repositorio
+func NewChannelTelegramRepository(db *sql.DB) *ChannelTelegramRepository {
+	return &ChannelTelegramRepository{db: db}
+}
+
+// Create cria canal Telegram
+func (r *ChannelTelegramRepository) Create(ctx context.Context, channel *domain.ChannelTelegram) error {
+	query := `
+		INSERT INTO channel_telegram (id, bot_token, bot_username, webhook_secret, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+	`
+	_, err := r.db.ExecContext(ctx, query, channel.ID, channel.BotToken, channel.BotUsername, channel.WebhookSecret)
+	return err
+}
+
+// GetByID busca canal por ID
+func (r *ChannelTelegramRepository) GetByID(ctx context.Context, id string) (*domain.ChannelTelegram, error) {
+	query := `
+		SELECT id, COALESCE(bot_token, ''), COALESCE(bot_username, ''), COALESCE(webhook_secret, ''), created_at, updated_at
+		FROM channel_telegram WHERE id = $1
+	`
+	channel := &domain.ChannelTelegram{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&channel.ID, &channel.BotToken, &channel.BotUsername, &channel.WebhookSecret, &channel.CreatedAt, &channel.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return channel, err
+}
+
+// UpdateUsername atualiza username do bot
+func (r *ChannelTelegramRepository) UpdateUsername(ctx context.Context, id, username string) error {
+	query := `UPDATE channel_telegram SET bot_username = $2, updated_at = NOW() WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, username)
+	return err
+}
+
+// Delete remove canal
+func (r *ChannelTelegramRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM channel_telegram WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// ChannelAPIRepository repositorio de canais API
+type ChannelAPIRepository struct {
+	db *sql.DB
+}
+
+// NewChannelAPIRepository cria novo repositorio
+func NewChannelAPIRepository(db *sql.DB) *ChannelAPIRepository {
+	return &ChannelAPIRepository{db: db}
+}
+
+// Create cria canal API
+func (r *ChannelAPIRepository) Create(ctx context.Context, channel *domain.ChannelAPI) error {
+	query := `
+		INSERT INTO channel_api (id, webhook_url, api_key, webhook_secret, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+	`
+	_, err := r.db.ExecContext(ctx, query, channel.ID, channel.WebhookURL, channel.APIKey, channel.WebhookSecret)
+	return err
+}
+
+// GetByID busca canal por ID
+func (r *ChannelAPIRepository) GetByID(ctx context.Context, id string) (*domain.ChannelAPI, error) {
+	query := `
+		SELECT id, COALESCE(webhook_url, ''), COALESCE(api_key, ''), COALESCE(webhook_secret, ''), created_at, updated_at
+		FROM channel_api WHERE id = $1
+	`
+	channel := &domain.ChannelAPI{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&channel.ID, &channel.WebhookURL, &channel.APIKey, &channel.WebhookSecret, &channel.CreatedAt, &channel.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return channel, err
+}
+
+// Delete remove canal
+func (r *ChannelAPIRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM channel_api WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
 // InboxMemberRepository repositorio de membros do inbox
 type InboxMemberRepository struct {
 	db *sql.DB
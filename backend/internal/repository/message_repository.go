@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/zyntra/backend/internal/domain"
 	"github.com/zyntra/backend/internal/ports"
@@ -14,71 +16,92 @@ type MessageRepository struct {
 	db *sql.DB
 }
 
+// ErrDuplicateSourceID ja existe mensagem com o mesmo source_id no inbox
+var ErrDuplicateSourceID = errors.New("message with this source_id already exists")
+
 // NewMessageRepository cria novo repositorio
 func NewMessageRepository(db *sql.DB) *MessageRepository {
 	return &MessageRepository{db: db}
 }
 
-// Create cria uma mensagem
+// messageColumns colunas lidas por scanMessage
+const messageColumns = `
+	id, conversation_id, inbox_id, sender_type, sender_id,
+	COALESCE(content, ''), content_type, COALESCE(content_attributes, '{}'),
+	COALESCE(source_id, ''), status, private, in_reply_to_id, edited_at, deleted_at, created_at
+`
+
+// rowScanner abstrai *sql.Row e *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanMessage(row rowScanner) (*domain.Message, error) {
+	msg := &domain.Message{}
+	var attrsJSON []byte
+	var inReplyToID sql.NullString
+	if err := row.Scan(
+		&msg.ID, &msg.ConversationID, &msg.InboxID, &msg.SenderType, &msg.SenderID,
+		&msg.Content, &msg.ContentType, &attrsJSON, &msg.SourceID, &msg.Status, &msg.Private,
+		&inReplyToID, &msg.EditedAt, &msg.DeletedAt, &msg.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if inReplyToID.Valid {
+		msg.InReplyToID = &inReplyToID.String
+	}
+	json.Unmarshal(attrsJSON, &msg.ContentAttributes)
+	return msg, nil
+}
+
+// Create cria uma mensagem.
+// Retorna ErrDuplicateSourceID se o inbox ja tem mensagem com o mesmo source_id.
 func (r *MessageRepository) Create(ctx context.Context, msg *domain.Message) error {
 	attrsJSON, _ := json.Marshal(msg.ContentAttributes)
 	query := `
 		INSERT INTO messages (id, conversation_id, inbox_id, sender_type, sender_id, 
-		                      content, content_type, content_attributes, source_id, status, private, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		                      content, content_type, content_attributes, source_id, status, private,
+		                      in_reply_to_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (inbox_id, source_id) WHERE source_id <> '' DO NOTHING
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	result, err := r.db.ExecContext(ctx, query,
 		msg.ID, msg.ConversationID, msg.InboxID, msg.SenderType, msg.SenderID,
-		msg.Content, msg.ContentType, attrsJSON, msg.SourceID, msg.Status, msg.Private, msg.CreatedAt,
+		msg.Content, msg.ContentType, attrsJSON, msg.SourceID, msg.Status, msg.Private,
+		msg.InReplyToID, msg.CreatedAt,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrDuplicateSourceID
+	}
+	return nil
 }
 
 // GetByID busca mensagem por ID
 func (r *MessageRepository) GetByID(ctx context.Context, id string) (*domain.Message, error) {
-	query := `
-		SELECT id, conversation_id, inbox_id, sender_type, sender_id, 
-		       COALESCE(content, ''), content_type, COALESCE(content_attributes, '{}'),
-		       COALESCE(source_id, ''), status, private, created_at
-		FROM messages WHERE id = $1
-	`
-	msg := &domain.Message{}
-	var attrsJSON []byte
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&msg.ID, &msg.ConversationID, &msg.InboxID, &msg.SenderType, &msg.SenderID,
-		&msg.Content, &msg.ContentType, &attrsJSON, &msg.SourceID, &msg.Status, &msg.Private, &msg.CreatedAt,
-	)
+	query := `SELECT ` + messageColumns + ` FROM messages WHERE id = $1`
+	msg, err := scanMessage(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	json.Unmarshal(attrsJSON, &msg.ContentAttributes)
 	return msg, nil
 }
 
 // GetBySourceID busca mensagem por source_id
 func (r *MessageRepository) GetBySourceID(ctx context.Context, inboxID, sourceID string) (*domain.Message, error) {
-	query := `
-		SELECT id, conversation_id, inbox_id, sender_type, sender_id, 
-		       COALESCE(content, ''), content_type, COALESCE(content_attributes, '{}'),
-		       COALESCE(source_id, ''), status, private, created_at
-		FROM messages WHERE inbox_id = $1 AND source_id = $2
-	`
-	msg := &domain.Message{}
-	var attrsJSON []byte
-	err := r.db.QueryRowContext(ctx, query, inboxID, sourceID).Scan(
-		&msg.ID, &msg.ConversationID, &msg.InboxID, &msg.SenderType, &msg.SenderID,
-		&msg.Content, &msg.ContentType, &attrsJSON, &msg.SourceID, &msg.Status, &msg.Private, &msg.CreatedAt,
-	)
+	query := `SELECT ` + messageColumns + ` FROM messages WHERE inbox_id = $1 AND source_id = $2`
+	msg, err := scanMessage(r.db.QueryRowContext(ctx, query, inboxID, sourceID))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	json.Unmarshal(attrsJSON, &msg.ContentAttributes)
 	return msg, nil
 }
 
@@ -88,9 +111,7 @@ func (r *MessageRepository) ListByConversation(ctx context.Context, conversation
 		limit = 50
 	}
 	query := `
-		SELECT id, conversation_id, inbox_id, sender_type, sender_id, 
-		       COALESCE(content, ''), content_type, COALESCE(content_attributes, '{}'),
-		       COALESCE(source_id, ''), status, private, created_at
+		SELECT ` + messageColumns + `
 		FROM messages WHERE conversation_id = $1
 		ORDER BY created_at ASC LIMIT $2 OFFSET $3
 	`
@@ -102,20 +123,48 @@ func (r *MessageRepository) ListByConversation(ctx context.Context, conversation
 
 	var messages []*domain.Message
 	for rows.Next() {
-		msg := &domain.Message{}
-		var attrsJSON []byte
-		if err := rows.Scan(
-			&msg.ID, &msg.ConversationID, &msg.InboxID, &msg.SenderType, &msg.SenderID,
-			&msg.Content, &msg.ContentType, &attrsJSON, &msg.SourceID, &msg.Status, &msg.Private, &msg.CreatedAt,
-		); err != nil {
+		msg, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	return messages, rows.Err()
+}
+
+// ListUnreadIncoming lista mensagens recebidas do contato ainda nao lidas (mais recentes primeiro)
+func (r *MessageRepository) ListUnreadIncoming(ctx context.Context, conversationID string, limit int) ([]*domain.Message, error) {
+	query := `
+		SELECT ` + messageColumns + `
+		FROM messages
+		WHERE conversation_id = $1 AND sender_type = 'contact' AND status <> 'read'
+		  AND COALESCE(source_id, '') <> '' AND deleted_at IS NULL
+		ORDER BY created_at DESC LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, conversationID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*domain.Message
+	for rows.Next() {
+		msg, err := scanMessage(rows)
+		if err != nil {
 			return nil, err
 		}
-		json.Unmarshal(attrsJSON, &msg.ContentAttributes)
 		messages = append(messages, msg)
 	}
 	return messages, rows.Err()
 }
 
+// MarkIncomingRead marca como lidas as mensagens recebidas do contato
+func (r *MessageRepository) MarkIncomingRead(ctx context.Context, conversationID string) error {
+	query := `UPDATE messages SET status = 'read' WHERE conversation_id = $1 AND sender_type = 'contact' AND status <> 'read'`
+	_, err := r.db.ExecContext(ctx, query, conversationID)
+	return err
+}
+
 // UpdateStatus atualiza status de uma mensagem
 func (r *MessageRepository) UpdateStatus(ctx context.Context, id string, status ports.MessageStatus) error {
 	query := `UPDATE messages SET status = $2 WHERE id = $1`
@@ -130,6 +179,83 @@ func (r *MessageRepository) UpdateStatusBySourceID(ctx context.Context, inboxID,
 	return err
 }
 
+// MarkSent marca mensagem como enviada e salva o source_id retornado pelo canal
+func (r *MessageRepository) MarkSent(ctx context.Context, id, sourceID string) error {
+	query := `
+		UPDATE messages
+		SET status = $2, source_id = $3, content_attributes = COALESCE(content_attributes, '{}') - 'error'
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, id, ports.MessageStatusSent, sourceID)
+	return err
+}
+
+// MarkFailed marca mensagem pendente como falha e salva o erro em content_attributes.
+// Mensagens ja enviadas nao sao alteradas.
+func (r *MessageRepository) MarkFailed(ctx context.Context, id, errMsg string) error {
+	query := `
+		UPDATE messages
+		SET status = $2, content_attributes = COALESCE(content_attributes, '{}') || jsonb_build_object('error', $3::text)
+		WHERE id = $1 AND status = $4
+	`
+	_, err := r.db.ExecContext(ctx, query, id, ports.MessageStatusFailed, errMsg, ports.MessageStatusPending)
+	return err
+}
+
+// ListPendingBefore lista IDs de mensagens de saida ainda pendentes criadas antes de before
+func (r *MessageRepository) ListPendingBefore(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	query := `
+		SELECT id FROM messages
+		WHERE status = $1 AND private = false AND created_at < $2
+		ORDER BY created_at
+		LIMIT $3
+	`
+	rows, err := r.db.QueryContext(ctx, query, ports.MessageStatusPending, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// UpdateContent altera o texto de uma mensagem e registra a edicao
+func (r *MessageRepository) UpdateContent(ctx context.Context, id, content string) error {
+	query := `UPDATE messages SET content = $2, edited_at = NOW() WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, content)
+	return err
+}
+
+// MarkDeleted marca mensagem como apagada para todos, descartando conteudo e anexos
+func (r *MessageRepository) MarkDeleted(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM attachments WHERE message_id = $1`, id); err != nil {
+		return err
+	}
+	query := `
+		UPDATE messages
+		SET content = '', content_attributes = '{}', deleted_at = NOW()
+		WHERE id = $1
+	`
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 // Delete remove uma mensagem
 func (r *MessageRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM messages WHERE id = $1`
@@ -148,25 +274,17 @@ func (r *MessageRepository) Count(ctx context.Context, conversationID string) (i
 // GetLastByConversation busca a ultima mensagem de uma conversa
 func (r *MessageRepository) GetLastByConversation(ctx context.Context, conversationID string) (*domain.Message, error) {
 	query := `
-		SELECT id, conversation_id, inbox_id, sender_type, sender_id, 
-		       COALESCE(content, ''), content_type, COALESCE(content_attributes, '{}'),
-		       COALESCE(source_id, ''), status, private, created_at
+		SELECT ` + messageColumns + `
 		FROM messages WHERE conversation_id = $1
 		ORDER BY created_at DESC LIMIT 1
 	`
-	msg := &domain.Message{}
-	var attrsJSON []byte
-	err := r.db.QueryRowContext(ctx, query, conversationID).Scan(
-		&msg.ID, &msg.ConversationID, &msg.InboxID, &msg.SenderType, &msg.SenderID,
-		&msg.Content, &msg.ContentType, &attrsJSON, &msg.SourceID, &msg.Status, &msg.Private, &msg.CreatedAt,
-	)
+	msg, err := scanMessage(r.db.QueryRowContext(ctx, query, conversationID))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	json.Unmarshal(attrsJSON, &msg.ContentAttributes)
 	return msg, nil
 }
 
@@ -262,12 +380,34 @@ func (r *AttachmentRepository) Create(ctx context.Context, att *domain.Attachmen
 	return err
 }
 
+// GetByID busca attachment por ID
+func (r *AttachmentRepository) GetByID(ctx context.Context, id string) (*domain.Attachment, error) {
+	query := `
+		SELECT id, message_id, COALESCE(file_type, ''), COALESCE(file_url, ''),
+		       COALESCE(file_name, ''), COALESCE(file_size, 0), COALESCE(mime_type, ''), created_at
+		FROM attachments WHERE id = $1
+	`
+	att := &domain.Attachment{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&att.ID, &att.MessageID, &att.FileType, &att.FileURL,
+		&att.FileName, &att.FileSize, &att.MimeType, &att.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return att, nil
+}
+
 // GetByMessageID lista attachments de uma mensagem
 func (r *AttachmentRepository) GetByMessageID(ctx context.Context, messageID string) ([]*domain.Attachment, error) {
 	query := `
 		SELECT id, message_id, COALESCE(file_type, ''), COALESCE(file_url, ''),
 		       COALESCE(file_name, ''), COALESCE(file_size, 0), COALESCE(mime_type, ''), created_at
 		FROM attachments WHERE message_id = $1
+		ORDER BY created_at ASC
 	`
 	rows, err := r.db.QueryContext(ctx, query, messageID)
 	if err != nil {
@@ -289,9 +429,102 @@ func (r *AttachmentRepository) GetByMessageID(ctx context.Context, messageID str
 	return attachments, rows.Err()
 }
 
+// GetByMessageIDs lista attachments de varias mensagens agrupados por message_id
+func (r *AttachmentRepository) GetByMessageIDs(ctx context.Context, messageIDs []string) (map[string][]*domain.Attachment, error) {
+	result := make(map[string][]*domain.Attachment)
+	if len(messageIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT id, message_id, COALESCE(file_type, ''), COALESCE(file_url, ''),
+		       COALESCE(file_name, ''), COALESCE(file_size, 0), COALESCE(mime_type, ''), created_at
+		FROM attachments WHERE message_id = ANY($1)
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, messageIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		att := &domain.Attachment{}
+		if err := rows.Scan(
+			&att.ID, &att.MessageID, &att.FileType, &att.FileURL,
+			&att.FileName, &att.FileSize, &att.MimeType, &att.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		result[att.MessageID] = append(result[att.MessageID], att)
+	}
+	return result, rows.Err()
+}
+
 // Delete remove um attachment
 func (r *AttachmentRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM attachments WHERE id = $1`
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
+
+// ReactionRepository repositorio de reacoes
+type ReactionRepository struct {
+	db *sql.DB
+}
+
+// NewReactionRepository cria novo repositorio
+func NewReactionRepository(db *sql.DB) *ReactionRepository {
+	return &ReactionRepository{db: db}
+}
+
+// Upsert grava a reacao do remetente, substituindo a anterior
+func (r *ReactionRepository) Upsert(ctx context.Context, reaction *domain.Reaction) error {
+	query := `
+		INSERT INTO message_reactions (id, message_id, sender_type, sender_id, emoji, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (message_id, sender_type, sender_id)
+		DO UPDATE SET emoji = EXCLUDED.emoji, created_at = EXCLUDED.created_at
+		RETURNING id
+	`
+	return r.db.QueryRowContext(ctx, query,
+		reaction.ID, reaction.MessageID, reaction.SenderType, reaction.SenderID, reaction.Emoji, reaction.CreatedAt,
+	).Scan(&reaction.ID)
+}
+
+// Delete remove a reacao do remetente
+func (r *ReactionRepository) Delete(ctx context.Context, messageID string, senderType domain.SenderType, senderID string) error {
+	query := `DELETE FROM message_reactions WHERE message_id = $1 AND sender_type = $2 AND sender_id = $3`
+	_, err := r.db.ExecContext(ctx, query, messageID, senderType, senderID)
+	return err
+}
+
+// GetByMessageIDs lista reacoes de varias mensagens agrupadas por message_id
+func (r *ReactionRepository) GetByMessageIDs(ctx context.Context, messageIDs []string) (map[string][]*domain.Reaction, error) {
+	result := make(map[string][]*domain.Reaction)
+	if len(messageIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT id, message_id, sender_type, sender_id, emoji, created_at
+		FROM message_reactions WHERE message_id = ANY($1)
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, messageIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		reaction := &domain.Reaction{}
+		if err := rows.Scan(
+			&reaction.ID, &reaction.MessageID, &reaction.SenderType, &reaction.SenderID, &reaction.Emoji, &reaction.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		result[reaction.MessageID] = append(result[reaction.MessageID], reaction)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/zyntra/backend/internal/domain"
+)
+
+// NotificationRepository repositorio de notificacoes
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationRepository cria novo repositorio
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// CreateMentions cria notificacoes de mencao para os usuarios informados.
+// Apenas membros do inbox e admins sao notificados; os demais IDs sao ignorados.
+func (r *NotificationRepository) CreateMentions(ctx context.Context, userIDs []string, inboxID, conversationID, messageID string, actorID *string) ([]*domain.Notification, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	query := `
+		INSERT INTO notifications (user_id, type, conversation_id, message_id, actor_id)
+		SELECT u.id, $2, $3, $4, $5 FROM users u
+		WHERE u.id::text = ANY($1)
+		  AND (u.role = $7 OR EXISTS (
+		      SELECT 1 FROM inbox_members m WHERE m.inbox_id = $6 AND m.user_id = u.id
+		  ))
+		RETURNING id, user_id, type, conversation_id, message_id, actor_id, read_at, created_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, userIDs, domain.NotificationTypeMention, conversationID, messageID, actorID,
+		inboxID, domain.UserRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanNotifications(rows)
+}
+
+// ListByUser lista notificacoes de um usuario (mais recentes primeiro)
+func (r *NotificationRepository) ListByUser(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]*domain.Notification, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	query := `
+		SELECT id, user_id, type, conversation_id, message_id, actor_id, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1 AND ($2 = false OR read_at IS NULL)
+		ORDER BY created_at DESC LIMIT $3 OFFSET $4
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanNotifications(rows)
+}
+
+// CountUnread conta notificacoes nao lidas de um usuario
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count)
+	return count, err
+}
+
+// MarkRead marca notificacao como lida; retorna false se nao pertencer ao usuario
+func (r *NotificationRepository) MarkRead(ctx context.Context, id, userID string) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE notifications SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND user_id = $2`,
+		id, userID,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// MarkAllRead marca todas as notificacoes do usuario como lidas
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`, userID)
+	return err
+}
+
+func scanNotifications(rows *sql.Rows) ([]*domain.Notification, error) {
+	var notifications []*domain.Notification
+	for rows.Next() {
+		n := &domain.Notification{}
+		var conversationID, messageID sql.NullString
+		if err := rows.Scan(
+			&n.ID, &n.UserID, &n.Type, &conversationID, &messageID, &n.ActorID, &n.ReadAt, &n.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		n.ConversationID = conversationID.String
+		n.MessageID = messageID.String
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
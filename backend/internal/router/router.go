@@ -6,6 +6,7 @@ import (
 	"github.com/labstack/echo/v4"
 	echoMiddleware "github.com/labstack/echo/v4/middleware"
 	"github.com/zyntra/backend/internal/api"
+	"github.com/zyntra/backend/internal/domain"
 	"github.com/zyntra/backend/internal/handlers"
 	"github.com/zyntra/backend/internal/middleware"
 )
@@ -27,6 +28,12 @@ type Handlers struct {
 	Contact      *handlers.ContactHandler
 	Label        *handlers.LabelHandler
 	WebSocket    *handlers.WebSocketHandler
+	Events       *handlers.EventsHandler
+	Telegram     *handlers.TelegramWebhookHandler
+	APIChannel   *handlers.APIChannelHandler
+	Files        *handlers.FileHandler
+	Notification *handlers.NotificationHandler
+	Group        *handlers.GroupHandler
 }
 
 // Setup configura todas as rotas
@@ -49,6 +56,19 @@ func Setup(e *echo.Echo, cfg Config, h Handlers) {
 	// Auth routes (public)
 	setupAuthRoutes(v1, h.Auth, cfg.StrictRateLimiter)
 
+	// Channel webhooks (public, autenticados pelo proprio canal)
+	if h.Telegram != nil {
+		v1.POST("/webhooks/telegram/:inbox_id", h.Telegram.Handle)
+	}
+	if h.APIChannel != nil {
+		v1.POST("/webhooks/api/:inbox_id", h.APIChannel.Inbound)
+	}
+
+	// Arquivos do storage local (public, autenticados por URL assinada)
+	if h.Files != nil {
+		v1.GET("/files/*", h.Files.Serve)
+	}
+
 	// Protected routes
 	protected := v1.Group("")
 	protected.Use(cfg.AuthMiddleware.Authenticate)
@@ -59,13 +79,30 @@ func Setup(e *echo.Echo, cfg Config, h Handlers) {
 	// Setup protected routes
 	setupInboxRoutes(protected, h.Inbox)
 	setupConversationRoutes(protected, h.Conversation, h.Message)
+	if h.Group != nil {
+		protected.GET("/conversations/:id/group", h.Group.Get)
+	}
+	setupMessageRoutes(protected, h.Message)
+	setupAttachmentRoutes(protected, h.Message)
 	setupContactRoutes(protected, h.Contact)
 	setupLabelRoutes(protected, h.Label)
 	setupAPIKeyRoutes(protected, h.APIKey)
+	if h.Notification != nil {
+		setupNotificationRoutes(protected, h.Notification)
+	}
 	
+	// Ticket de uso unico para autenticar WebSocket e SSE no navegador
+	protected.POST("/auth/stream-ticket", h.Auth.StreamTicket)
+
 	// WebSocket
 	if h.WebSocket != nil {
 		protected.GET("/ws", h.WebSocket.Handle)
+		protected.GET("/ws/stats", h.WebSocket.Stats, middleware.RequireRole(string(domain.UserRoleAdmin)))
+	}
+
+	// Server-Sent Events
+	if h.Events != nil {
+		protected.GET("/events", h.Events.Stream)
 	}
 }
 
@@ -96,15 +133,18 @@ func setupInboxRoutes(g *echo.Group, h *handlers.InboxHandler) {
 	inboxes.POST("/:id/connect", h.Connect)
 	inboxes.POST("/:id/disconnect", h.Disconnect)
 	inboxes.GET("/:id/qrcode", h.GetQRCode)
+	inboxes.POST("/:id/pair-code", h.PairCode)
+	inboxes.GET("/:id/health", h.GetHealth)
 }
 
 func setupConversationRoutes(g *echo.Group, convH *handlers.ConversationHandler, msgH *handlers.MessageHandler) {
 	conversations := g.Group("/conversations")
 	conversations.GET("", convH.List)
+	conversations.POST("", msgH.StartConversation)
 	conversations.GET("/:id", convH.Get)
 	conversations.PUT("/:id", convH.Update)
 	conversations.DELETE("/:id", convH.Delete)
-	conversations.POST("/:id/read", convH.MarkAsRead)
+	conversations.POST("/:id/read", msgH.MarkAsRead)
 	conversations.POST("/:id/assign", convH.Assign)
 	conversations.POST("/:id/favorite", convH.ToggleFavorite)
 	conversations.POST("/:id/archive", convH.ToggleArchive)
@@ -112,6 +152,20 @@ func setupConversationRoutes(g *echo.Group, convH *handlers.ConversationHandler,
 	// Messages nested under conversations
 	conversations.GET("/:id/messages", msgH.List)
 	conversations.POST("/:id/messages", msgH.Send)
+	conversations.POST("/:id/typing", msgH.SendPresence)
+}
+
+func setupMessageRoutes(g *echo.Group, h *handlers.MessageHandler) {
+	messages := g.Group("/messages")
+	messages.PATCH("/:id", h.Edit)
+	messages.DELETE("/:id", h.Delete)
+	messages.POST("/:id/reactions", h.React)
+	messages.DELETE("/:id/reactions", h.RemoveReaction)
+}
+
+func setupAttachmentRoutes(g *echo.Group, h *handlers.MessageHandler) {
+	attachments := g.Group("/attachments")
+	attachments.GET("/:id/download", h.DownloadAttachment)
 }
 
 func setupContactRoutes(g *echo.Group, h *handlers.ContactHandler) {
@@ -131,6 +185,13 @@ func setupLabelRoutes(g *echo.Group, h *handlers.LabelHandler) {
 	labels.DELETE("/:id", h.Delete)
 }
 
+func setupNotificationRoutes(g *echo.Group, h *handlers.NotificationHandler) {
+	notifications := g.Group("/notifications")
+	notifications.GET("", h.List)
+	notifications.POST("/read", h.MarkAllRead)
+	notifications.POST("/:id/read", h.MarkRead)
+}
+
 func setupAPIKeyRoutes(g *echo.Group, h *handlers.APIKeyHandler) {
 	apikeys := g.Group("/api-keys")
 	apikeys.GET("", h.ListAPIKeys)
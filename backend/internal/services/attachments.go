@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/zyntra/backend/internal/domain"
+	"github.com/zyntra/backend/internal/ports"
+)
+
+const (
+	// MaxAttachments quantidade maxima de anexos por mensagem
+	MaxAttachments = 10
+
+	attachmentFetchTimeout = 30 * time.Second
+	attachmentMaxRedirects = 5
+)
+
+// ErrInvalidAttachment anexo rejeitado na validacao
+var ErrInvalidAttachment = errors.New("invalid attachment")
+
+// errBlockedAddress destino interno recusado no download de URLs externas
+var errBlockedAddress = errors.New("destination address not allowed")
+
+// fetchClient cliente HTTP para URLs informadas por usuarios ou contatos.
+// O endereco e verificado depois da resolucao DNS, em toda conexao (inclusive
+// redirecionamentos), e o proxy do ambiente e ignorado para que a checagem
+// se aplique ao destino real.
+var fetchClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: nil,
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: func(network, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				addr, err := netip.ParseAddr(host)
+				if err != nil || !isPublicAddr(addr) {
+					return fmt.Errorf("%w: %s", errBlockedAddress, host)
+				}
+				return nil
+			},
+		}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: attachmentFetchTimeout,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= attachmentMaxRedirects {
+			return fmt.Errorf("stopped after %d redirects", attachmentMaxRedirects)
+		}
+		if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+			return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+		}
+		return nil
+	},
+}
+
+// cgnatPrefix faixa compartilhada de operadoras (RFC 6598), nao roteavel publicamente
+var cgnatPrefix = netip.MustParsePrefix("100.64.0.0/10")
+
+// isPublicAddr verifica se o endereco e roteavel na internet publica
+// (recusa loopback, redes privadas, link-local, multicast e nao especificado)
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified() &&
+		!cgnatPrefix.Contains(addr)
+}
+
+// preparedAttachment anexo validado com conteudo em memoria
+type preparedAttachment struct {
+	attachment domain.Attachment
+	data       []byte
+}
+
+// prepareAttachments valida os anexos e baixa os informados por URL
+func prepareAttachments(ctx context.Context, reqs []domain.AttachmentRequest, maxSize int64) ([]preparedAttachment, error) {
+	if len(reqs) > MaxAttachments {
+		return nil, fmt.Errorf("%w: at most %d attachments allowed", ErrInvalidAttachment, MaxAttachments)
+	}
+
+	prepared := make([]preparedAttachment, 0, len(reqs))
+	for i, req := range reqs {
+		data := req.Data
+		if len(data) == 0 {
+			if req.FileURL == "" {
+				return nil, fmt.Errorf("%w: attachment %d requires data or file_url", ErrInvalidAttachment, i)
+			}
+			fetched, err := fetchAttachment(ctx, req.FileURL, maxSize)
+			if err != nil {
+				return nil, fmt.Errorf("%w: attachment %d: %v", ErrInvalidAttachment, i, err)
+			}
+			data = fetched
+		}
+		if int64(len(data)) > maxSize {
+			return nil, fmt.Errorf("%w: attachment %d exceeds %d bytes", ErrInvalidAttachment, i, maxSize)
+		}
+
+		mimeType := req.MimeType
+		if mimeType == "" || mimeType == "application/octet-stream" {
+			mimeType = http.DetectContentType(data)
+		}
+
+		fileType := req.FileType
+		if fileType == "" {
+			fileType = string(mediaTypeFromMime(mimeType))
+		}
+		if !isSupportedMediaType(ports.MediaType(fileType)) {
+			return nil, fmt.Errorf("%w: attachment %d has unsupported file_type %q", ErrInvalidAttachment, i, fileType)
+		}
+
+		fileName := sanitizeFileName(req.FileName)
+		if fileName == "" && req.FileURL != "" {
+			if u, err := url.Parse(req.FileURL); err == nil {
+				fileName = sanitizeFileName(path.Base(u.Path))
+			}
+		}
+		if fileName == "" {
+			fileName = fileType
+		}
+
+		prepared = append(prepared, preparedAttachment{
+			attachment: domain.Attachment{
+				FileType: fileType,
+				FileName: fileName,
+				FileSize: int64(len(data)),
+				MimeType: mimeType,
+			},
+			data: data,
+		})
+	}
+	return prepared, nil
+}
+
+// incomingAttachment monta anexo a partir da midia recebida do canal
+func incomingAttachment(event ports.IncomingEvent) preparedAttachment {
+	media := event.Media
+
+	mimeType := media.MimeType
+	if mimeType == "" {
+		mimeType = http.DetectContentType(media.Data)
+	}
+
+	fileType := media.Type
+	if !isSupportedMediaType(fileType) {
+		fileType = mediaTypeFromMime(mimeType)
+	}
+
+	fileName := sanitizeFileName(media.FileName)
+	if fileName == "" {
+		fileName = event.SourceID + extensionForMime(mimeType)
+	}
+
+	return preparedAttachment{
+		attachment: domain.Attachment{
+			FileType: string(fileType),
+			FileName: fileName,
+			FileSize: int64(len(media.Data)),
+			MimeType: mimeType,
+		},
+		data: media.Data,
+	}
+}
+
+// extensionForMime retorna extensao de arquivo para o mime type (ou vazio)
+func extensionForMime(mimeType string) string {
+	if exts, err := mime.ExtensionsByType(mimeType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// fetchAttachment baixa anexo de uma URL http(s) publica respeitando o tamanho maximo
+func fetchAttachment(ctx context.Context, rawURL string, maxSize int64) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("file_url must be an http(s) URL")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, attachmentFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := fetchClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch file_url: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to fetch file_url: HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file_url: %v", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("file_url exceeds %d bytes", maxSize)
+	}
+	return data, nil
+}
+
+// mediaTypeFromMime infere o tipo de midia a partir do mime type
+func mediaTypeFromMime(mimeType string) ports.MediaType {
+	switch {
+	case mimeType == "image/webp":
+		return ports.MediaTypeSticker
+	case strings.HasPrefix(mimeType, "image/"):
+		return ports.MediaTypeImage
+	case strings.HasPrefix(mimeType, "video/"):
+		return ports.MediaTypeVideo
+	case strings.HasPrefix(mimeType, "audio/"):
+		return ports.MediaTypeAudio
+	default:
+		return ports.MediaTypeDocument
+	}
+}
+
+func isSupportedMediaType(t ports.MediaType) bool {
+	switch t {
+	case ports.MediaTypeImage, ports.MediaTypeVideo, ports.MediaTypeAudio,
+		ports.MediaTypeDocument, ports.MediaTypeSticker:
+		return true
+	}
+	return false
+}
+
+// sanitizeFileName remove diretorios e caracteres de controle do nome do arquivo
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "." || name == "/" {
+		return ""
+	}
+	return strings.TrimSpace(name)
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fc00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"100.64.0.1", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+	}
+	for _, tt := range tests {
+		if got := isPublicAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("isPublicAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestFetchAttachmentBlocksInternalAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("secret"))
+	}))
+	defer srv.Close()
+
+	_, err := fetchAttachment(context.Background(), srv.URL, 1024)
+	if !errors.Is(err, errBlockedAddress) {
+		t.Fatalf("fetchAttachment(%s) error = %v, want errBlockedAddress", srv.URL, err)
+	}
+}
+
+func TestFetchAttachmentRejectsNonHTTP(t *testing.T) {
+	for _, raw := range []string{"file:///etc/passwd", "gopher://example.com", "not a url"} {
+		if _, err := fetchAttachment(context.Background(), raw, 1024); err == nil {
+			t.Errorf("fetchAttachment(%q) succeeded, want error", raw)
+		}
+	}
+}
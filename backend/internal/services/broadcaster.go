@@ -4,10 +4,18 @@ import (
 	"github.com/zyntra/backend/internal/domain"
 )
 
-// BroadcastHub interface para o hub de broadcast (implementado por handlers.WebSocketHub)
+// BroadcastHub interface para o hub de broadcast (implementado por websocket.Hub).
+// conversationID permite entregar o evento a quem assinou apenas a conversa.
 type BroadcastHub interface {
-	BroadcastMessage(inboxID string, message interface{})
-	BroadcastConversationUpdate(inboxID string, conversation interface{})
+	BroadcastMessage(inboxID, conversationID string, message interface{})
+	BroadcastConversationUpdate(inboxID, conversationID string, conversation interface{})
+	BroadcastMessageUpdate(inboxID, conversationID string, message interface{})
+	BroadcastMessageDeleted(inboxID, conversationID string, message interface{})
+	BroadcastReaction(inboxID, conversationID string, reaction interface{})
+	BroadcastNotification(userID string, notification interface{})
+	BroadcastHistorySync(inboxID string, progress interface{})
+	BroadcastTyping(inboxID, conversationID string, typing interface{})
+	BroadcastPresence(inboxID, conversationID string, presence interface{})
 }
 
 // WebSocketBroadcaster implementa EventBroadcaster usando BroadcastHub
@@ -25,7 +33,7 @@ func (b *WebSocketBroadcaster) BroadcastMessage(inboxID string, msg *domain.Mess
 	if b.hub == nil {
 		return
 	}
-	b.hub.BroadcastMessage(inboxID, msg)
+	b.hub.BroadcastMessage(inboxID, msg.ConversationID, msg)
 }
 
 // BroadcastConversationUpdate envia atualizacao de conversa via WebSocket
@@ -33,7 +41,63 @@ func (b *WebSocketBroadcaster) BroadcastConversationUpdate(inboxID string, conv
 	if b.hub == nil {
 		return
 	}
-	b.hub.BroadcastConversationUpdate(inboxID, conv)
+	b.hub.BroadcastConversationUpdate(inboxID, conv.ID, conv)
+}
+
+// BroadcastMessageUpdate envia mensagem editada via WebSocket
+func (b *WebSocketBroadcaster) BroadcastMessageUpdate(inboxID string, msg *domain.Message) {
+	if b.hub == nil {
+		return
+	}
+	b.hub.BroadcastMessageUpdate(inboxID, msg.ConversationID, msg)
+}
+
+// BroadcastMessageDeleted envia mensagem apagada via WebSocket
+func (b *WebSocketBroadcaster) BroadcastMessageDeleted(inboxID string, msg *domain.Message) {
+	if b.hub == nil {
+		return
+	}
+	b.hub.BroadcastMessageDeleted(inboxID, msg.ConversationID, msg)
+}
+
+// BroadcastReaction envia reacao via WebSocket
+func (b *WebSocketBroadcaster) BroadcastReaction(inboxID string, reaction *domain.Reaction) {
+	if b.hub == nil {
+		return
+	}
+	b.hub.BroadcastReaction(inboxID, reaction.ConversationID, reaction)
+}
+
+// BroadcastNotification envia notificacao ao usuario via WebSocket
+func (b *WebSocketBroadcaster) BroadcastNotification(n *domain.Notification) {
+	if b.hub == nil {
+		return
+	}
+	b.hub.BroadcastNotification(n.UserID, n)
+}
+
+// BroadcastHistoryProgress envia andamento da importacao de historico via WebSocket
+func (b *WebSocketBroadcaster) BroadcastHistoryProgress(inboxID string, progress *domain.HistoryImportProgress) {
+	if b.hub == nil {
+		return
+	}
+	b.hub.BroadcastHistorySync(inboxID, progress)
+}
+
+// BroadcastTyping envia indicador de digitacao via WebSocket
+func (b *WebSocketBroadcaster) BroadcastTyping(inboxID string, typing *domain.TypingIndicator) {
+	if b.hub == nil {
+		return
+	}
+	b.hub.BroadcastTyping(inboxID, typing.ConversationID, typing)
+}
+
+// BroadcastPresence envia online/offline do contato via WebSocket
+func (b *WebSocketBroadcaster) BroadcastPresence(inboxID string, presence *domain.ContactPresence) {
+	if b.hub == nil {
+		return
+	}
+	b.hub.BroadcastPresence(inboxID, presence.ConversationID, presence)
 }
 
 // Verify interface implementation
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/zyntra/backend/internal/domain"
+	"github.com/zyntra/backend/internal/ports"
+	"github.com/zyntra/backend/internal/repository"
+	"github.com/zyntra/backend/pkg/storage"
+)
+
+const (
+	profileQueueSize     = 256
+	profileRefreshEvery  = time.Hour
+	profileMaxAge        = 7 * 24 * time.Hour
+	profileRefreshBatch  = 100
+	profileFetchTimeout  = 30 * time.Second
+	profileMaxPictureLen = 2 << 20
+)
+
+// ContactProfileService sincroniza foto e perfil comercial dos contatos pelo canal.
+// Contatos novos entram numa fila; os demais sao atualizados periodicamente
+// quando o perfil tem mais de profileMaxAge.
+type ContactProfileService struct {
+	contactRepo *repository.ContactRepository
+	profileRepo *repository.ContactProfileRepository
+	storage     *storage.Store
+	channels    ports.ChannelManager
+
+	queue chan domain.ProfileSyncTarget
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewContactProfileService cria novo servico
+func NewContactProfileService(
+	contactRepo *repository.ContactRepository,
+	profileRepo *repository.ContactProfileRepository,
+	store *storage.Store,
+	registry ports.ChannelManager,
+) *ContactProfileService {
+	return &ContactProfileService{
+		contactRepo: contactRepo,
+		profileRepo: profileRepo,
+		storage:     store,
+		channels:    registry,
+		queue:       make(chan domain.ProfileSyncTarget, profileQueueSize),
+		stop:        make(chan struct{}),
+	}
+}
+
+// Start inicia o worker da fila e a atualizacao periodica.
+// Rodam em goroutines separadas para que um lote de perfis antigos
+// nao segure os contatos novos nem encha a fila.
+func (s *ContactProfileService) Start() {
+	s.wg.Add(2)
+	go s.runQueue()
+	go s.runRefresh()
+	log.Printf("[ContactProfile] Sync started")
+}
+
+// Stop interrompe a sincronizacao
+func (s *ContactProfileService) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+// Enqueue agenda a sincronizacao do perfil de um contato.
+// Com a fila cheia o pedido e descartado: a atualizacao periodica cobre o contato.
+func (s *ContactProfileService) Enqueue(inboxID, contactID, sourceID string) {
+	select {
+	case s.queue <- domain.ProfileSyncTarget{ContactID: contactID, InboxID: inboxID, SourceID: sourceID}:
+	default:
+	}
+}
+
+// GetProfile busca o perfil sincronizado do contato
+func (s *ContactProfileService) GetProfile(ctx context.Context, contactID string) (*domain.ContactProfile, error) {
+	return s.profileRepo.GetByContactID(ctx, contactID)
+}
+
+// SignAvatars preenche AvatarURL com URL assinada da foto sincronizada
+func (s *ContactProfileService) SignAvatars(ctx context.Context, contacts ...*domain.Contact) {
+	for _, contact := range contacts {
+		if contact == nil || contact.AvatarKey == "" {
+			continue
+		}
+		url, err := s.storage.URL(ctx, contact.AvatarKey, contact.ID+".jpg")
+		if err != nil {
+			log.Printf("[ContactProfile] Failed to sign avatar of contact %s: %v", contact.ID, err)
+			continue
+		}
+		contact.AvatarURL = url
+	}
+}
+
+// runQueue sincroniza os contatos enfileirados
+func (s *ContactProfileService) runQueue() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case target := <-s.queue:
+			s.syncTarget(&target)
+		}
+	}
+}
+
+// runRefresh atualiza periodicamente os perfis antigos
+func (s *ContactProfileService) runRefresh() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(profileRefreshEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.refreshStale()
+		}
+	}
+}
+
+// refreshStale atualiza perfis antigos dos inboxes conectados
+func (s *ContactProfileService) refreshStale() {
+	ctx := context.Background()
+	before := time.Now().Add(-profileMaxAge)
+
+	for inboxID, channel := range s.channels.GetAll() {
+		if _, ok := channel.(ports.ProfileChannel); !ok || channel.Status() != ports.ChannelStatusConnected {
+			continue
+		}
+
+		targets, err := s.profileRepo.ListStale(ctx, inboxID, before, profileRefreshBatch)
+		if err != nil {
+			log.Printf("[ContactProfile] Failed to list stale profiles of inbox %s: %v", inboxID, err)
+			continue
+		}
+		for _, target := range targets {
+			select {
+			case <-s.stop:
+				return
+			default:
+			}
+			s.syncTarget(target)
+		}
+	}
+}
+
+// syncTarget sincroniza um perfil registrando falhas em log
+func (s *ContactProfileService) syncTarget(target *domain.ProfileSyncTarget) {
+	ctx, cancel := context.WithTimeout(context.Background(), profileFetchTimeout)
+	defer cancel()
+
+	if err := s.Sync(ctx, target); err != nil {
+		log.Printf("[ContactProfile] Failed to sync contact %s: %v", target.ContactID, err)
+	}
+}
+
+// Sync busca o perfil no canal e grava foto e dados comerciais.
+// Canais sem suporte ou desconectados sao ignorados.
+func (s *ContactProfileService) Sync(ctx context.Context, target *domain.ProfileSyncTarget) error {
+	channel, err := s.channels.Get(target.InboxID)
+	if err != nil {
+		return nil
+	}
+	profileChannel, ok := channel.(ports.ProfileChannel)
+	if !ok || channel.Status() != ports.ChannelStatusConnected {
+		return nil
+	}
+
+	current, err := s.profileRepo.GetByContactID(ctx, target.ContactID)
+	if err != nil {
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+	knownPictureID := target.PictureID
+	if current != nil {
+		knownPictureID = current.PictureID
+	}
+
+	remote, err := profileChannel.GetContactProfile(ctx, target.SourceID, knownPictureID)
+	if err != nil {
+		// Marca como sincronizado para nao repetir a consulta ate a proxima atualizacao
+		if touchErr := s.profileRepo.Touch(ctx, target.ContactID, target.InboxID); touchErr != nil {
+			log.Printf("[ContactProfile] Failed to touch profile of contact %s: %v", target.ContactID, touchErr)
+		}
+		return err
+	}
+
+	if remote.PictureChanged {
+		key, err := s.savePicture(ctx, remote.PictureURL)
+		if err != nil {
+			return err
+		}
+		if err := s.contactRepo.UpdateAvatarKey(ctx, target.ContactID, key); err != nil {
+			return fmt.Errorf("failed to update avatar: %w", err)
+		}
+	}
+
+	return s.profileRepo.Upsert(ctx, &domain.ContactProfile{
+		ContactID: target.ContactID,
+		InboxID:   target.InboxID,
+		PictureID: remote.PictureID,
+		About:     remote.About,
+		Business:  remote.Business,
+		SyncedAt:  time.Now(),
+	})
+}
+
+// savePicture baixa a foto e grava no storage; URL vazia significa foto removida
+func (s *ContactProfileService) savePicture(ctx context.Context, pictureURL string) (string, error) {
+	if pictureURL == "" {
+		return "", nil
+	}
+
+	data, err := fetchAttachment(ctx, pictureURL, profileMaxPictureLen)
+	if err != nil {
+		return "", fmt.Errorf("failed to download picture: %w", err)
+	}
+	obj, err := s.storage.Save(ctx, data, http.DetectContentType(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to store picture: %w", err)
+	}
+	return obj.Key, nil
+}
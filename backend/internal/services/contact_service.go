@@ -8,12 +8,14 @@ import (
 	"github.com/google/uuid"
 	"github.com/zyntra/backend/internal/domain"
 	"github.com/zyntra/backend/internal/repository"
+	"github.com/zyntra/backend/pkg/phone"
 )
 
 // ContactService servico de contatos
 type ContactService struct {
 	contactRepo      *repository.ContactRepository
 	contactInboxRepo *repository.ContactInboxRepository
+	profiles         *ContactProfileService
 }
 
 // NewContactService cria novo servico
@@ -27,13 +29,23 @@ func NewContactService(
 	}
 }
 
+// SetProfileService define o servico de fotos e perfis sincronizados do canal
+func (s *ContactService) SetProfileService(p *ContactProfileService) {
+	s.profiles = p
+}
+
 // Create cria um contato
 func (s *ContactService) Create(ctx context.Context, req domain.CreateContactRequest) (*domain.Contact, error) {
+	phoneNumber, err := phone.Normalize(req.PhoneNumber, "")
+	if err != nil {
+		return nil, err
+	}
+
 	contact := &domain.Contact{
 		ID:               uuid.New().String(),
 		Name:             req.Name,
 		Email:            req.Email,
-		PhoneNumber:      req.PhoneNumber,
+		PhoneNumber:      phoneNumber,
 		CustomAttributes: req.CustomAttributes,
 		CreatedAt:        time.Now(),
 		UpdatedAt:        time.Now(),
@@ -55,22 +67,42 @@ func (s *ContactService) GetByID(ctx context.Context, id string) (*domain.Contac
 	if contact == nil {
 		return nil, fmt.Errorf("contact not found")
 	}
+	s.signAvatars(ctx, contact)
 	return contact, nil
 }
 
-// GetByPhone busca contato por telefone
-func (s *ContactService) GetByPhone(ctx context.Context, phone string) (*domain.Contact, error) {
-	return s.contactRepo.GetByPhone(ctx, phone)
+// GetByPhone busca contato por telefone em qualquer formato
+func (s *ContactService) GetByPhone(ctx context.Context, phoneNumber string) (*domain.Contact, error) {
+	normalized, err := phone.Normalize(phoneNumber, "")
+	if err != nil || normalized == "" {
+		return nil, err
+	}
+	contact, err := s.contactRepo.GetByPhone(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+	s.signAvatars(ctx, contact)
+	return contact, nil
 }
 
 // List lista contatos
 func (s *ContactService) List(ctx context.Context, limit, offset int) ([]*domain.Contact, error) {
-	return s.contactRepo.GetAll(ctx, limit, offset)
+	contacts, err := s.contactRepo.GetAll(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	s.signAvatars(ctx, contacts...)
+	return contacts, nil
 }
 
 // Search busca contatos
 func (s *ContactService) Search(ctx context.Context, term string, limit int) ([]*domain.Contact, error) {
-	return s.contactRepo.Search(ctx, term, limit)
+	contacts, err := s.contactRepo.Search(ctx, term, limit)
+	if err != nil {
+		return nil, err
+	}
+	s.signAvatars(ctx, contacts...)
+	return contacts, nil
 }
 
 // Update atualiza um contato
@@ -87,10 +119,16 @@ func (s *ContactService) Update(ctx context.Context, id string, req domain.Updat
 		contact.Email = *req.Email
 	}
 	if req.PhoneNumber != nil {
-		contact.PhoneNumber = *req.PhoneNumber
+		phoneNumber, err := phone.Normalize(*req.PhoneNumber, "")
+		if err != nil {
+			return nil, err
+		}
+		contact.PhoneNumber = phoneNumber
 	}
 	if req.AvatarURL != nil {
+		// Foto definida manualmente substitui a sincronizada do canal
 		contact.AvatarURL = *req.AvatarURL
+		contact.AvatarKey = ""
 	}
 	if req.CustomAttributes != nil {
 		for k, v := range req.CustomAttributes {
@@ -107,6 +145,7 @@ func (s *ContactService) Update(ctx context.Context, id string, req domain.Updat
 		return nil, fmt.Errorf("failed to update contact: %w", err)
 	}
 
+	s.signAvatars(ctx, contact)
 	return contact, nil
 }
 
@@ -124,10 +163,22 @@ func (s *ContactService) GetWithInboxes(ctx context.Context, id string) (*domain
 
 	contactInboxes, _ := s.contactInboxRepo.GetByContactID(ctx, id)
 
-	return &domain.ContactWithInboxes{
+	result := &domain.ContactWithInboxes{
 		Contact:        *contact,
 		ContactInboxes: derefContactInboxes(contactInboxes),
-	}, nil
+	}
+	if s.profiles != nil {
+		s.profiles.SignAvatars(ctx, &result.Contact)
+		result.Profile, _ = s.profiles.GetProfile(ctx, id)
+	}
+	return result, nil
+}
+
+// signAvatars preenche a URL das fotos sincronizadas, se houver servico de perfis
+func (s *ContactService) signAvatars(ctx context.Context, contacts ...*domain.Contact) {
+	if s.profiles != nil {
+		s.profiles.SignAvatars(ctx, contacts...)
+	}
 }
 
 func derefContactInboxes(list []*domain.ContactInbox) []domain.ContactInbox {
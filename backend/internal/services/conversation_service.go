@@ -15,6 +15,8 @@ type ConversationService struct {
 	labelRepo        *repository.LabelRepository
 	inboxRepo        *repository.InboxRepository
 	messageRepo      *repository.MessageRepository
+	groupRepo        *repository.GroupRepository
+	profiles         *ContactProfileService
 }
 
 // NewConversationService cria novo servico
@@ -24,6 +26,7 @@ func NewConversationService(
 	labelRepo *repository.LabelRepository,
 	inboxRepo *repository.InboxRepository,
 	messageRepo *repository.MessageRepository,
+	groupRepo *repository.GroupRepository,
 ) *ConversationService {
 	return &ConversationService{
 		conversationRepo: conversationRepo,
@@ -31,9 +34,15 @@ func NewConversationService(
 		labelRepo:        labelRepo,
 		inboxRepo:        inboxRepo,
 		messageRepo:      messageRepo,
+		groupRepo:        groupRepo,
 	}
 }
 
+// SetProfileService define o servico que assina as fotos dos contatos
+func (s *ConversationService) SetProfileService(p *ContactProfileService) {
+	s.profiles = p
+}
+
 // GetByID busca conversa por ID
 func (s *ConversationService) GetByID(ctx context.Context, id string) (*domain.Conversation, error) {
 	conv, err := s.conversationRepo.GetByID(ctx, id)
@@ -57,6 +66,7 @@ func (s *ConversationService) GetWithDetails(ctx context.Context, id string) (*d
 
 	// Buscar contato
 	if contact, _ := s.contactRepo.GetByID(ctx, conv.ContactID); contact != nil {
+		s.signAvatars(ctx, contact)
 		result.Contact = contact
 	}
 
@@ -65,6 +75,12 @@ func (s *ConversationService) GetWithDetails(ctx context.Context, id string) (*d
 		result.Inbox = inbox
 	}
 
+	// Buscar grupo (com participantes)
+	if group, _ := s.groupRepo.GetByContactID(ctx, conv.InboxID, conv.ContactID); group != nil {
+		group.Participants, _ = s.groupRepo.ListParticipants(ctx, group.ID)
+		result.Group = group
+	}
+
 	return result, nil
 }
 
@@ -86,9 +102,15 @@ func (s *ConversationService) ListWithDetails(ctx context.Context, filter domain
 
 		// Buscar contato
 		if contact, _ := s.contactRepo.GetByID(ctx, conv.ContactID); contact != nil {
+			s.signAvatars(ctx, contact)
 			cwd.Contact = contact
 		}
 
+		// Buscar grupo
+		if group, _ := s.groupRepo.GetByContactID(ctx, conv.InboxID, conv.ContactID); group != nil {
+			cwd.Group = group
+		}
+
 		// Buscar ultima mensagem
 		if s.messageRepo != nil {
 			if msg, _ := s.messageRepo.GetLastByConversation(ctx, conv.ID); msg != nil {
@@ -196,11 +218,6 @@ func (s *ConversationService) Unassign(ctx context.Context, id string) error {
 	return s.conversationRepo.Update(ctx, conv)
 }
 
-// MarkAsRead marca como lida
-func (s *ConversationService) MarkAsRead(ctx context.Context, id string) error {
-	return s.conversationRepo.ResetUnread(ctx, id)
-}
-
 // AddLabel adiciona label
 func (s *ConversationService) AddLabel(ctx context.Context, conversationID, labelID string) error {
 	return s.labelRepo.AddToConversation(ctx, conversationID, labelID)
@@ -220,3 +237,10 @@ func (s *ConversationService) GetLabels(ctx context.Context, conversationID stri
 func (s *ConversationService) Delete(ctx context.Context, id string) error {
 	return s.conversationRepo.Delete(ctx, id)
 }
+
+// signAvatars preenche a URL das fotos sincronizadas, se houver servico de perfis
+func (s *ConversationService) signAvatars(ctx context.Context, contacts ...*domain.Contact) {
+	if s.profiles != nil {
+		s.profiles.SignAvatars(ctx, contacts...)
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/zyntra/backend/internal/domain"
+	"github.com/zyntra/backend/internal/ports"
+	"github.com/zyntra/backend/pkg/phone"
+)
+
+// ErrStartNotSupported canal nao permite iniciar conversa por telefone
+var ErrStartNotSupported = errors.New("channel does not support starting conversations by phone number")
+
+// StartConversation inicia conversa de saida com um telefone.
+// O numero e verificado no canal antes de criar contato, identidade e conversa;
+// numeros inexistentes retornam o erro do canal (envolvendo ports.ErrContactNotFound).
+func (s *MessageService) StartConversation(ctx context.Context, req domain.StartConversationRequest, senderID string) (*domain.ConversationWithDetails, error) {
+	if req.InboxID == "" || req.PhoneNumber == "" {
+		return nil, fmt.Errorf("%w: inbox_id and phone_number are required", ErrInvalidMessage)
+	}
+
+	inbox, err := s.inboxRepo.GetByID(ctx, req.InboxID)
+	if err != nil || inbox == nil {
+		return nil, fmt.Errorf("inbox not found")
+	}
+
+	if s.channels == nil {
+		return nil, fmt.Errorf("channel manager not initialized")
+	}
+	channel, err := s.channels.Get(req.InboxID)
+	if err != nil || channel.Status() != ports.ChannelStatusConnected {
+		return nil, fmt.Errorf("inbox %s not connected", req.InboxID)
+	}
+	resolver, ok := channel.(ports.ContactResolver)
+	if !ok {
+		return nil, ErrStartNotSupported
+	}
+
+	phoneNumber, err := phone.Normalize(req.PhoneNumber, inbox.PhoneRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := resolver.ResolveContact(ctx, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// Contato ja conhecido por outro ID do canal (ex: LID sem telefone)
+	sourceID := resolved.ID
+	for _, id := range resolved.AltIDs {
+		if ci, err := s.contactInboxRepo.GetBySourceID(ctx, req.InboxID, id); err == nil && ci != nil {
+			sourceID = id
+			break
+		}
+	}
+
+	name := req.Name
+	if name == "" {
+		name = resolved.Name
+	}
+	contact, err := s.findOrCreateContact(ctx, ports.IncomingEvent{
+		InboxID:      req.InboxID,
+		ContactID:    sourceID,
+		ContactPhone: resolved.Phone,
+		ContactName:  name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contact: %w", err)
+	}
+
+	contactInbox, err := s.findOrCreateContactInbox(ctx, req.InboxID, contact.ID, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contact inbox: %w", err)
+	}
+
+	conv, err := s.findOrCreateConversation(ctx, req.InboxID, contact.ID, contactInbox.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+	if s.broadcaster != nil {
+		s.broadcaster.BroadcastConversationUpdate(req.InboxID, conv)
+	}
+
+	if s.profiles != nil {
+		s.profiles.SignAvatars(ctx, contact)
+	}
+
+	result := &domain.ConversationWithDetails{
+		Conversation: *conv,
+		Contact:      contact,
+		Inbox:        inbox,
+	}
+
+	if req.Content != "" {
+		msg, err := s.SendMessage(ctx, conv.ID, domain.SendMessageRequest{
+			Content:     req.Content,
+			ContentType: domain.ContentTypeText,
+		}, senderID)
+		if err != nil {
+			return nil, fmt.Errorf("conversation created but first message failed: %w", err)
+		}
+		result.LastMessage = msg
+	}
+
+	return result, nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/zyntra/backend/internal/ports"
 )
@@ -11,6 +12,7 @@ import (
 type ChannelEventHandler struct {
 	inboxService   *InboxService
 	messageService *MessageService
+	outbound       *OutboundQueue
 }
 
 // NewChannelEventHandler cria novo handler
@@ -21,16 +23,25 @@ func NewChannelEventHandler(inboxService *InboxService, messageService *MessageS
 	}
 }
 
+// SetOutboundQueue define a fila de envio consumida pelos inboxes conectados nesta replica
+func (h *ChannelEventHandler) SetOutboundQueue(q *OutboundQueue) {
+	h.outbound = q
+}
+
 // OnMessage processa mensagem recebida
 func (h *ChannelEventHandler) OnMessage(event ports.IncomingEvent) {
-	log.Printf("[EventHandler] Message received for inbox %s from %s", event.InboxID, event.ContactID)
-
-	ctx := context.Background()
-	if err := h.messageService.ProcessIncomingMessage(ctx, event); err != nil {
+	if err := h.ReceiveMessage(event); err != nil {
 		log.Printf("[EventHandler] Failed to process message: %v", err)
 	}
 }
 
+// ReceiveMessage processa mensagem recebida e retorna a falha ao canal
+func (h *ChannelEventHandler) ReceiveMessage(event ports.IncomingEvent) error {
+	log.Printf("[EventHandler] Message received for inbox %s from %s", event.InboxID, event.ContactID)
+
+	return h.messageService.ProcessIncomingMessage(context.Background(), event)
+}
+
 // OnStatusUpdate processa atualizacao de status
 func (h *ChannelEventHandler) OnStatusUpdate(inboxID, sourceID string, status ports.MessageStatus) {
 	log.Printf("[EventHandler] Status update for inbox %s: %s -> %s", inboxID, sourceID, status)
@@ -59,17 +70,71 @@ func (h *ChannelEventHandler) OnConnected(inboxID, phone string) {
 	if err := h.inboxService.OnConnected(ctx, inboxID, phone); err != nil {
 		log.Printf("[EventHandler] Failed to handle connection: %v", err)
 	}
+
+	// A replica com a sessao do canal passa a entregar as mensagens do inbox
+	if h.outbound != nil {
+		if err := h.outbound.Attach(ctx, inboxID); err != nil {
+			log.Printf("[EventHandler] Failed to attach outbound queue: %v", err)
+		}
+	}
 }
 
 // OnDisconnected processa desconexao
 func (h *ChannelEventHandler) OnDisconnected(inboxID string) {
 	log.Printf("[EventHandler] Disconnected inbox %s", inboxID)
 
+	if h.outbound != nil {
+		h.outbound.Detach(inboxID)
+	}
+
 	ctx := context.Background()
 	if err := h.inboxService.OnDisconnected(ctx, inboxID); err != nil {
 		log.Printf("[EventHandler] Failed to handle disconnection: %v", err)
 	}
 }
 
+// OnReconnecting processa tentativa de reconexao agendada
+func (h *ChannelEventHandler) OnReconnecting(inboxID string, attempt int, retryIn time.Duration, err error) {
+	log.Printf("[EventHandler] Reconnecting inbox %s (attempt %d) in %s", inboxID, attempt, retryIn)
+
+	ctx := context.Background()
+	if err := h.inboxService.OnReconnecting(ctx, inboxID, attempt, retryIn, err); err != nil {
+		log.Printf("[EventHandler] Failed to handle reconnection: %v", err)
+	}
+}
+
+// OnLoggedOut processa sessao encerrada no aparelho
+func (h *ChannelEventHandler) OnLoggedOut(inboxID string) {
+	log.Printf("[EventHandler] Logged out inbox %s", inboxID)
+
+	if h.outbound != nil {
+		h.outbound.Detach(inboxID)
+	}
+
+	ctx := context.Background()
+	if err := h.inboxService.OnLoggedOut(ctx, inboxID); err != nil {
+		log.Printf("[EventHandler] Failed to handle logout: %v", err)
+	}
+}
+
+// OnHistorySync processa lote de historico do canal
+func (h *ChannelEventHandler) OnHistorySync(batch ports.HistoryBatch) {
+	log.Printf("[EventHandler] History sync for inbox %s: %d messages", batch.InboxID, len(batch.Events))
+
+	ctx := context.Background()
+	if err := h.messageService.ImportHistory(ctx, batch); err != nil {
+		log.Printf("[EventHandler] Failed to import history: %v", err)
+	}
+}
+
+// OnPresence processa digitacao e online/offline do contato
+func (h *ChannelEventHandler) OnPresence(event ports.PresenceEvent) {
+	ctx := context.Background()
+	if err := h.messageService.ProcessPresence(ctx, event); err != nil {
+		log.Printf("[EventHandler] Failed to process presence: %v", err)
+	}
+}
+
 // Verify interface implementation
 var _ ports.ChannelEventHandler = (*ChannelEventHandler)(nil)
+var _ ports.MessageReceiver = (*ChannelEventHandler)(nil)
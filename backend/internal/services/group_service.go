@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/zyntra/backend/internal/domain"
+	"github.com/zyntra/backend/internal/ports"
+	"github.com/zyntra/backend/internal/repository"
+	"github.com/zyntra/backend/pkg/phone"
+)
+
+// ErrNotGroup conversa nao e de um grupo
+var ErrNotGroup = errors.New("conversation is not a group")
+
+// GroupService servico de grupos (metadados e participantes)
+type GroupService struct {
+	groupRepo        *repository.GroupRepository
+	conversationRepo *repository.ConversationRepository
+	contactRepo      *repository.ContactRepository
+	contactInboxRepo *repository.ContactInboxRepository
+	channels         ports.ChannelManager
+}
+
+// NewGroupService cria novo servico
+func NewGroupService(
+	groupRepo *repository.GroupRepository,
+	conversationRepo *repository.ConversationRepository,
+	contactRepo *repository.ContactRepository,
+	contactInboxRepo *repository.ContactInboxRepository,
+	channels ports.ChannelManager,
+) *GroupService {
+	return &GroupService{
+		groupRepo:        groupRepo,
+		conversationRepo: conversationRepo,
+		contactRepo:      contactRepo,
+		contactInboxRepo: contactInboxRepo,
+		channels:         channels,
+	}
+}
+
+// Ensure retorna o grupo gravado ou sincroniza com o canal se ainda nao existir
+func (s *GroupService) Ensure(ctx context.Context, inboxID, sourceID, contactID string) (*domain.Group, error) {
+	group, err := s.groupRepo.GetBySourceID(ctx, inboxID, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+	if group != nil {
+		return group, nil
+	}
+	return s.Sync(ctx, inboxID, sourceID, contactID)
+}
+
+// Sync busca metadados do grupo no canal e grava grupo e participantes.
+// O nome do contato que representa o grupo acompanha o assunto.
+func (s *GroupService) Sync(ctx context.Context, inboxID, sourceID, contactID string) (*domain.Group, error) {
+	if s.channels == nil {
+		return nil, fmt.Errorf("channel manager not initialized")
+	}
+	channel, err := s.channels.Get(inboxID)
+	if err != nil {
+		return nil, fmt.Errorf("inbox %s not connected", inboxID)
+	}
+	groupChannel, ok := channel.(ports.GroupChannel)
+	if !ok {
+		return nil, fmt.Errorf("channel %s does not support groups", channel.Type())
+	}
+
+	info, err := groupChannel.GetGroupInfo(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &domain.Group{
+		ID:            uuid.New().String(),
+		InboxID:       inboxID,
+		ContactID:     contactID,
+		SourceID:      sourceID,
+		Subject:       info.Subject,
+		Description:   info.Description,
+		OwnerSourceID: info.OwnerID,
+		Announce:      info.Announce,
+	}
+	for _, p := range info.Participants {
+		group.Participants = append(group.Participants, &domain.GroupParticipant{
+			SourceID:     p.ID,
+			Name:         p.Name,
+			PhoneNumber:  participantPhone(p.Phone),
+			IsAdmin:      p.IsAdmin,
+			IsSuperAdmin: p.IsSuperAdmin,
+		})
+	}
+
+	if err := s.groupRepo.Save(ctx, group); err != nil {
+		return nil, fmt.Errorf("failed to save group: %w", err)
+	}
+
+	if contact, err := s.contactRepo.GetByID(ctx, contactID); err == nil && contact != nil && info.Subject != "" && contact.Name != info.Subject {
+		contact.Name = info.Subject
+		if err := s.contactRepo.Update(ctx, contact); err != nil {
+			log.Printf("[GroupService] Failed to rename group contact %s: %v", contactID, err)
+		}
+	}
+
+	log.Printf("[GroupService] Synced group %s (%d participants)", sourceID, len(group.Participants))
+	return group, nil
+}
+
+// SyncBySourceID sincroniza um grupo ja conhecido no inbox (ignora grupos sem conversa)
+func (s *GroupService) SyncBySourceID(ctx context.Context, inboxID, sourceID string) (*domain.Group, error) {
+	ci, err := s.contactInboxRepo.GetBySourceID(ctx, inboxID, sourceID)
+	if err != nil || ci == nil {
+		return nil, nil
+	}
+	return s.Sync(ctx, inboxID, sourceID, ci.ContactID)
+}
+
+// GetByConversation retorna o grupo da conversa com participantes.
+// refresh busca os metadados atualizados no canal.
+func (s *GroupService) GetByConversation(ctx context.Context, conversationID string, refresh bool) (*domain.Group, error) {
+	conv, err := s.conversationRepo.GetByID(ctx, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+	if conv == nil {
+		return nil, fmt.Errorf("conversation not found")
+	}
+
+	group, err := s.groupRepo.GetByContactID(ctx, conv.InboxID, conv.ContactID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+	if group == nil {
+		return nil, ErrNotGroup
+	}
+
+	if refresh {
+		return s.Sync(ctx, group.InboxID, group.SourceID, group.ContactID)
+	}
+
+	group.Participants, err = s.groupRepo.ListParticipants(ctx, group.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participants: %w", err)
+	}
+	return group, nil
+}
+
+// participantPhone normaliza o telefone do participante (vindo do canal em formato internacional)
+func participantPhone(raw string) string {
+	normalized, err := phone.Normalize(raw, "")
+	if err != nil {
+		return raw
+	}
+	return normalized
+}
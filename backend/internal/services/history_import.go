@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+
+	"github.com/zyntra/backend/internal/domain"
+	"github.com/zyntra/backend/internal/ports"
+	"github.com/zyntra/backend/internal/repository"
+)
+
+// ImportHistory grava um lote de historico do canal.
+// Idempotente por source_id: mensagens ja existentes sao ignoradas, entao lotes
+// repetidos (ou um novo pareamento) nao duplicam conversas nem mensagens.
+func (s *MessageService) ImportHistory(ctx context.Context, batch ports.HistoryBatch) error {
+	progress := &domain.HistoryImportProgress{
+		InboxID:       batch.InboxID,
+		SyncType:      batch.SyncType,
+		Chunk:         batch.Chunk,
+		Progress:      batch.Progress,
+		Conversations: batch.Conversations,
+	}
+	s.broadcastHistoryProgress(progress)
+
+	// Ordem cronologica: citacoes, reacoes e edicoes encontram a mensagem alvo
+	events := make([]ports.IncomingEvent, len(batch.Events))
+	copy(events, batch.Events)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	touched := make(map[string]*domain.Conversation)
+	for _, event := range events {
+		event.InboxID = batch.InboxID
+
+		switch event.Type {
+		case ports.EventTypeMessage:
+		case ports.EventTypeReaction, ports.EventTypeEdit, ports.EventTypeRevoke:
+			if err := s.processInteraction(ctx, event); err != nil {
+				log.Printf("[MessageService] Failed to import %s %s: %v", event.Type, event.SourceID, err)
+			}
+			continue
+		default:
+			continue
+		}
+
+		if event.SourceID == "" {
+			progress.Skipped++
+			continue
+		}
+		if existing, err := s.messageRepo.GetBySourceID(ctx, batch.InboxID, event.SourceID); err == nil && existing != nil {
+			progress.Skipped++
+			continue
+		}
+
+		msg, conv, err := s.saveIncoming(ctx, event)
+		if errors.Is(err, repository.ErrDuplicateSourceID) {
+			progress.Skipped++
+			continue
+		}
+		if err != nil {
+			log.Printf("[MessageService] Failed to import message %s: %v", event.SourceID, err)
+			progress.Skipped++
+			continue
+		}
+		progress.Imported++
+
+		if prev, ok := touched[conv.ID]; ok {
+			conv = prev
+		}
+		if conv.LastMessageAt == nil || msg.CreatedAt.After(*conv.LastMessageAt) {
+			createdAt := msg.CreatedAt
+			conv.LastMessageAt = &createdAt
+		}
+		touched[conv.ID] = conv
+	}
+
+	// Historico nao conta como nao lido: apenas a ordenacao das conversas muda
+	for _, conv := range touched {
+		if err := s.conversationRepo.Update(ctx, conv); err != nil {
+			log.Printf("[MessageService] Failed to update conversation %s: %v", conv.ID, err)
+			continue
+		}
+		if s.broadcaster != nil {
+			s.broadcaster.BroadcastConversationUpdate(batch.InboxID, conv)
+		}
+	}
+
+	progress.Done = true
+	s.broadcastHistoryProgress(progress)
+
+	log.Printf("[MessageService] History chunk %d for inbox %s: %d imported, %d skipped",
+		batch.Chunk, batch.InboxID, progress.Imported, progress.Skipped)
+	return nil
+}
+
+func (s *MessageService) broadcastHistoryProgress(progress *domain.HistoryImportProgress) {
+	if s.broadcaster == nil {
+		return
+	}
+	snapshot := *progress
+	s.broadcaster.BroadcastHistoryProgress(progress.InboxID, &snapshot)
+}
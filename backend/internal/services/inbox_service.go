@@ -2,62 +2,136 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/zyntra/backend/internal/channels"
+	"github.com/zyntra/backend/internal/channels/apichannel"
 	"github.com/zyntra/backend/internal/domain"
 	"github.com/zyntra/backend/internal/ports"
 	"github.com/zyntra/backend/internal/repository"
-	"github.com/zyntra/backend/internal/channels/whatsapp"
+	"github.com/zyntra/backend/pkg/phone"
+	tgpkg "github.com/zyntra/backend/pkg/telegram"
 )
 
+// pairCodeTimeout tempo maximo para obter o codigo de pareamento
+const pairCodeTimeout = 30 * time.Second
+
+// ErrPairingNotSupported canal nao suporta pareamento por codigo
+var ErrPairingNotSupported = errors.New("channel does not support phone pairing")
+
 // InboxService servico de inboxes
 type InboxService struct {
 	inboxRepo      *repository.InboxRepository
 	waChannelRepo  *repository.ChannelWhatsAppRepository
+	tgChannelRepo  *repository.ChannelTelegramRepository
+	apiChannelRepo *repository.ChannelAPIRepository
 	memberRepo     *repository.InboxMemberRepository
-	waManager      *whatsapp.Manager
+	connEventRepo  *repository.ConnectionEventRepository
+	channels       *channels.Registry
+	access         AccessNotifier
+}
+
+// AccessNotifier avisa os clientes em tempo real que os inboxes de um usuario mudaram
+type AccessNotifier interface {
+	BroadcastAccessChanged(userID string)
 }
 
+// healthHistoryLimit eventos de conexao retornados com a saude do inbox
+const healthHistoryLimit = 20
+
 // NewInboxService cria novo servico
 func NewInboxService(
 	inboxRepo *repository.InboxRepository,
 	waChannelRepo *repository.ChannelWhatsAppRepository,
+	tgChannelRepo *repository.ChannelTelegramRepository,
+	apiChannelRepo *repository.ChannelAPIRepository,
 	memberRepo *repository.InboxMemberRepository,
-	waManager *whatsapp.Manager,
+	connEventRepo *repository.ConnectionEventRepository,
+	registry *channels.Registry,
 ) *InboxService {
 	return &InboxService{
-		inboxRepo:     inboxRepo,
-		waChannelRepo: waChannelRepo,
-		memberRepo:    memberRepo,
-		waManager:     waManager,
+		inboxRepo:      inboxRepo,
+		waChannelRepo:  waChannelRepo,
+		tgChannelRepo:  tgChannelRepo,
+		apiChannelRepo: apiChannelRepo,
+		memberRepo:     memberRepo,
+		connEventRepo:  connEventRepo,
+		channels:       registry,
 	}
 }
 
+// SetAccessNotifier define quem avisa os clientes conectados sobre mudancas de membros
+func (s *InboxService) SetAccessNotifier(n AccessNotifier) {
+	s.access = n
+}
+
 // Create cria um inbox
 func (s *InboxService) Create(ctx context.Context, req domain.CreateInboxRequest) (*domain.Inbox, error) {
+	region, err := phoneRegion(req.PhoneRegion)
+	if err != nil {
+		return nil, err
+	}
+
 	channelID := uuid.New().String()
 
 	// Criar canal especifico
 	switch req.ChannelType {
 	case ports.ChannelTypeWhatsApp:
 		channel := &domain.ChannelWhatsApp{
-			ID:       channelID,
-			Provider: "whatsmeow",
+			ID:             channelID,
+			Provider:       "whatsmeow",
+			ProviderConfig: whatsAppProviderConfig(req.ChannelConfig),
 		}
 		if err := s.waChannelRepo.Create(ctx, channel); err != nil {
 			return nil, fmt.Errorf("failed to create whatsapp channel: %w", err)
 		}
 
 	case ports.ChannelTypeTelegram:
-		// TODO: implementar canal telegram
-		return nil, fmt.Errorf("telegram channel not implemented yet")
+		token := req.ChannelConfig["bot_token"]
+		if token == "" {
+			return nil, fmt.Errorf("bot_token is required for telegram channel")
+		}
+		webhookSecret, err := tgpkg.GenerateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		channel := &domain.ChannelTelegram{
+			ID:            channelID,
+			BotToken:      token,
+			WebhookSecret: webhookSecret,
+		}
+		if err := s.tgChannelRepo.Create(ctx, channel); err != nil {
+			return nil, fmt.Errorf("failed to create telegram channel: %w", err)
+		}
 
 	case ports.ChannelTypeAPI:
-		// TODO: implementar canal API
-		return nil, fmt.Errorf("api channel not implemented yet")
+		webhookURL := req.ChannelConfig["webhook_url"]
+		if webhookURL == "" {
+			return nil, fmt.Errorf("webhook_url is required for api channel")
+		}
+		apiKey, err := apichannel.GenerateAPIKey()
+		if err != nil {
+			return nil, err
+		}
+		webhookSecret, err := apichannel.GenerateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		channel := &domain.ChannelAPI{
+			ID:            channelID,
+			WebhookURL:    webhookURL,
+			APIKey:        apiKey,
+			WebhookSecret: webhookSecret,
+		}
+		if err := s.apiChannelRepo.Create(ctx, channel); err != nil {
+			return nil, fmt.Errorf("failed to create api channel: %w", err)
+		}
 
 	default:
 		return nil, fmt.Errorf("unknown channel type: %s", req.ChannelType)
@@ -72,6 +146,7 @@ func (s *InboxService) Create(ctx context.Context, req domain.CreateInboxRequest
 		Status:          ports.ChannelStatusDisconnected,
 		GreetingMessage: req.GreetingMessage,
 		AutoAssignment:  req.AutoAssignment,
+		PhoneRegion:     region,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
@@ -80,9 +155,57 @@ func (s *InboxService) Create(ctx context.Context, req domain.CreateInboxRequest
 		return nil, fmt.Errorf("failed to create inbox: %w", err)
 	}
 
+	// Canal API nao tem sessao remota: fica disponivel imediatamente
+	if inbox.ChannelType == ports.ChannelTypeAPI {
+		if err := s.Connect(ctx, inbox.ID); err != nil {
+			log.Printf("Failed to enable api inbox %s: %v", inbox.ID, err)
+		}
+	}
+
 	return inbox, nil
 }
 
+// Update grava nome, saudacao, atribuicao automatica e regiao de telefone do inbox
+func (s *InboxService) Update(ctx context.Context, inbox *domain.Inbox) error {
+	region, err := phoneRegion(inbox.PhoneRegion)
+	if err != nil {
+		return err
+	}
+	inbox.PhoneRegion = region
+
+	if err := s.inboxRepo.Update(ctx, inbox); err != nil {
+		return fmt.Errorf("failed to update inbox: %w", err)
+	}
+	return nil
+}
+
+// phoneRegion valida a regiao de telefone do inbox (vazia usa a padrao do servidor)
+func phoneRegion(region string) (string, error) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if region != "" && !phone.ValidRegion(region) {
+		return "", fmt.Errorf("%w: %s", phone.ErrUnknownRegion, region)
+	}
+	return region, nil
+}
+
+// whatsAppProviderConfig extrai as opcoes do canal WhatsApp da criacao do inbox.
+// "history_import" ("true") habilita a importacao; "history_days" e "history_messages" limitam a profundidade.
+// "announce_online" ("true") marca o dispositivo como online para receber digitacao e presenca dos contatos.
+func whatsAppProviderConfig(config map[string]string) map[string]interface{} {
+	providerConfig := map[string]interface{}{}
+	for _, key := range []string{"history_import", "announce_online"} {
+		if enabled, err := strconv.ParseBool(config[key]); err == nil && enabled {
+			providerConfig[key] = true
+		}
+	}
+	for _, key := range []string{"history_days", "history_messages"} {
+		if n, err := strconv.Atoi(config[key]); err == nil && n > 0 {
+			providerConfig[key] = n
+		}
+	}
+	return providerConfig
+}
+
 // GetByID busca inbox por ID
 func (s *InboxService) GetByID(ctx context.Context, id string) (*domain.Inbox, error) {
 	inbox, err := s.inboxRepo.GetByID(ctx, id)
@@ -93,14 +216,39 @@ func (s *InboxService) GetByID(ctx context.Context, id string) (*domain.Inbox, e
 		return nil, fmt.Errorf("inbox not found")
 	}
 
-	// Atualizar status do manager
-	if inbox.ChannelType == ports.ChannelTypeWhatsApp && s.waManager != nil {
-		inbox.Status = s.waManager.Status(id)
-	}
+	// Atualizar status do registry
+	inbox.Status = s.channelStatus(inbox)
 
 	return inbox, nil
 }
 
+// GetWithChannel busca inbox com a configuracao do canal
+func (s *InboxService) GetWithChannel(ctx context.Context, id string) (*domain.InboxWithChannel, error) {
+	inbox, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.InboxWithChannel{Inbox: *inbox}
+
+	switch inbox.ChannelType {
+	case ports.ChannelTypeWhatsApp:
+		if channel, _ := s.waChannelRepo.GetByID(ctx, inbox.ChannelID); channel != nil {
+			result.Channel = channel
+		}
+	case ports.ChannelTypeTelegram:
+		if channel, _ := s.tgChannelRepo.GetByID(ctx, inbox.ChannelID); channel != nil {
+			result.Channel = channel
+		}
+	case ports.ChannelTypeAPI:
+		if channel, _ := s.apiChannelRepo.GetByID(ctx, inbox.ChannelID); channel != nil {
+			result.Channel = channel
+		}
+	}
+
+	return result, nil
+}
+
 // GetAll lista todos os inboxes
 func (s *InboxService) GetAll(ctx context.Context) ([]*domain.Inbox, error) {
 	inboxes, err := s.inboxRepo.GetAll(ctx)
@@ -108,11 +256,9 @@ func (s *InboxService) GetAll(ctx context.Context) ([]*domain.Inbox, error) {
 		return nil, fmt.Errorf("failed to list inboxes: %w", err)
 	}
 
-	// Atualizar status do manager
+	// Atualizar status do registry
 	for _, inbox := range inboxes {
-		if inbox.ChannelType == ports.ChannelTypeWhatsApp && s.waManager != nil {
-			inbox.Status = s.waManager.Status(inbox.ID)
-		}
+		inbox.Status = s.channelStatus(inbox)
 	}
 
 	return inboxes, nil
@@ -128,32 +274,24 @@ func (s *InboxService) Connect(ctx context.Context, inboxID string) error {
 		return fmt.Errorf("inbox not found")
 	}
 
-	switch inbox.ChannelType {
-	case ports.ChannelTypeWhatsApp:
-		if s.waManager == nil {
-			return fmt.Errorf("whatsapp manager not initialized")
-		}
-
-		// Buscar JID existente
-		channel, _ := s.waChannelRepo.GetByID(ctx, inbox.ChannelID)
-		jid := ""
-		if channel != nil {
-			jid = channel.JID
-		}
+	if s.channels == nil || !s.channels.Supports(inbox.ChannelType) {
+		return fmt.Errorf("channel type %s not supported", inbox.ChannelType)
+	}
 
-		// Atualizar status
-		if err := s.inboxRepo.UpdateStatus(ctx, inboxID, ports.ChannelStatusConnecting); err != nil {
-			log.Printf("Failed to update inbox status: %v", err)
-		}
+	config, err := s.channelConfig(ctx, inbox)
+	if err != nil {
+		return err
+	}
 
-		// Conectar
-		if err := s.waManager.Connect(ctx, inboxID, jid); err != nil {
-			s.inboxRepo.UpdateStatus(ctx, inboxID, ports.ChannelStatusDisconnected)
-			return fmt.Errorf("failed to connect: %w", err)
-		}
+	// Atualizar status
+	if err := s.inboxRepo.UpdateStatus(ctx, inboxID, ports.ChannelStatusConnecting); err != nil {
+		log.Printf("Failed to update inbox status: %v", err)
+	}
 
-	default:
-		return fmt.Errorf("channel type %s not supported", inbox.ChannelType)
+	// Conectar
+	if err := s.channels.Connect(ctx, inboxID, inbox.ChannelType, config); err != nil {
+		s.inboxRepo.UpdateStatus(ctx, inboxID, ports.ChannelStatusDisconnected)
+		return fmt.Errorf("failed to connect: %w", err)
 	}
 
 	return nil
@@ -169,12 +307,9 @@ func (s *InboxService) Disconnect(ctx context.Context, inboxID string) error {
 		return fmt.Errorf("inbox not found")
 	}
 
-	switch inbox.ChannelType {
-	case ports.ChannelTypeWhatsApp:
-		if s.waManager != nil {
-			if err := s.waManager.Disconnect(ctx, inboxID); err != nil {
-				return fmt.Errorf("failed to disconnect: %w", err)
-			}
+	if s.channels != nil {
+		if err := s.channels.Disconnect(ctx, inboxID); err != nil {
+			return fmt.Errorf("failed to disconnect: %w", err)
 		}
 	}
 
@@ -195,31 +330,106 @@ func (s *InboxService) Delete(ctx context.Context, inboxID string) error {
 		return fmt.Errorf("inbox not found")
 	}
 
-	// Desconectar e remover do manager
+	// Desconectar e remover do registry (com logout se o canal suportar)
+	if s.channels != nil {
+		channel, _ := s.channels.Get(inboxID)
+		if lo, ok := channel.(logouter); ok && channel.Status() == ports.ChannelStatusConnected {
+			if err := lo.Logout(ctx); err != nil {
+				log.Printf("Failed to logout inbox %s: %v", inboxID, err)
+			}
+		}
+		s.channels.Disconnect(ctx, inboxID)
+	}
+
+	// Remover configuracao do canal
 	switch inbox.ChannelType {
 	case ports.ChannelTypeWhatsApp:
-		if s.waManager != nil {
-			s.waManager.Remove(ctx, inboxID)
-		}
 		s.waChannelRepo.Delete(ctx, inbox.ChannelID)
+	case ports.ChannelTypeTelegram:
+		s.tgChannelRepo.Delete(ctx, inbox.ChannelID)
+	case ports.ChannelTypeAPI:
+		s.apiChannelRepo.Delete(ctx, inbox.ChannelID)
 	}
 
+	// Membros perdem o acesso junto com o inbox
+	members, _ := s.memberRepo.GetByInboxID(ctx, inboxID)
+
 	// Remover inbox
 	if err := s.inboxRepo.Delete(ctx, inboxID); err != nil {
 		return fmt.Errorf("failed to delete inbox: %w", err)
 	}
 
+	for _, userID := range members {
+		s.notifyAccess(userID)
+	}
 	return nil
 }
 
+// logouter canal que suporta encerrar a sessao remota (ex: WhatsApp)
+type logouter interface {
+	Logout(ctx context.Context) error
+}
+
 // GetStatus retorna status do inbox
 func (s *InboxService) GetStatus(inboxID string) ports.ChannelStatus {
-	if s.waManager != nil {
-		return s.waManager.Status(inboxID)
+	if s.channels != nil {
+		return s.channels.Status(inboxID)
 	}
 	return ports.ChannelStatusDisconnected
 }
 
+// channelStatus retorna status em memoria do canal, ou o salvo se nao houver registry
+func (s *InboxService) channelStatus(inbox *domain.Inbox) ports.ChannelStatus {
+	if s.channels == nil || !s.channels.Supports(inbox.ChannelType) {
+		return inbox.Status
+	}
+	return s.channels.Status(inbox.ID)
+}
+
+// channelConfig monta a configuracao passada a factory do canal
+func (s *InboxService) channelConfig(ctx context.Context, inbox *domain.Inbox) (map[string]interface{}, error) {
+	switch inbox.ChannelType {
+	case ports.ChannelTypeWhatsApp:
+		// JID vazio cria nova sessao (QR code)
+		channel, _ := s.waChannelRepo.GetByID(ctx, inbox.ChannelID)
+		config := map[string]interface{}{"jid": ""}
+		if channel != nil {
+			config["jid"] = channel.JID
+			// Importacao de historico e presenca online (opt-in) definidas no provider_config
+			for _, key := range []string{"history_import", "history_days", "history_messages", "announce_online"} {
+				if v, ok := channel.ProviderConfig[key]; ok {
+					config[key] = v
+				}
+			}
+		}
+		return config, nil
+
+	case ports.ChannelTypeTelegram:
+		channel, err := s.tgChannelRepo.GetByID(ctx, inbox.ChannelID)
+		if err != nil || channel == nil {
+			return nil, fmt.Errorf("telegram channel not found")
+		}
+		return map[string]interface{}{
+			"bot_token":      channel.BotToken,
+			"webhook_secret": channel.WebhookSecret,
+		}, nil
+
+	case ports.ChannelTypeAPI:
+		channel, err := s.apiChannelRepo.GetByID(ctx, inbox.ChannelID)
+		if err != nil || channel == nil {
+			return nil, fmt.Errorf("api channel not found")
+		}
+		return map[string]interface{}{
+			"webhook_url":    channel.WebhookURL,
+			"api_key":        channel.APIKey,
+			"webhook_secret": channel.WebhookSecret,
+		}, nil
+
+	default:
+		return map[string]interface{}{}, nil
+	}
+}
+
 // GetQRCode retorna QR code do inbox
 func (s *InboxService) GetQRCode(ctx context.Context, inboxID string) string {
 	// Primeiro verifica no banco
@@ -228,21 +438,89 @@ func (s *InboxService) GetQRCode(ctx context.Context, inboxID string) string {
 		return inbox.QRCode
 	}
 
-	// Fallback para o manager
-	if s.waManager != nil {
-		return s.waManager.GetQRCode(inboxID)
+	// Fallback para o canal em memoria
+	if s.channels != nil {
+		if channel, err := s.channels.Get(inboxID); err == nil {
+			return channel.GetQRCode()
+		}
 	}
 	return ""
 }
 
+// phonePairer canal que suporta pareamento por codigo de telefone (ex: WhatsApp)
+type phonePairer interface {
+	PairPhone(ctx context.Context, phone string) (string, error)
+}
+
+// PairPhone inicia login e retorna codigo de pareamento para o telefone,
+// alternativa ao QR code com as mesmas transicoes de status
+func (s *InboxService) PairPhone(ctx context.Context, inboxID, phone string) (string, error) {
+	inbox, err := s.inboxRepo.GetByID(ctx, inboxID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get inbox: %w", err)
+	}
+	if inbox == nil {
+		return "", fmt.Errorf("inbox not found")
+	}
+	if inbox.ChannelType != ports.ChannelTypeWhatsApp || s.channels == nil {
+		return "", ErrPairingNotSupported
+	}
+
+	// Reaproveita login em andamento; senao inicia um novo
+	channel, err := s.channels.Get(inboxID)
+	if err != nil || channel.Status() == ports.ChannelStatusDisconnected {
+		if err := s.Connect(ctx, inboxID); err != nil {
+			return "", err
+		}
+		if channel, err = s.channels.Get(inboxID); err != nil {
+			return "", fmt.Errorf("failed to get channel: %w", err)
+		}
+	}
+
+	pairer, ok := channel.(phonePairer)
+	if !ok {
+		return "", ErrPairingNotSupported
+	}
+
+	pairCtx, cancel := context.WithTimeout(ctx, pairCodeTimeout)
+	defer cancel()
+
+	code, err := pairer.PairPhone(pairCtx, phone)
+	if err != nil {
+		return "", err
+	}
+
+	// QR code deixa de valer enquanto o pareamento por codigo esta pendente
+	if err := s.inboxRepo.ClearQRCode(ctx, inboxID, ports.ChannelStatusQRCode); err != nil {
+		log.Printf("Failed to update inbox status: %v", err)
+	}
+
+	return code, nil
+}
+
 // AddMember adiciona membro ao inbox
 func (s *InboxService) AddMember(ctx context.Context, inboxID, userID string) error {
-	return s.memberRepo.Add(ctx, inboxID, userID)
+	if err := s.memberRepo.Add(ctx, inboxID, userID); err != nil {
+		return err
+	}
+	s.notifyAccess(userID)
+	return nil
 }
 
 // RemoveMember remove membro do inbox
 func (s *InboxService) RemoveMember(ctx context.Context, inboxID, userID string) error {
-	return s.memberRepo.Remove(ctx, inboxID, userID)
+	if err := s.memberRepo.Remove(ctx, inboxID, userID); err != nil {
+		return err
+	}
+	s.notifyAccess(userID)
+	return nil
+}
+
+// notifyAccess faz as conexoes em tempo real do usuario recarregarem seus inboxes
+func (s *InboxService) notifyAccess(userID string) {
+	if s.access != nil {
+		s.access.BroadcastAccessChanged(userID)
+	}
 }
 
 // GetMembers lista membros do inbox
@@ -250,20 +528,31 @@ func (s *InboxService) GetMembers(ctx context.Context, inboxID string) ([]string
 	return s.memberRepo.GetByInboxID(ctx, inboxID)
 }
 
+// GetUserInboxes lista os inboxes em que o usuario e membro
+func (s *InboxService) GetUserInboxes(ctx context.Context, userID string) ([]string, error) {
+	return s.memberRepo.GetInboxesByUserID(ctx, userID)
+}
+
 // OnConnected chamado quando canal conecta
 func (s *InboxService) OnConnected(ctx context.Context, inboxID, phone string) error {
 	if err := s.inboxRepo.ClearQRCode(ctx, inboxID, ports.ChannelStatusConnected); err != nil {
 		return err
 	}
+	s.recordConnectionEvent(ctx, inboxID, domain.ConnectionEventConnected, "", 0)
 
-	// Atualizar canal com telefone
+	// Atualizar canal com telefone (WhatsApp) ou username do bot (Telegram)
 	inbox, _ := s.inboxRepo.GetByID(ctx, inboxID)
-	if inbox != nil && inbox.ChannelType == ports.ChannelTypeWhatsApp {
-		adapter, _ := s.waManager.Get(inboxID)
-		if adapter != nil {
-			jid := adapter.GetJID()
-			s.waChannelRepo.UpdateJID(ctx, inbox.ChannelID, jid, phone)
+	if inbox == nil {
+		return nil
+	}
+	switch inbox.ChannelType {
+	case ports.ChannelTypeWhatsApp:
+		channel, _ := s.channels.Get(inboxID)
+		if wa, ok := channel.(interface{ GetJID() string }); ok {
+			s.waChannelRepo.UpdateJID(ctx, inbox.ChannelID, wa.GetJID(), phone)
 		}
+	case ports.ChannelTypeTelegram:
+		s.tgChannelRepo.UpdateUsername(ctx, inbox.ChannelID, phone)
 	}
 
 	return nil
@@ -271,32 +560,127 @@ func (s *InboxService) OnConnected(ctx context.Context, inboxID, phone string) e
 
 // OnDisconnected chamado quando canal desconecta
 func (s *InboxService) OnDisconnected(ctx context.Context, inboxID string) error {
+	s.recordConnectionEvent(ctx, inboxID, domain.ConnectionEventDisconnected, "", 0)
 	return s.inboxRepo.UpdateStatus(ctx, inboxID, ports.ChannelStatusDisconnected)
 }
 
+// OnReconnecting chamado quando o canal agenda nova tentativa de conexao
+func (s *InboxService) OnReconnecting(ctx context.Context, inboxID string, attempt int, retryIn time.Duration, cause error) error {
+	reason := fmt.Sprintf("retry in %s", retryIn.Round(time.Second))
+	if cause != nil {
+		reason = fmt.Sprintf("%v; %s", cause, reason)
+	}
+	s.recordConnectionEvent(ctx, inboxID, domain.ConnectionEventReconnecting, reason, attempt)
+	return s.inboxRepo.UpdateStatus(ctx, inboxID, ports.ChannelStatusConnecting)
+}
+
+// OnLoggedOut chamado quando a sessao e encerrada no aparelho.
+// A sessao salva deixa de valer: o inbox precisa ser pareado novamente.
+func (s *InboxService) OnLoggedOut(ctx context.Context, inboxID string) error {
+	s.recordConnectionEvent(ctx, inboxID, domain.ConnectionEventLoggedOut, "session removed on device", 0)
+
+	inbox, err := s.inboxRepo.GetByID(ctx, inboxID)
+	if err != nil {
+		return fmt.Errorf("failed to get inbox: %w", err)
+	}
+	if inbox != nil && inbox.ChannelType == ports.ChannelTypeWhatsApp {
+		if err := s.waChannelRepo.UpdateJID(ctx, inbox.ChannelID, "", ""); err != nil {
+			log.Printf("Failed to clear session of inbox %s: %v", inboxID, err)
+		}
+	}
+
+	// Cliente com sessao apagada nao pode ser reaproveitado
+	if s.channels != nil {
+		s.channels.Unregister(inboxID)
+	}
+
+	return s.inboxRepo.ClearQRCode(ctx, inboxID, ports.ChannelStatusDisconnected)
+}
+
+// GetHealth retorna a saude da conexao do inbox e o historico recente
+func (s *InboxService) GetHealth(ctx context.Context, inboxID string) (*domain.InboxHealth, error) {
+	inbox, err := s.inboxRepo.GetByID(ctx, inboxID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inbox: %w", err)
+	}
+	if inbox == nil {
+		return nil, fmt.Errorf("inbox not found")
+	}
+
+	health := &domain.InboxHealth{
+		InboxID:       inboxID,
+		ChannelHealth: ports.ChannelHealth{Status: s.channelStatus(inbox)},
+	}
+	if s.channels != nil {
+		if channel, err := s.channels.Get(inboxID); err == nil {
+			if hc, ok := channel.(ports.HealthChannel); ok {
+				health.ChannelHealth = hc.Health()
+			}
+		}
+	}
+
+	if s.connEventRepo != nil {
+		history, err := s.connEventRepo.ListByInbox(ctx, inboxID, healthHistoryLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list connection events: %w", err)
+		}
+		health.History = history
+	}
+	return health, nil
+}
+
+// recordConnectionEvent grava o historico de conexao; falhas nao interrompem o fluxo
+func (s *InboxService) recordConnectionEvent(ctx context.Context, inboxID string, event domain.ConnectionEventType, reason string, attempt int) {
+	if s.connEventRepo == nil {
+		return
+	}
+	if err := s.connEventRepo.Create(ctx, &domain.ConnectionEvent{
+		InboxID: inboxID,
+		Event:   event,
+		Reason:  reason,
+		Attempt: attempt,
+	}); err != nil {
+		log.Printf("Failed to record connection event for inbox %s: %v", inboxID, err)
+	}
+}
+
 // OnQRCode chamado quando QR code e gerado
 func (s *InboxService) OnQRCode(ctx context.Context, inboxID, qrCode, base64Image string) error {
 	return s.inboxRepo.SetQRCode(ctx, inboxID, base64Image)
 }
 
-// RestoreConnections restaura conexoes WhatsApp
+// RestoreConnections restaura conexoes de todos os canais
 func (s *InboxService) RestoreConnections(ctx context.Context) error {
-	if s.waManager == nil {
+	if s.channels == nil {
 		return nil
 	}
 
-	items, err := s.inboxRepo.GetAllWhatsAppForRestore(ctx)
+	inboxes, err := s.inboxRepo.GetAll(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get inboxes for restore: %w", err)
 	}
 
-	var inboxes []whatsapp.InboxInfo
-	for _, item := range items {
-		inboxes = append(inboxes, whatsapp.InboxInfo{
-			ID:  item.ID,
-			JID: item.JID,
-		})
+	for _, inbox := range inboxes {
+		if !s.channels.Supports(inbox.ChannelType) {
+			continue
+		}
+
+		config, err := s.channelConfig(ctx, inbox)
+		if err != nil {
+			log.Printf("[Inbox] Failed to load channel for %s: %v", inbox.ID, err)
+			continue
+		}
+
+		// WhatsApp sem sessao pareada precisa de QR code: nao restaurar
+		if inbox.ChannelType == ports.ChannelTypeWhatsApp && channels.ConfigString(config, "jid") == "" {
+			continue
+		}
+
+		log.Printf("[Inbox] Restoring %s connection for inbox %s", inbox.ChannelType, inbox.ID)
+		if err := s.channels.Connect(ctx, inbox.ID, inbox.ChannelType, config); err != nil {
+			log.Printf("[Inbox] Failed to restore %s: %v", inbox.ID, err)
+		}
 	}
 
-	return s.waManager.RestoreConnections(ctx, inboxes)
+	return nil
 }
@@ -2,15 +2,20 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/zyntra/backend/internal/domain"
 	"github.com/zyntra/backend/internal/ports"
 	"github.com/zyntra/backend/internal/repository"
-	"github.com/zyntra/backend/internal/channels/whatsapp"
+	phonepkg "github.com/zyntra/backend/pkg/phone"
+	"github.com/zyntra/backend/pkg/storage"
 )
 
 // MessageService servico de mensagens
@@ -20,14 +25,46 @@ type MessageService struct {
 	contactRepo      *repository.ContactRepository
 	contactInboxRepo *repository.ContactInboxRepository
 	inboxRepo        *repository.InboxRepository
-	waManager        *whatsapp.Manager
+	attachmentRepo   *repository.AttachmentRepository
+	notificationRepo *repository.NotificationRepository
+	reactionRepo     *repository.ReactionRepository
+	storage          *storage.Store
+	channels         ports.ChannelManager
 	broadcaster      EventBroadcaster
+	outbound         OutboundPublisher
+	groups           *GroupService
+	profiles         *ContactProfileService
 }
 
+// ErrInvalidMessage mensagem rejeitada na validacao
+var ErrInvalidMessage = errors.New("invalid message")
+
+// ErrMessageNotFound mensagem nao encontrada
+var ErrMessageNotFound = errors.New("message not found")
+
+// ErrMessageForbidden usuario sem permissao para alterar a mensagem
+var ErrMessageForbidden = errors.New("not allowed to change this message")
+
+// ErrPresenceNotSupported canal nao exibe digitacao ao contato
+var ErrPresenceNotSupported = errors.New("channel does not support chat presence")
+
+// maxReadReceipts limite de mensagens confirmadas como lidas por vez
+const maxReadReceipts = 500
+
+// maxReactionLength tamanho maximo (em caracteres) de uma reacao
+const maxReactionLength = 32
+
 // EventBroadcaster interface para broadcast de eventos
 type EventBroadcaster interface {
 	BroadcastMessage(inboxID string, msg *domain.Message)
 	BroadcastConversationUpdate(inboxID string, conv *domain.Conversation)
+	BroadcastMessageUpdate(inboxID string, msg *domain.Message)
+	BroadcastMessageDeleted(inboxID string, msg *domain.Message)
+	BroadcastReaction(inboxID string, reaction *domain.Reaction)
+	BroadcastNotification(n *domain.Notification)
+	BroadcastHistoryProgress(inboxID string, progress *domain.HistoryImportProgress)
+	BroadcastTyping(inboxID string, typing *domain.TypingIndicator)
+	BroadcastPresence(inboxID string, presence *domain.ContactPresence)
 }
 
 // NewMessageService cria novo servico
@@ -37,7 +74,11 @@ func NewMessageService(
 	contactRepo *repository.ContactRepository,
 	contactInboxRepo *repository.ContactInboxRepository,
 	inboxRepo *repository.InboxRepository,
-	waManager *whatsapp.Manager,
+	attachmentRepo *repository.AttachmentRepository,
+	notificationRepo *repository.NotificationRepository,
+	reactionRepo *repository.ReactionRepository,
+	store *storage.Store,
+	channels ports.ChannelManager,
 ) *MessageService {
 	return &MessageService{
 		messageRepo:      messageRepo,
@@ -45,7 +86,11 @@ func NewMessageService(
 		contactRepo:      contactRepo,
 		contactInboxRepo: contactInboxRepo,
 		inboxRepo:        inboxRepo,
-		waManager:        waManager,
+		attachmentRepo:   attachmentRepo,
+		notificationRepo: notificationRepo,
+		reactionRepo:     reactionRepo,
+		storage:          store,
+		channels:         channels,
 	}
 }
 
@@ -54,7 +99,23 @@ func (s *MessageService) SetBroadcaster(b EventBroadcaster) {
 	s.broadcaster = b
 }
 
-// SendMessage envia uma mensagem
+// SetOutboundQueue define a fila de envio; sem fila o envio e sincrono
+func (s *MessageService) SetOutboundQueue(q OutboundPublisher) {
+	s.outbound = q
+}
+
+// SetGroupService define o servico de grupos usado nas mensagens de grupo
+func (s *MessageService) SetGroupService(g *GroupService) {
+	s.groups = g
+}
+
+// SetProfileService define o servico que sincroniza foto e perfil dos contatos novos
+func (s *MessageService) SetProfileService(p *ContactProfileService) {
+	s.profiles = p
+}
+
+// SendMessage envia uma mensagem.
+// Notas privadas sao apenas gravadas e transmitidas aos agentes, nunca ao canal.
 func (s *MessageService) SendMessage(ctx context.Context, conversationID string, req domain.SendMessageRequest, senderID string) (*domain.Message, error) {
 	// Buscar conversa
 	conv, err := s.conversationRepo.GetByID(ctx, conversationID)
@@ -62,9 +123,8 @@ func (s *MessageService) SendMessage(ctx context.Context, conversationID string,
 		return nil, fmt.Errorf("conversation not found")
 	}
 
-	// Buscar contact_inbox para obter source_id (telefone/JID)
-	contactInbox, err := s.contactInboxRepo.GetByID(ctx, conv.ContactInboxID)
-	if err != nil || contactInbox == nil {
+	// Validar contact_inbox (destino usado na entrega)
+	if ci, err := s.contactInboxRepo.GetByID(ctx, conv.ContactInboxID); err != nil || ci == nil {
 		return nil, fmt.Errorf("contact inbox not found")
 	}
 
@@ -74,24 +134,39 @@ func (s *MessageService) SendMessage(ctx context.Context, conversationID string,
 		return nil, fmt.Errorf("inbox not found")
 	}
 
-	var sourceID string
+	// Validar anexos (e baixar os informados por URL) antes de persistir
+	prepared, err := prepareAttachments(ctx, req.Attachments, s.storage.MaxSize())
+	if err != nil {
+		return nil, err
+	}
+	if err := validateStructuredContent(req, len(prepared)); err != nil {
+		return nil, err
+	}
+	if req.Content == "" && len(prepared) == 0 && req.Location == nil && req.Contact == nil {
+		return nil, fmt.Errorf("content, attachments, location or contact required")
+	}
 
-	// Enviar pelo canal apropriado
-	switch inbox.ChannelType {
-	case ports.ChannelTypeWhatsApp:
-		if s.waManager == nil {
-			return nil, fmt.Errorf("whatsapp manager not initialized")
+	mentions := uniqueMentions(req.Mentions, senderID)
+	if len(mentions) > 0 && !req.Private {
+		return nil, fmt.Errorf("%w: mentions are only allowed in private notes", ErrInvalidMessage)
+	}
+
+	// Resposta deve citar mensagem da mesma conversa
+	var parent *domain.Message
+	if req.InReplyToID != "" {
+		if _, err := uuid.Parse(req.InReplyToID); err != nil {
+			return nil, fmt.Errorf("%w: invalid in_reply_to_id", ErrInvalidMessage)
 		}
-		sourceID, err = s.waManager.SendText(ctx, inbox.ID, contactInbox.SourceID, req.Content)
+		parent, err = s.messageRepo.GetByID(ctx, req.InReplyToID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to send message: %w", err)
+			return nil, fmt.Errorf("failed to get replied message: %w", err)
+		}
+		if parent == nil || parent.ConversationID != conversationID {
+			return nil, fmt.Errorf("%w: replied message not found in conversation", ErrInvalidMessage)
 		}
-
-	default:
-		return nil, fmt.Errorf("channel type %s not supported for sending", inbox.ChannelType)
 	}
 
-	// Criar mensagem no banco
+	// Persistir como pendente antes de enviar ao canal
 	msg := &domain.Message{
 		ID:             uuid.New().String(),
 		ConversationID: conversationID,
@@ -100,18 +175,50 @@ func (s *MessageService) SendMessage(ctx context.Context, conversationID string,
 		SenderID:       &senderID,
 		Content:        req.Content,
 		ContentType:    req.ContentType,
-		SourceID:       sourceID,
-		Status:         ports.MessageStatusSent,
+		Status:         ports.MessageStatusPending,
 		Private:        req.Private,
 		CreatedAt:      time.Now(),
 	}
+	if msg.Private {
+		msg.Status = ports.MessageStatusSent
+	}
+	if parent != nil {
+		msg.InReplyToID = &parent.ID
+	}
 
+	attrs := make(map[string]interface{})
+	if len(mentions) > 0 {
+		attrs["mentions"] = mentions
+	}
+	if req.Voice {
+		attrs["voice"] = true
+	}
+	switch {
+	case req.Location != nil:
+		msg.ContentType = domain.ContentTypeLocation
+		attrs["location"] = req.Location
+	case req.Contact != nil:
+		msg.ContentType = domain.ContentTypeContact
+		attrs["contact"] = req.Contact
+	}
+	if len(attrs) > 0 {
+		msg.ContentAttributes = attrs
+	}
+
+	if len(prepared) > 0 && (msg.ContentType == "" || msg.ContentType == domain.ContentTypeText) {
+		msg.ContentType = domain.ContentType(prepared[0].attachment.FileType)
+	}
 	if msg.ContentType == "" {
 		msg.ContentType = domain.ContentTypeText
 	}
 
 	if err := s.messageRepo.Create(ctx, msg); err != nil {
-		log.Printf("Failed to save message: %v", err)
+		return nil, fmt.Errorf("failed to save message: %w", err)
+	}
+
+	if err := s.saveAttachments(ctx, msg, prepared); err != nil {
+		s.messageRepo.Delete(ctx, msg.ID)
+		return nil, err
 	}
 
 	// Atualizar conversa
@@ -124,29 +231,658 @@ func (s *MessageService) SendMessage(ctx context.Context, conversationID string,
 		s.broadcaster.BroadcastMessage(inbox.ID, msg)
 	}
 
+	// Nota privada: notificar mencionados e nao entregar ao canal
+	if msg.Private {
+		s.notifyMentions(ctx, msg, mentions)
+		return msg, nil
+	}
+
+	// Enfileirar entrega; sem fila (NATS indisponivel) tenta enviar uma vez
+	job := OutboundJob{MessageID: msg.ID, InboxID: inbox.ID}
+	if s.outbound != nil {
+		err := s.outbound.Enqueue(ctx, job)
+		if err == nil {
+			return msg, nil
+		}
+		log.Printf("[MessageService] Failed to enqueue message %s, sending directly: %v", msg.ID, err)
+	}
+
+	if err := s.DeliverOutbound(ctx, msg.ID); err != nil {
+		s.FailOutbound(ctx, msg.ID, err)
+	}
+
+	if updated, err := s.messageRepo.GetByID(ctx, msg.ID); err == nil && updated != nil {
+		updated.Attachments = msg.Attachments
+		msg = updated
+	}
+	return msg, nil
+}
+
+// DeliverOutbound envia ao canal uma mensagem pendente.
+// Mensagens ja processadas sao ignoradas, tornando a reentrega segura.
+func (s *MessageService) DeliverOutbound(ctx context.Context, messageID string) error {
+	msg, err := s.messageRepo.GetByID(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to get message: %w", err)
+	}
+	if msg == nil || msg.Status != ports.MessageStatusPending {
+		return nil
+	}
+	if msg.Private {
+		log.Printf("[MessageService] Refusing to deliver private note %s", msg.ID)
+		return nil
+	}
+	if msg.DeletedAt != nil {
+		return nil
+	}
+
+	channel, to, err := s.channelTarget(ctx, msg)
+	if err != nil {
+		return err
+	}
+
+	attachments, err := s.loadAttachments(ctx, msg.ID)
+	if err != nil {
+		return err
+	}
+	msg.Attachments = attachments
+
+	// MessageID estavel permite ao canal descartar retentativas da fila ja entregues
+	opts := s.sendOptions(ctx, msg, to)
+	opts.MessageID = msg.ID
+	sourceID, err := s.sendToChannel(ctx, channel, to, msg, opts)
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	if err := s.messageRepo.MarkSent(ctx, msg.ID, sourceID); err != nil {
+		log.Printf("[MessageService] Failed to mark message %s as sent: %v", msg.ID, err)
+	}
+
+	msg.Status = ports.MessageStatusSent
+	msg.SourceID = sourceID
+	delete(msg.ContentAttributes, "error")
+	if s.broadcaster != nil {
+		s.broadcaster.BroadcastMessage(msg.InboxID, msg)
+	}
+
+	return nil
+}
+
+// channelTarget retorna o canal conectado do inbox e o destino da conversa da mensagem
+func (s *MessageService) channelTarget(ctx context.Context, msg *domain.Message) (ports.Channel, string, error) {
+	conv, err := s.conversationRepo.GetByID(ctx, msg.ConversationID)
+	if err != nil || conv == nil {
+		return nil, "", fmt.Errorf("conversation not found")
+	}
+	return s.conversationTarget(ctx, conv)
+}
+
+// conversationTarget retorna o canal conectado do inbox e o destino da conversa
+func (s *MessageService) conversationTarget(ctx context.Context, conv *domain.Conversation) (ports.Channel, string, error) {
+	contactInbox, err := s.contactInboxRepo.GetByID(ctx, conv.ContactInboxID)
+	if err != nil || contactInbox == nil {
+		return nil, "", fmt.Errorf("contact inbox not found")
+	}
+
+	// Enviar pelo canal registrado para o inbox
+	if s.channels == nil {
+		return nil, "", fmt.Errorf("channel manager not initialized")
+	}
+	channel, err := s.channels.Get(conv.InboxID)
+	if err != nil || channel.Status() != ports.ChannelStatusConnected {
+		return nil, "", fmt.Errorf("inbox %s not connected", conv.InboxID)
+	}
+
+	return channel, contactInbox.SourceID, nil
+}
+
+// sendOptions monta a citacao da mensagem respondida, se ela existir no canal
+func (s *MessageService) sendOptions(ctx context.Context, msg *domain.Message, to string) ports.SendOptions {
+	if msg.InReplyToID == nil {
+		return ports.SendOptions{}
+	}
+	parent, err := s.messageRepo.GetByID(ctx, *msg.InReplyToID)
+	if err != nil || parent == nil || parent.SourceID == "" || parent.DeletedAt != nil {
+		return ports.SendOptions{}
+	}
+	ref := messageRef(parent, to)
+	return ports.SendOptions{ReplyTo: &ref}
+}
+
+// messageRef referencia a mensagem no canal; to e o destino da conversa.
+// Em grupos o remetente e o participante gravado na mensagem.
+func messageRef(msg *domain.Message, to string) ports.MessageRef {
+	sender := to
+	if participant, ok := msg.ContentAttributes["participant"].(string); ok && participant != "" {
+		sender = participant
+	}
+	return ports.MessageRef{
+		SourceID: msg.SourceID,
+		FromMe:   msg.SenderType != domain.SenderTypeContact,
+		Sender:   sender,
+		Content:  msg.Content,
+	}
+}
+
+// ReactToMessage define a reacao do agente na mensagem; emoji vazio remove.
+// Em notas privadas a reacao e apenas local.
+func (s *MessageService) ReactToMessage(ctx context.Context, messageID, userID, emoji string) (*domain.Reaction, error) {
+	msg, err := s.messageRepo.GetByID(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	if msg == nil {
+		return nil, ErrMessageNotFound
+	}
+	if msg.DeletedAt != nil {
+		return nil, fmt.Errorf("%w: message was deleted", ErrInvalidMessage)
+	}
+
+	emoji = strings.TrimSpace(emoji)
+	if utf8.RuneCountInString(emoji) > maxReactionLength {
+		return nil, fmt.Errorf("%w: reaction is too long", ErrInvalidMessage)
+	}
+
+	if !msg.Private {
+		if msg.SourceID == "" {
+			return nil, fmt.Errorf("%w: message was not delivered to the channel", ErrInvalidMessage)
+		}
+		channel, to, err := s.channelTarget(ctx, msg)
+		if err != nil {
+			return nil, err
+		}
+		if err := channel.SendReaction(ctx, to, messageRef(msg, to), emoji); err != nil {
+			return nil, fmt.Errorf("failed to send reaction: %w", err)
+		}
+	}
+
+	reaction := &domain.Reaction{
+		ID:             uuid.New().String(),
+		MessageID:      msg.ID,
+		ConversationID: msg.ConversationID,
+		SenderType:     domain.SenderTypeUser,
+		SenderID:       userID,
+		Emoji:          emoji,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.saveReaction(ctx, reaction); err != nil {
+		return nil, err
+	}
+
+	if s.broadcaster != nil {
+		s.broadcaster.BroadcastReaction(msg.InboxID, reaction)
+	}
+	return reaction, nil
+}
+
+// EditMessage altera o texto de uma mensagem enviada pelo proprio agente
+func (s *MessageService) EditMessage(ctx context.Context, messageID, userID, content string) (*domain.Message, error) {
+	msg, err := s.messageRepo.GetByID(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	if msg == nil {
+		return nil, ErrMessageNotFound
+	}
+
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, fmt.Errorf("%w: content is required", ErrInvalidMessage)
+	}
+	if err := canEditMessage(msg, userID); err != nil {
+		return nil, err
+	}
+	switch {
+	case msg.DeletedAt != nil:
+		return nil, fmt.Errorf("%w: message was deleted", ErrInvalidMessage)
+	case msg.ContentType != domain.ContentTypeText:
+		return nil, fmt.Errorf("%w: only text messages can be edited", ErrInvalidMessage)
+	}
+
+	if !msg.Private {
+		if msg.SourceID == "" {
+			return nil, fmt.Errorf("%w: message was not delivered to the channel", ErrInvalidMessage)
+		}
+		channel, to, err := s.channelTarget(ctx, msg)
+		if err != nil {
+			return nil, err
+		}
+		if err := channel.EditMessage(ctx, to, messageRef(msg, to), content); err != nil {
+			return nil, fmt.Errorf("failed to edit message: %w", err)
+		}
+	}
+
+	if err := s.messageRepo.UpdateContent(ctx, msg.ID, content); err != nil {
+		return nil, fmt.Errorf("failed to update message: %w", err)
+	}
+
+	now := time.Now()
+	msg.Content = content
+	msg.EditedAt = &now
+	if s.broadcaster != nil {
+		s.broadcaster.BroadcastMessageUpdate(msg.InboxID, msg)
+	}
+	return msg, nil
+}
+
+// DeleteMessage apaga mensagem de agente para todos (no canal, se ja entregue).
+// Apenas o autor apaga a propria mensagem; admin apaga a de qualquer agente.
+func (s *MessageService) DeleteMessage(ctx context.Context, messageID, userID string, admin bool) (*domain.Message, error) {
+	msg, err := s.messageRepo.GetByID(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	if msg == nil {
+		return nil, ErrMessageNotFound
+	}
+	if err := canDeleteMessage(msg, userID, admin); err != nil {
+		return nil, err
+	}
+	if msg.DeletedAt != nil {
+		return msg, nil
+	}
+
+	if !msg.Private && msg.SourceID != "" {
+		channel, to, err := s.channelTarget(ctx, msg)
+		if err != nil {
+			return nil, err
+		}
+		if err := channel.DeleteMessage(ctx, to, messageRef(msg, to)); err != nil {
+			return nil, fmt.Errorf("failed to delete message: %w", err)
+		}
+	}
+
+	if err := s.messageRepo.MarkDeleted(ctx, msg.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete message: %w", err)
+	}
+
+	log.Printf("[MessageService] Message %s deleted by %s", msg.ID, userID)
+	s.markDeleted(msg)
 	return msg, nil
 }
 
+// canEditMessage verifica se o usuario pode editar a mensagem (apenas o autor)
+func canEditMessage(msg *domain.Message, userID string) error {
+	switch {
+	case msg.SenderType != domain.SenderTypeUser:
+		return fmt.Errorf("%w: only agent messages can be edited", ErrInvalidMessage)
+	case msg.SenderID == nil || *msg.SenderID != userID:
+		return fmt.Errorf("%w: only the author can edit a message", ErrMessageForbidden)
+	}
+	return nil
+}
+
+// canDeleteMessage verifica se o usuario pode apagar a mensagem
+func canDeleteMessage(msg *domain.Message, userID string, admin bool) error {
+	switch {
+	case msg.SenderType != domain.SenderTypeUser:
+		return fmt.Errorf("%w: only agent messages can be deleted", ErrInvalidMessage)
+	case admin:
+		return nil
+	case msg.SenderID == nil || *msg.SenderID != userID:
+		return fmt.Errorf("%w: only the author can delete a message", ErrMessageForbidden)
+	}
+	return nil
+}
+
+// saveReaction grava a reacao ou a remove quando o emoji e vazio
+func (s *MessageService) saveReaction(ctx context.Context, reaction *domain.Reaction) error {
+	if reaction.Emoji == "" {
+		if err := s.reactionRepo.Delete(ctx, reaction.MessageID, reaction.SenderType, reaction.SenderID); err != nil {
+			return fmt.Errorf("failed to delete reaction: %w", err)
+		}
+		return nil
+	}
+	if err := s.reactionRepo.Upsert(ctx, reaction); err != nil {
+		return fmt.Errorf("failed to save reaction: %w", err)
+	}
+	return nil
+}
+
+// markDeleted limpa a mensagem apagada e transmite a remocao
+func (s *MessageService) markDeleted(msg *domain.Message) {
+	now := time.Now()
+	msg.Content = ""
+	msg.ContentAttributes = nil
+	msg.Attachments = nil
+	msg.DeletedAt = &now
+	if s.broadcaster != nil {
+		s.broadcaster.BroadcastMessageDeleted(msg.InboxID, msg)
+	}
+}
+
+// notifyMentions cria e transmite notificacoes dos usuarios mencionados na nota.
+// Mencionados sem acesso ao inbox nao sao notificados.
+func (s *MessageService) notifyMentions(ctx context.Context, msg *domain.Message, userIDs []string) {
+	if len(userIDs) == 0 || s.notificationRepo == nil {
+		return
+	}
+
+	var actorID *string
+	if msg.SenderID != nil && *msg.SenderID != "" {
+		actorID = msg.SenderID
+	}
+
+	notifications, err := s.notificationRepo.CreateMentions(ctx, userIDs, msg.InboxID, msg.ConversationID, msg.ID, actorID)
+	if err != nil {
+		log.Printf("[MessageService] Failed to create mention notifications for %s: %v", msg.ID, err)
+		return
+	}
+	if s.broadcaster == nil {
+		return
+	}
+	for _, n := range notifications {
+		s.broadcaster.BroadcastNotification(n)
+	}
+}
+
+// uniqueMentions remove duplicados, vazios e o proprio autor da lista de mencoes
+func uniqueMentions(userIDs []string, senderID string) []string {
+	seen := make(map[string]bool, len(userIDs))
+	var result []string
+	for _, id := range userIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || id == senderID || seen[id] {
+			continue
+		}
+		if _, err := uuid.Parse(id); err != nil {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
+
+// FailOutbound marca mensagem como falha definitiva
+func (s *MessageService) FailOutbound(ctx context.Context, messageID string, cause error) {
+	if err := s.messageRepo.MarkFailed(ctx, messageID, cause.Error()); err != nil {
+		log.Printf("[MessageService] Failed to mark message %s as failed: %v", messageID, err)
+		return
+	}
+
+	msg, err := s.messageRepo.GetByID(ctx, messageID)
+	if err == nil && msg != nil && s.broadcaster != nil {
+		msg.Attachments, _ = s.loadAttachments(ctx, msg.ID)
+		s.broadcaster.BroadcastMessage(msg.InboxID, msg)
+	}
+}
+
+// FailExpiredOutbound marca como failed as mensagens ainda pendentes criadas antes de before.
+// Se o job for consumido depois, DeliverOutbound ignora a mensagem que ja nao esta pendente.
+func (s *MessageService) FailExpiredOutbound(ctx context.Context, before time.Time, limit int) {
+	ids, err := s.messageRepo.ListPendingBefore(ctx, before, limit)
+	if err != nil {
+		log.Printf("[MessageService] Failed to list expired outbound messages: %v", err)
+		return
+	}
+	for _, id := range ids {
+		log.Printf("[MessageService] Message %s expired before delivery", id)
+		s.FailOutbound(ctx, id, ErrOutboundExpired)
+	}
+}
+
+// saveAttachments grava o conteudo no storage e registra os anexos da mensagem
+func (s *MessageService) saveAttachments(ctx context.Context, msg *domain.Message, prepared []preparedAttachment) error {
+	for _, p := range prepared {
+		obj, err := s.storage.Save(ctx, p.data, p.attachment.MimeType)
+		if errors.Is(err, storage.ErrTooLarge) {
+			return fmt.Errorf("%w: %v", ErrInvalidAttachment, err)
+		}
+		if err != nil {
+			return err
+		}
+
+		att := p.attachment
+		att.ID = uuid.New().String()
+		att.MessageID = msg.ID
+		att.FileURL = obj.Key
+		att.CreatedAt = time.Now()
+
+		if err := s.attachmentRepo.Create(ctx, &att); err != nil {
+			return fmt.Errorf("failed to save attachment: %w", err)
+		}
+		msg.Attachments = append(msg.Attachments, &att)
+	}
+
+	s.signAttachments(ctx, msg.Attachments)
+	return nil
+}
+
+// loadAttachments busca anexos da mensagem com URLs de download
+func (s *MessageService) loadAttachments(ctx context.Context, messageID string) ([]*domain.Attachment, error) {
+	attachments, err := s.attachmentRepo.GetByMessageID(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	s.signAttachments(ctx, attachments)
+	return attachments, nil
+}
+
+// signAttachments preenche a URL assinada (expiravel) de download
+func (s *MessageService) signAttachments(ctx context.Context, attachments []*domain.Attachment) {
+	for _, att := range attachments {
+		url, err := s.storage.URL(ctx, att.FileURL, att.FileName)
+		if err != nil {
+			log.Printf("[MessageService] Failed to sign attachment %s: %v", att.ID, err)
+			continue
+		}
+		att.DownloadURL = url
+	}
+}
+
+// MaxAttachmentSize retorna tamanho maximo aceito para anexos
+func (s *MessageService) MaxAttachmentSize() int64 {
+	return s.storage.MaxSize()
+}
+
+// GetAttachment retorna um anexo e seu conteudo
+func (s *MessageService) GetAttachment(ctx context.Context, id string) (*domain.Attachment, []byte, error) {
+	att, err := s.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	if att == nil {
+		return nil, nil, nil
+	}
+
+	data, err := s.storage.Load(ctx, att.FileURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return att, data, nil
+}
+
+// sendToChannel envia texto e anexos; retorna o source_id da ultima mensagem enviada
+// A citacao (opts) acompanha apenas a primeira mensagem enviada; cada envio e uma parte.
+func (s *MessageService) sendToChannel(ctx context.Context, channel ports.Channel, to string, msg *domain.Message, opts ports.SendOptions) (string, error) {
+	switch msg.ContentType {
+	case domain.ContentTypeLocation:
+		var location ports.Location
+		if !contentAttribute(msg, "location", &location) {
+			return "", fmt.Errorf("message %s has no location", msg.ID)
+		}
+		if err := sendLeadingText(ctx, channel, to, msg.Content, &opts); err != nil {
+			return "", err
+		}
+		return channel.SendLocation(ctx, to, location, opts)
+
+	case domain.ContentTypeContact:
+		var contact ports.ContactCard
+		if !contentAttribute(msg, "contact", &contact) {
+			return "", fmt.Errorf("message %s has no contact", msg.ID)
+		}
+		if err := sendLeadingText(ctx, channel, to, msg.Content, &opts); err != nil {
+			return "", err
+		}
+		return channel.SendContact(ctx, to, contact, opts)
+	}
+
+	if len(msg.Attachments) == 0 {
+		return channel.SendText(ctx, to, msg.Content, opts)
+	}
+
+	// Audio e sticker nao aceitam legenda: texto vai em mensagem separada
+	caption := msg.Content
+	if first := ports.MediaType(msg.Attachments[0].FileType); first == ports.MediaTypeAudio || first == ports.MediaTypeSticker {
+		if err := sendLeadingText(ctx, channel, to, caption, &opts); err != nil {
+			return "", err
+		}
+		caption = ""
+	}
+	voice, _ := msg.ContentAttributes["voice"].(bool)
+
+	var sourceID string
+	for _, att := range msg.Attachments {
+		data, err := s.storage.Load(ctx, att.FileURL)
+		if err != nil {
+			return "", err
+		}
+
+		sourceID, err = channel.SendMedia(ctx, to, ports.Media{
+			Type:     ports.MediaType(att.FileType),
+			Data:     data,
+			MimeType: att.MimeType,
+			Caption:  caption,
+			FileName: att.FileName,
+			Voice:    voice && att.FileType == string(ports.MediaTypeAudio),
+		}, opts)
+		if err != nil {
+			return "", err
+		}
+		caption = ""
+		opts.Next()
+	}
+	return sourceID, nil
+}
+
+// sendLeadingText envia o texto que acompanha mensagens sem legenda.
+// Se enviado, o texto leva a citacao e opts avanca para a proxima parte.
+func sendLeadingText(ctx context.Context, channel ports.Channel, to, content string, opts *ports.SendOptions) error {
+	if content == "" {
+		return nil
+	}
+	_, err := channel.SendText(ctx, to, content, *opts)
+	opts.Next()
+	return err
+}
+
+// contentAttribute decodifica content_attributes[key] em out
+func contentAttribute(msg *domain.Message, key string, out interface{}) bool {
+	value, ok := msg.ContentAttributes[key]
+	if !ok || value == nil {
+		return false
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(raw, out) == nil
+}
+
+// validateStructuredContent valida localizacao e cartao de contato da requisicao
+func validateStructuredContent(req domain.SendMessageRequest, attachments int) error {
+	if req.ContentType == domain.ContentTypeLocation && req.Location == nil {
+		return fmt.Errorf("%w: content_type location requires location", ErrInvalidMessage)
+	}
+	if req.ContentType == domain.ContentTypeContact && req.Contact == nil {
+		return fmt.Errorf("%w: content_type contact requires contact", ErrInvalidMessage)
+	}
+	if req.Location == nil && req.Contact == nil {
+		return nil
+	}
+	if req.Location != nil && req.Contact != nil {
+		return fmt.Errorf("%w: location and contact cannot be sent together", ErrInvalidMessage)
+	}
+	if attachments > 0 {
+		return fmt.Errorf("%w: location and contact cannot have attachments", ErrInvalidMessage)
+	}
+
+	if loc := req.Location; loc != nil {
+		if loc.Latitude < -90 || loc.Latitude > 90 || loc.Longitude < -180 || loc.Longitude > 180 {
+			return fmt.Errorf("%w: invalid location coordinates", ErrInvalidMessage)
+		}
+	}
+	if contact := req.Contact; contact != nil {
+		if strings.TrimSpace(contact.Name) == "" {
+			return fmt.Errorf("%w: contact name is required", ErrInvalidMessage)
+		}
+		if contact.Phone == "" && contact.VCard == "" {
+			return fmt.Errorf("%w: contact requires phone or vcard", ErrInvalidMessage)
+		}
+	}
+	return nil
+}
+
 // ProcessIncomingMessage processa mensagem recebida do canal
 func (s *MessageService) ProcessIncomingMessage(ctx context.Context, event ports.IncomingEvent) error {
 	log.Printf("[MessageService] Processing incoming message for inbox %s from %s", event.InboxID, event.ContactID)
 
+	switch event.Type {
+	case ports.EventTypeReaction, ports.EventTypeEdit, ports.EventTypeRevoke:
+		return s.processInteraction(ctx, event)
+	case ports.EventTypeGroupUpdate:
+		return s.processGroupUpdate(ctx, event)
+	}
+
+	// Reentregas do canal (mesmo source_id no inbox) nao duplicam a mensagem.
+	// A consulta evita trabalho; o indice unico cobre reentregas simultaneas.
+	if event.SourceID != "" {
+		existing, err := s.messageRepo.GetBySourceID(ctx, event.InboxID, event.SourceID)
+		if err != nil {
+			return fmt.Errorf("failed to check duplicate message: %w", err)
+		}
+		if existing != nil {
+			log.Printf("[MessageService] Message %s already received for inbox %s, skipping", event.SourceID, event.InboxID)
+			return nil
+		}
+	}
+
+	msg, conv, err := s.saveIncoming(ctx, event)
+	if errors.Is(err, repository.ErrDuplicateSourceID) {
+		log.Printf("[MessageService] Message %s already received for inbox %s, skipping", event.SourceID, event.InboxID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// 5. Atualizar conversa
+	conv.LastMessageAt = &event.Timestamp
+	if !event.IsFromMe {
+		conv.UnreadCount++
+	}
+	s.conversationRepo.Update(ctx, conv)
+
+	// 6. Broadcast
+	if s.broadcaster != nil {
+		s.broadcaster.BroadcastMessage(event.InboxID, msg)
+		s.broadcaster.BroadcastConversationUpdate(event.InboxID, conv)
+	}
+
+	log.Printf("[MessageService] Message saved: %s", msg.ID)
+	return nil
+}
+
+// saveIncoming grava a mensagem recebida criando contato, contact_inbox e conversa se preciso
+func (s *MessageService) saveIncoming(ctx context.Context, event ports.IncomingEvent) (*domain.Message, *domain.Conversation, error) {
 	// 1. Buscar ou criar contato
 	contact, err := s.findOrCreateContact(ctx, event)
 	if err != nil {
-		return fmt.Errorf("failed to find/create contact: %w", err)
+		return nil, nil, fmt.Errorf("failed to find/create contact: %w", err)
 	}
 
 	// 2. Buscar ou criar contact_inbox
 	contactInbox, err := s.findOrCreateContactInbox(ctx, event.InboxID, contact.ID, event.ContactID)
 	if err != nil {
-		return fmt.Errorf("failed to find/create contact_inbox: %w", err)
+		return nil, nil, fmt.Errorf("failed to find/create contact_inbox: %w", err)
 	}
 
 	// 3. Buscar ou criar conversa
 	conv, err := s.findOrCreateConversation(ctx, event.InboxID, contact.ID, contactInbox.ID)
 	if err != nil {
-		return fmt.Errorf("failed to find/create conversation: %w", err)
+		return nil, nil, fmt.Errorf("failed to find/create conversation: %w", err)
 	}
 
 	// 4. Criar mensagem
@@ -155,42 +891,246 @@ func (s *MessageService) ProcessIncomingMessage(ctx context.Context, event ports
 		senderType = domain.SenderTypeUser
 	}
 
+	// Grupo: garantir metadados e usar o participante como remetente
+	senderID := contact.ID
+	attrs := event.Attributes
+	if event.IsGroup {
+		s.ensureGroup(ctx, event.InboxID, event.ContactID, contact.ID)
+
+		if !event.IsFromMe && event.SenderID != "" {
+			sender, err := s.findOrCreateParticipant(ctx, event)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to find/create participant: %w", err)
+			}
+			senderID = sender.ID
+
+			if attrs == nil {
+				attrs = make(map[string]interface{})
+			}
+			attrs["participant"] = event.SenderID
+		}
+	}
+
 	contentType := domain.ContentTypeText
 	if event.MediaType != "" {
 		contentType = domain.ContentType(event.MediaType)
 	}
+	if event.ContentType != "" {
+		contentType = domain.ContentType(event.ContentType)
+	}
 
 	msg := &domain.Message{
-		ID:             uuid.New().String(),
-		ConversationID: conv.ID,
-		InboxID:        event.InboxID,
-		SenderType:     senderType,
-		SenderID:       &contact.ID,
-		Content:        event.Content,
-		ContentType:    contentType,
-		SourceID:       event.SourceID,
-		Status:         ports.MessageStatusDelivered,
-		CreatedAt:      event.Timestamp,
+		ID:                uuid.New().String(),
+		ConversationID:    conv.ID,
+		InboxID:           event.InboxID,
+		SenderType:        senderType,
+		SenderID:          &senderID,
+		Content:           event.Content,
+		ContentType:       contentType,
+		ContentAttributes: attrs,
+		SourceID:          event.SourceID,
+		Status:            ports.MessageStatusDelivered,
+		CreatedAt:         event.Timestamp,
+	}
+
+	// Resposta: vincular a mensagem citada (ou guardar o source_id se desconhecida)
+	if event.ReplyTo != "" {
+		if parent, err := s.messageRepo.GetBySourceID(ctx, event.InboxID, event.ReplyTo); err == nil && parent != nil {
+			msg.InReplyToID = &parent.ID
+		} else {
+			if msg.ContentAttributes == nil {
+				msg.ContentAttributes = make(map[string]interface{})
+			}
+			msg.ContentAttributes["in_reply_to_source_id"] = event.ReplyTo
+		}
 	}
 
 	if err := s.messageRepo.Create(ctx, msg); err != nil {
-		return fmt.Errorf("failed to create message: %w", err)
+		return nil, nil, fmt.Errorf("failed to create message: %w", err)
 	}
 
-	// 5. Atualizar conversa
-	conv.LastMessageAt = &event.Timestamp
-	if !event.IsFromMe {
-		conv.UnreadCount++
+	// Midia baixada pelo canal vira attachment da mensagem
+	if event.Media != nil && len(event.Media.Data) > 0 {
+		if err := s.saveAttachments(ctx, msg, []preparedAttachment{incomingAttachment(event)}); err != nil {
+			log.Printf("[MessageService] Failed to save media for message %s: %v", msg.ID, err)
+		}
 	}
-	s.conversationRepo.Update(ctx, conv)
 
-	// 6. Broadcast
-	if s.broadcaster != nil {
-		s.broadcaster.BroadcastMessage(event.InboxID, msg)
+	return msg, conv, nil
+}
+
+// processInteraction aplica reacao, edicao ou remocao recebida do canal
+func (s *MessageService) processInteraction(ctx context.Context, event ports.IncomingEvent) error {
+	target, err := s.messageRepo.GetBySourceID(ctx, event.InboxID, event.Target)
+	if err != nil {
+		return fmt.Errorf("failed to get target message: %w", err)
+	}
+	if target == nil {
+		log.Printf("[MessageService] Ignoring %s for unknown message %s", event.Type, event.Target)
+		return nil
+	}
+
+	switch event.Type {
+	case ports.EventTypeReaction:
+		// Reacao feita no aparelho do proprio numero fica sem sender_id
+		reaction := &domain.Reaction{
+			ID:             uuid.New().String(),
+			MessageID:      target.ID,
+			ConversationID: target.ConversationID,
+			SenderType:     domain.SenderTypeUser,
+			Emoji:          event.Reaction,
+			CreatedAt:      event.Timestamp,
+		}
+		if !event.IsFromMe {
+			reaction.SenderType = domain.SenderTypeContact
+			senderSourceID := event.ContactID
+			if event.IsGroup && event.SenderID != "" {
+				senderSourceID = event.SenderID
+			}
+			if ci, err := s.contactInboxRepo.GetBySourceID(ctx, event.InboxID, senderSourceID); err == nil && ci != nil {
+				reaction.SenderID = ci.ContactID
+			}
+		}
+		if err := s.saveReaction(ctx, reaction); err != nil {
+			return err
+		}
+		if s.broadcaster != nil {
+			s.broadcaster.BroadcastReaction(target.InboxID, reaction)
+		}
+
+	case ports.EventTypeEdit:
+		if target.DeletedAt != nil {
+			return nil
+		}
+		if err := s.messageRepo.UpdateContent(ctx, target.ID, event.Content); err != nil {
+			return fmt.Errorf("failed to update message: %w", err)
+		}
+		target.Content = event.Content
+		target.EditedAt = &event.Timestamp
+		if target.Attachments, err = s.loadAttachments(ctx, target.ID); err != nil {
+			log.Printf("[MessageService] Failed to load attachments for %s: %v", target.ID, err)
+		}
+		if s.broadcaster != nil {
+			s.broadcaster.BroadcastMessageUpdate(target.InboxID, target)
+		}
+
+	case ports.EventTypeRevoke:
+		if target.DeletedAt != nil {
+			return nil
+		}
+		if err := s.messageRepo.MarkDeleted(ctx, target.ID); err != nil {
+			return fmt.Errorf("failed to delete message: %w", err)
+		}
+		s.markDeleted(target)
+	}
+
+	log.Printf("[MessageService] Applied %s to message %s", event.Type, target.ID)
+	return nil
+}
+
+// processGroupUpdate atualiza metadados de um grupo alterado no canal
+func (s *MessageService) processGroupUpdate(ctx context.Context, event ports.IncomingEvent) error {
+	if s.groups == nil {
+		return nil
+	}
+	group, err := s.groups.SyncBySourceID(ctx, event.InboxID, event.ContactID)
+	if err != nil {
+		return fmt.Errorf("failed to sync group %s: %w", event.ContactID, err)
+	}
+	if group == nil || s.broadcaster == nil {
+		return nil
+	}
+
+	// Nome do contato do grupo pode ter mudado
+	ci, err := s.contactInboxRepo.GetBySourceID(ctx, event.InboxID, event.ContactID)
+	if err != nil || ci == nil {
+		return nil
+	}
+	if conv, err := s.conversationRepo.GetByContactInboxID(ctx, ci.ID); err == nil && conv != nil {
 		s.broadcaster.BroadcastConversationUpdate(event.InboxID, conv)
 	}
+	return nil
+}
 
-	log.Printf("[MessageService] Message saved: %s", msg.ID)
+// ensureGroup grava metadados do grupo na primeira mensagem; falhas nao impedem a mensagem
+func (s *MessageService) ensureGroup(ctx context.Context, inboxID, sourceID, contactID string) {
+	if s.groups == nil {
+		return
+	}
+	if _, err := s.groups.Ensure(ctx, inboxID, sourceID, contactID); err != nil {
+		log.Printf("[MessageService] Failed to load group %s: %v", sourceID, err)
+	}
+}
+
+// findOrCreateParticipant retorna o contato do participante que enviou a mensagem no grupo
+func (s *MessageService) findOrCreateParticipant(ctx context.Context, event ports.IncomingEvent) (*domain.Contact, error) {
+	contact, err := s.findOrCreateContact(ctx, ports.IncomingEvent{
+		InboxID:      event.InboxID,
+		ContactID:    event.SenderID,
+		ContactName:  event.SenderName,
+		ContactPhone: event.SenderPhone,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.findOrCreateContactInbox(ctx, event.InboxID, contact.ID, event.SenderID); err != nil {
+		return nil, err
+	}
+	return contact, nil
+}
+
+// ProcessPresence repassa aos agentes digitacao e online/offline de contatos conhecidos.
+// Eventos de contatos sem conversa no inbox sao ignorados.
+func (s *MessageService) ProcessPresence(ctx context.Context, event ports.PresenceEvent) error {
+	if s.broadcaster == nil {
+		return nil
+	}
+
+	ci, err := s.contactInboxRepo.GetBySourceID(ctx, event.InboxID, event.ContactID)
+	if err != nil {
+		return fmt.Errorf("failed to get contact inbox: %w", err)
+	}
+	if ci == nil {
+		return nil
+	}
+	conv, err := s.conversationRepo.GetByContactInboxID(ctx, ci.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get conversation: %w", err)
+	}
+
+	switch event.Type {
+	case ports.EventTypeTyping:
+		if conv == nil {
+			return nil
+		}
+		// Em grupos quem digita e o participante, nao o grupo
+		contactID := ci.ContactID
+		if event.SenderID != "" {
+			sender, err := s.contactInboxRepo.GetBySourceID(ctx, event.InboxID, event.SenderID)
+			if err != nil || sender == nil {
+				return nil
+			}
+			contactID = sender.ContactID
+		}
+		s.broadcaster.BroadcastTyping(event.InboxID, &domain.TypingIndicator{
+			ConversationID: conv.ID,
+			ContactID:      contactID,
+			State:          event.State,
+			Timestamp:      event.Timestamp,
+		})
+
+	case ports.EventTypePresence:
+		presence := &domain.ContactPresence{
+			ContactID: ci.ContactID,
+			Online:    event.State == "online",
+			LastSeen:  event.LastSeen,
+			Timestamp: event.Timestamp,
+		}
+		if conv != nil {
+			presence.ConversationID = conv.ID
+		}
+		s.broadcaster.BroadcastPresence(event.InboxID, presence)
+	}
 	return nil
 }
 
@@ -199,19 +1139,124 @@ func (s *MessageService) ProcessStatusUpdate(ctx context.Context, inboxID, sourc
 	return s.messageRepo.UpdateStatusBySourceID(ctx, inboxID, sourceID, status)
 }
 
-// GetMessages lista mensagens de uma conversa
+// GetMessages lista mensagens de uma conversa com seus anexos e reacoes
 func (s *MessageService) GetMessages(ctx context.Context, conversationID string, limit, offset int) ([]*domain.Message, error) {
-	return s.messageRepo.ListByConversation(ctx, conversationID, limit, offset)
+	messages, err := s.messageRepo.ListByConversation(ctx, conversationID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(messages))
+	for i, msg := range messages {
+		ids[i] = msg.ID
+	}
+	attachments, err := s.attachmentRepo.GetByMessageIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	reactions, err := s.reactionRepo.GetByMessageIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reactions: %w", err)
+	}
+	for _, msg := range messages {
+		msg.Attachments = attachments[msg.ID]
+		msg.Reactions = reactions[msg.ID]
+		s.signAttachments(ctx, msg.Attachments)
+	}
+
+	return messages, nil
 }
 
-// MarkAsRead marca conversa como lida
+// MarkAsRead marca conversa como lida e confirma a leitura ao contato
+// nos canais que suportam (tiques azuis no WhatsApp)
 func (s *MessageService) MarkAsRead(ctx context.Context, conversationID string) error {
-	return s.conversationRepo.ResetUnread(ctx, conversationID)
+	conv, err := s.conversationRepo.GetByID(ctx, conversationID)
+	if err != nil || conv == nil {
+		return fmt.Errorf("conversation not found")
+	}
+
+	if err := s.conversationRepo.ResetUnread(ctx, conversationID); err != nil {
+		return fmt.Errorf("failed to reset unread count: %w", err)
+	}
+
+	unread, err := s.messageRepo.ListUnreadIncoming(ctx, conversationID, maxReadReceipts)
+	if err != nil {
+		return fmt.Errorf("failed to list unread messages: %w", err)
+	}
+	if len(unread) == 0 {
+		return nil
+	}
+
+	// Canal sem confirmacao de leitura: leitura e apenas local
+	if s.channels != nil {
+		if channel, err := s.channels.Get(conv.InboxID); err == nil {
+			if _, ok := channel.(ports.ReadReceiptChannel); ok {
+				if err := s.sendReadReceipts(ctx, conv, unread); err != nil {
+					// Mantem pendente: a confirmacao e reenviada na proxima leitura
+					log.Printf("[MessageService] Failed to send read receipts for %s: %v", conversationID, err)
+					return nil
+				}
+			}
+		}
+	}
+
+	if err := s.messageRepo.MarkIncomingRead(ctx, conversationID); err != nil {
+		return fmt.Errorf("failed to mark messages as read: %w", err)
+	}
+	return nil
+}
+
+// sendReadReceipts confirma ao contato a leitura das mensagens recebidas
+func (s *MessageService) sendReadReceipts(ctx context.Context, conv *domain.Conversation, messages []*domain.Message) error {
+	channel, to, err := s.conversationTarget(ctx, conv)
+	if err != nil {
+		return err
+	}
+	receipts, ok := channel.(ports.ReadReceiptChannel)
+	if !ok {
+		return nil
+	}
+
+	refs := make([]ports.MessageRef, 0, len(messages))
+	for _, msg := range messages {
+		refs = append(refs, messageRef(msg, to))
+	}
+	return receipts.MarkRead(ctx, to, refs)
+}
+
+// SendChatPresence exibe ao contato que o agente esta digitando, gravando ou parou
+func (s *MessageService) SendChatPresence(ctx context.Context, conversationID string, presence ports.ChatPresence) error {
+	switch presence {
+	case ports.ChatPresenceComposing, ports.ChatPresenceRecording, ports.ChatPresencePaused:
+	default:
+		return fmt.Errorf("%w: invalid presence %q", ErrInvalidMessage, presence)
+	}
+
+	conv, err := s.conversationRepo.GetByID(ctx, conversationID)
+	if err != nil || conv == nil {
+		return fmt.Errorf("conversation not found")
+	}
+
+	channel, to, err := s.conversationTarget(ctx, conv)
+	if err != nil {
+		return err
+	}
+	presenceChannel, ok := channel.(ports.PresenceChannel)
+	if !ok {
+		return ErrPresenceNotSupported
+	}
+	return presenceChannel.SendChatPresence(ctx, to, presence)
 }
 
 func (s *MessageService) findOrCreateContact(ctx context.Context, event ports.IncomingEvent) (*domain.Contact, error) {
-	// Extrair telefone do source_id (JID)
-	phone := extractPhoneFromSourceID(event.ContactID)
+	// Identidade ja conhecida neste inbox (canais sem telefone, ex: Telegram)
+	if ci, err := s.contactInboxRepo.GetBySourceID(ctx, event.InboxID, event.ContactID); err == nil && ci != nil {
+		if contact, err := s.contactRepo.GetByID(ctx, ci.ContactID); err == nil && contact != nil {
+			return contact, nil
+		}
+	}
+
+	phone := s.normalizePhone(ctx, event.InboxID, event.ContactPhone)
 
 	// Buscar por telefone
 	if phone != "" {
@@ -231,6 +1276,9 @@ func (s *MessageService) findOrCreateContact(ctx context.Context, event ports.In
 	if name == "" {
 		name = phone
 	}
+	if name == "" {
+		name = event.ContactID
+	}
 
 	contact := &domain.Contact{
 		ID:          uuid.New().String(),
@@ -247,6 +1295,21 @@ func (s *MessageService) findOrCreateContact(ctx context.Context, event ports.In
 	return contact, nil
 }
 
+// normalizePhone converte o telefone para E.164 na regiao do inbox.
+// Valores que nao sao telefones validos sao mantidos como recebidos.
+func (s *MessageService) normalizePhone(ctx context.Context, inboxID, raw string) string {
+	region := ""
+	if inbox, err := s.inboxRepo.GetByID(ctx, inboxID); err == nil && inbox != nil {
+		region = inbox.PhoneRegion
+	}
+	normalized, err := phonepkg.Normalize(raw, region)
+	if err != nil {
+		log.Printf("[MessageService] Keeping unnormalized phone %q from inbox %s: %v", raw, inboxID, err)
+		return strings.TrimSpace(raw)
+	}
+	return normalized
+}
+
 func (s *MessageService) findOrCreateContactInbox(ctx context.Context, inboxID, contactID, sourceID string) (*domain.ContactInbox, error) {
 	ci, err := s.contactInboxRepo.GetBySourceID(ctx, inboxID, sourceID)
 	if err == nil && ci != nil {
@@ -266,6 +1329,9 @@ func (s *MessageService) findOrCreateContactInbox(ctx context.Context, inboxID,
 		return nil, err
 	}
 
+	if s.profiles != nil {
+		s.profiles.Enqueue(inboxID, contactID, sourceID)
+	}
 	return ci, nil
 }
 
@@ -297,25 +1363,3 @@ func (s *MessageService) findOrCreateConversation(ctx context.Context, inboxID,
 
 	return conv, nil
 }
-
-func extractPhoneFromSourceID(sourceID string) string {
-	// sourceID pode ser JID (5511999999999@s.whatsapp.net)
-	// ou telefone direto
-	if len(sourceID) > 0 {
-		// Remover sufixo @s.whatsapp.net ou similar
-		phone := sourceID
-		if idx := len(phone) - 1; idx > 0 {
-			for i := 0; i < len(phone); i++ {
-				if phone[i] == '@' {
-					phone = phone[:i]
-					break
-				}
-			}
-		}
-		if phone[0] != '+' && len(phone) > 5 {
-			phone = "+" + phone
-		}
-		return phone
-	}
-	return ""
-}
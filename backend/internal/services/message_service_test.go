@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zyntra/backend/internal/domain"
+)
+
+func TestCanEditMessage(t *testing.T) {
+	author := "user-1"
+	other := "user-2"
+
+	tests := []struct {
+		name    string
+		msg     *domain.Message
+		userID  string
+		wantErr error
+	}{
+		{name: "author", msg: &domain.Message{SenderType: domain.SenderTypeUser, SenderID: &author}, userID: author},
+		{name: "other agent", msg: &domain.Message{SenderType: domain.SenderTypeUser, SenderID: &author}, userID: other, wantErr: ErrMessageForbidden},
+		{name: "message without sender", msg: &domain.Message{SenderType: domain.SenderTypeUser}, userID: other, wantErr: ErrMessageForbidden},
+		{name: "contact message", msg: &domain.Message{SenderType: domain.SenderTypeContact}, userID: author, wantErr: ErrInvalidMessage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := canEditMessage(tt.msg, tt.userID)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("canEditMessage() error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("canEditMessage() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCanDeleteMessage(t *testing.T) {
+	author := "user-1"
+	other := "user-2"
+
+	tests := []struct {
+		name    string
+		msg     *domain.Message
+		userID  string
+		admin   bool
+		wantErr error
+	}{
+		{name: "author", msg: &domain.Message{SenderType: domain.SenderTypeUser, SenderID: &author}, userID: author},
+		{name: "other agent", msg: &domain.Message{SenderType: domain.SenderTypeUser, SenderID: &author}, userID: other, wantErr: ErrMessageForbidden},
+		{name: "admin", msg: &domain.Message{SenderType: domain.SenderTypeUser, SenderID: &author}, userID: other, admin: true},
+		{name: "message without sender", msg: &domain.Message{SenderType: domain.SenderTypeUser}, userID: other, wantErr: ErrMessageForbidden},
+		{name: "contact message", msg: &domain.Message{SenderType: domain.SenderTypeContact}, userID: author, wantErr: ErrInvalidMessage},
+		{name: "contact message as admin", msg: &domain.Message{SenderType: domain.SenderTypeContact}, userID: author, admin: true, wantErr: ErrInvalidMessage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := canDeleteMessage(tt.msg, tt.userID, tt.admin)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("canDeleteMessage() error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("canDeleteMessage() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	natspkg "github.com/zyntra/backend/pkg/nats"
+)
+
+const (
+	// outboundLegacyConsumer consumer unico anterior aos consumers por inbox
+	outboundLegacyConsumer = "outbound-delivery"
+	outboundConsumerPrefix = "outbound-"
+	outboundMaxAttempts    = 5
+	outboundBaseDelay      = 2 * time.Second
+	outboundMaxDelay       = 2 * time.Minute
+	outboundSendTimeout    = 20 * time.Second
+
+	// Mensagens pendentes ha mais de outboundDeadline sao marcadas como failed,
+	// mesmo que o canal nunca tenha conectado para consumir o job
+	outboundDeadline   = 10 * time.Minute
+	outboundSweepEvery = time.Minute
+	outboundSweepBatch = 100
+)
+
+// ErrOutboundExpired mensagem nao entregue dentro de outboundDeadline
+var ErrOutboundExpired = errors.New("channel unavailable: message not delivered in time")
+
+// OutboundJob mensagem aguardando entrega ao canal
+type OutboundJob struct {
+	MessageID string `json:"message_id"`
+	InboxID   string `json:"inbox_id"`
+}
+
+// OutboundPublisher interface para enfileirar mensagens de saida
+type OutboundPublisher interface {
+	Enqueue(ctx context.Context, job OutboundJob) error
+}
+
+// OutboundQueue fila de envio sobre NATS JetStream.
+// Cada inbox tem um consumer duravel filtrado em SubjectOutbound(inboxID),
+// consumido apenas pela replica que mantem a sessao do canal (Attach).
+// O consumer entrega um job por vez, preservando a ordem das respostas.
+// Falhas sao reentregues com backoff exponencial ate outboundMaxAttempts;
+// depois a mensagem e marcada como failed. Jobs de canais desconectados
+// aguardam no stream e a varredura marca a mensagem como failed apos outboundDeadline.
+type OutboundQueue struct {
+	nats           *natspkg.Client
+	messageService *MessageService
+	stop           chan struct{}
+	wg             sync.WaitGroup
+
+	mu        sync.Mutex
+	consumers map[string]jetstream.Consumer       // consumers ja criados, por inbox
+	active    map[string]jetstream.ConsumeContext // inboxes consumidos nesta replica
+}
+
+// NewOutboundQueue cria nova fila de envio
+func NewOutboundQueue(client *natspkg.Client, messageService *MessageService) *OutboundQueue {
+	return &OutboundQueue{
+		nats:           client,
+		messageService: messageService,
+		stop:           make(chan struct{}),
+		consumers:      make(map[string]jetstream.Consumer),
+		active:         make(map[string]jetstream.ConsumeContext),
+	}
+}
+
+// Enqueue publica mensagem para entrega.
+// O consumer do inbox e criado antes: o stream descarta mensagens sem interessados.
+func (q *OutboundQueue) Enqueue(ctx context.Context, job OutboundJob) error {
+	if _, err := q.consumer(ctx, job.InboxID); err != nil {
+		return err
+	}
+	if _, err := q.nats.PublishToStream(ctx, natspkg.SubjectOutbound(job.InboxID), job); err != nil {
+		return fmt.Errorf("failed to publish outbound message: %w", err)
+	}
+	return nil
+}
+
+// Start remove o consumer unico legado, que reteria todas as mensagens do stream,
+// e inicia a varredura de mensagens pendentes expiradas
+func (q *OutboundQueue) Start(ctx context.Context) error {
+	err := q.nats.DeleteConsumer(ctx, natspkg.StreamOutbound, outboundLegacyConsumer)
+	if err != nil && !errors.Is(err, jetstream.ErrConsumerNotFound) {
+		return fmt.Errorf("failed to remove legacy outbound consumer: %w", err)
+	}
+
+	q.wg.Add(1)
+	go q.runSweep()
+
+	log.Printf("[Outbound] Queue started")
+	return nil
+}
+
+// Attach passa a entregar as mensagens do inbox nesta replica
+func (q *OutboundQueue) Attach(ctx context.Context, inboxID string) error {
+	consumer, err := q.consumer(ctx, inboxID)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.active[inboxID]; ok {
+		return nil
+	}
+	consumeCtx, err := consumer.Consume(q.handle)
+	if err != nil {
+		return fmt.Errorf("failed to start outbound consumer for inbox %s: %w", inboxID, err)
+	}
+	q.active[inboxID] = consumeCtx
+
+	log.Printf("[Outbound] Consuming inbox %s", inboxID)
+	return nil
+}
+
+// Detach para de entregar as mensagens do inbox; os jobs seguem no stream
+func (q *OutboundQueue) Detach(inboxID string) {
+	q.mu.Lock()
+	consumeCtx, ok := q.active[inboxID]
+	delete(q.active, inboxID)
+	q.mu.Unlock()
+
+	if ok {
+		consumeCtx.Stop()
+		log.Printf("[Outbound] Stopped consuming inbox %s", inboxID)
+	}
+}
+
+// Stop interrompe o processamento
+func (q *OutboundQueue) Stop() {
+	close(q.stop)
+	q.wg.Wait()
+
+	q.mu.Lock()
+	active := q.active
+	q.active = make(map[string]jetstream.ConsumeContext)
+	q.mu.Unlock()
+
+	for _, consumeCtx := range active {
+		consumeCtx.Stop()
+	}
+}
+
+// consumer cria (uma vez por processo) o consumer duravel do inbox
+func (q *OutboundQueue) consumer(ctx context.Context, inboxID string) (jetstream.Consumer, error) {
+	q.mu.Lock()
+	consumer, ok := q.consumers[inboxID]
+	q.mu.Unlock()
+	if ok {
+		return consumer, nil
+	}
+
+	consumer, err := q.nats.CreateSerialConsumer(ctx, natspkg.StreamOutbound, outboundConsumerPrefix+inboxID, natspkg.SubjectOutbound(inboxID))
+	if err != nil {
+		return nil, err
+	}
+
+	q.mu.Lock()
+	q.consumers[inboxID] = consumer
+	q.mu.Unlock()
+	return consumer, nil
+}
+
+func (q *OutboundQueue) handle(msg jetstream.Msg) {
+	var job OutboundJob
+	if err := json.Unmarshal(msg.Data(), &job); err != nil {
+		log.Printf("[Outbound] Invalid job discarded: %v", err)
+		msg.Term()
+		return
+	}
+
+	attempt := uint64(1)
+	if meta, err := msg.Metadata(); err == nil {
+		attempt = meta.NumDelivered
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), outboundSendTimeout)
+	defer cancel()
+
+	err := q.messageService.DeliverOutbound(ctx, job.MessageID)
+	if err == nil {
+		msg.Ack()
+		return
+	}
+
+	if attempt >= outboundMaxAttempts {
+		log.Printf("[Outbound] Message %s failed after %d attempts: %v", job.MessageID, attempt, err)
+		q.messageService.FailOutbound(context.Background(), job.MessageID, err)
+		msg.Term()
+		return
+	}
+
+	delay := outboundBackoff(attempt)
+	log.Printf("[Outbound] Message %s attempt %d failed, retrying in %s: %v", job.MessageID, attempt, delay, err)
+	msg.NakWithDelay(delay)
+}
+
+// runSweep marca periodicamente como failed as mensagens pendentes expiradas
+func (q *OutboundQueue) runSweep() {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(outboundSweepEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-q.stop:
+			return
+		case <-ticker.C:
+			q.messageService.FailExpiredOutbound(context.Background(), time.Now().Add(-outboundDeadline), outboundSweepBatch)
+		}
+	}
+}
+
+// outboundBackoff retorna espera antes da proxima tentativa (2s, 4s, 8s... ate outboundMaxDelay)
+func outboundBackoff(attempt uint64) time.Duration {
+	delay := outboundBaseDelay
+	for i := uint64(1); i < attempt && delay < outboundMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > outboundMaxDelay {
+		delay = outboundMaxDelay
+	}
+	return delay
+}
+
+// Verify interface implementation
+var _ OutboundPublisher = (*OutboundQueue)(nil)
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	natspkg "github.com/zyntra/backend/pkg/nats"
+	wspkg "github.com/zyntra/backend/pkg/websocket"
+)
+
+// realtimeEnvelope evento do hub publicado no NATS com a instancia de origem
+type realtimeEnvelope struct {
+	Origin string      `json:"origin"`
+	Event  wspkg.Event `json:"event"`
+}
+
+// RealtimeRelay distribui os eventos do hub entre as replicas via NATS.
+// Cada instancia publica os proprios eventos e assina apenas os inboxes e
+// usuarios dos seus clientes (SetInterest), alem dos eventos globais.
+// Eventos que voltam com a propria origem ja foram entregues e sao ignorados.
+type RealtimeRelay struct {
+	nats   *natspkg.Client
+	hub    *wspkg.Hub
+	origin string
+
+	mu   sync.Mutex
+	subs map[string]*nats.Subscription // Assinaturas por subject
+}
+
+// NewRealtimeRelay cria relay com uma origem unica para esta instancia
+func NewRealtimeRelay(client *natspkg.Client, hub *wspkg.Hub) *RealtimeRelay {
+	return &RealtimeRelay{
+		nats:   client,
+		hub:    hub,
+		origin: uuid.NewString(),
+		subs:   make(map[string]*nats.Subscription),
+	}
+}
+
+// Start assina os eventos globais; os demais seguem os clientes conectados
+func (r *RealtimeRelay) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.subscribe(natspkg.SubjectGlobalEvents); err != nil {
+		return err
+	}
+
+	log.Printf("[Realtime] Relay started (origin %s)", r.origin)
+	return nil
+}
+
+// Stop cancela as assinaturas
+func (r *RealtimeRelay) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for subject, sub := range r.subs {
+		sub.Unsubscribe()
+		delete(r.subs, subject)
+	}
+}
+
+// SetInterest ajusta as assinaturas aos inboxes e usuarios dos clientes locais
+func (r *RealtimeRelay) SetInterest(interest wspkg.Interest) {
+	wanted := map[string]bool{natspkg.SubjectGlobalEvents: true}
+	if interest.AllInboxes {
+		wanted[natspkg.SubjectAllInboxEvents] = true
+	}
+	for _, id := range interest.InboxIDs {
+		wanted[natspkg.SubjectInboxEvents(id)] = true
+	}
+	for _, id := range interest.UserIDs {
+		wanted[natspkg.SubjectUserEvents(id)] = true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for subject, sub := range r.subs {
+		if !wanted[subject] {
+			sub.Unsubscribe()
+			delete(r.subs, subject)
+		}
+	}
+	for subject := range wanted {
+		if err := r.subscribe(subject); err != nil {
+			log.Printf("[Realtime] %v", err)
+		}
+	}
+}
+
+// subscribe assina o subject se ainda nao assinado (chamar com mu)
+func (r *RealtimeRelay) subscribe(subject string) error {
+	if _, ok := r.subs[subject]; ok {
+		return nil
+	}
+	sub, err := r.nats.Conn().Subscribe(subject, r.handle)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to realtime events on %s: %w", subject, err)
+	}
+	r.subs[subject] = sub
+	return nil
+}
+
+// Publish envia evento local para as demais replicas
+func (r *RealtimeRelay) Publish(event wspkg.Event) {
+	subject := natspkg.SubjectGlobalEvents
+	switch {
+	case event.UserID != "":
+		subject = natspkg.SubjectUserEvents(event.UserID)
+	case event.InboxID != "":
+		subject = natspkg.SubjectInboxEvents(event.InboxID)
+	}
+
+	if err := r.nats.Publish(subject, realtimeEnvelope{Origin: r.origin, Event: event}); err != nil {
+		log.Printf("[Realtime] Failed to publish %s event: %v", event.Type, err)
+	}
+}
+
+// handle entrega aos clientes locais os eventos de outras replicas
+func (r *RealtimeRelay) handle(msg *nats.Msg) {
+	var envelope realtimeEnvelope
+	if err := json.Unmarshal(msg.Data, &envelope); err != nil {
+		log.Printf("[Realtime] Invalid event on %s: %v", msg.Subject, err)
+		return
+	}
+	if envelope.Origin == r.origin {
+		return
+	}
+	r.hub.BroadcastLocal(envelope.Event)
+}
+
+// Verify interface implementation
+var _ wspkg.Relay = (*RealtimeRelay)(nil)
@@ -188,6 +188,25 @@ func SubjectQRCode(connectionID string) string {
 	return fmt.Sprintf("zyntra.qr.%s", connectionID)
 }
 
+func SubjectOutbound(inboxID string) string {
+	return fmt.Sprintf("zyntra.outbound.%s", inboxID)
+}
+
+// Real-time fan-out between replicas (core NATS, not stored in any stream).
+// Each replica subscribes only to the inboxes and users of its connected clients.
+const (
+	SubjectGlobalEvents   = "zyntra.events.global"
+	SubjectAllInboxEvents = "zyntra.events.inbox.*"
+)
+
+func SubjectInboxEvents(inboxID string) string {
+	return fmt.Sprintf("zyntra.events.inbox.%s", inboxID)
+}
+
+func SubjectUserEvents(userID string) string {
+	return fmt.Sprintf("zyntra.events.user.%s", userID)
+}
+
 // PublishMessage publishes a new message event
 func (c *Client) PublishMessage(ctx context.Context, connectionID string, data *MessageData) error {
 	event := NewEvent(EventTypeMessage, connectionID, data)
@@ -14,6 +14,7 @@ const (
 	StreamMessages    = "MESSAGES"
 	StreamConnections = "CONNECTIONS"
 	StreamQR          = "QR"
+	StreamOutbound    = "OUTBOUND"
 )
 
 // SetupStreams creates the JetStream streams for the application
@@ -70,6 +71,21 @@ func (c *Client) SetupStreams(ctx context.Context) error {
 		return fmt.Errorf("failed to create QR stream: %w", err)
 	}
 
+	// Outbound stream - work queue for messages pending delivery to channels
+	if err := c.createOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:        StreamOutbound,
+		Description: "Outbound messages pending delivery to channels",
+		Subjects:    []string{"zyntra.outbound.>"},
+		Retention:   jetstream.InterestPolicy, // Removed once acked by the delivery consumer
+		MaxAge:      24 * time.Hour,
+		MaxMsgs:     -1,
+		Discard:     jetstream.DiscardOld,
+		Storage:     jetstream.FileStorage,
+		Replicas:    1,
+	}); err != nil {
+		return fmt.Errorf("failed to create OUTBOUND stream: %w", err)
+	}
+
 	log.Printf("[NATS] JetStream streams setup complete")
 	return nil
 }
@@ -87,20 +103,38 @@ func (c *Client) createOrUpdateStream(ctx context.Context, config jetstream.Stre
 
 // CreateConsumer creates a consumer for a stream
 func (c *Client) CreateConsumer(ctx context.Context, streamName, consumerName string, filterSubject string) (jetstream.Consumer, error) {
-	stream, err := c.js.Stream(ctx, streamName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get stream %s: %w", streamName, err)
-	}
+	return c.createConsumer(ctx, streamName, jetstream.ConsumerConfig{
+		Name:          consumerName,
+		Durable:       consumerName,
+		FilterSubject: filterSubject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		DeliverPolicy: jetstream.DeliverNewPolicy,
+	})
+}
 
-	consumer, err := stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+// CreateSerialConsumer creates a consumer that delivers one message at a time.
+// The next message waits until the current one is acked or terminated, so a
+// message redelivered after a delayed NAK is never overtaken by a later one.
+func (c *Client) CreateSerialConsumer(ctx context.Context, streamName, consumerName string, filterSubject string) (jetstream.Consumer, error) {
+	return c.createConsumer(ctx, streamName, jetstream.ConsumerConfig{
 		Name:          consumerName,
 		Durable:       consumerName,
 		FilterSubject: filterSubject,
 		AckPolicy:     jetstream.AckExplicitPolicy,
 		DeliverPolicy: jetstream.DeliverNewPolicy,
+		MaxAckPending: 1,
 	})
+}
+
+func (c *Client) createConsumer(ctx context.Context, streamName string, config jetstream.ConsumerConfig) (jetstream.Consumer, error) {
+	stream, err := c.js.Stream(ctx, streamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stream %s: %w", streamName, err)
+	}
+
+	consumer, err := stream.CreateOrUpdateConsumer(ctx, config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create consumer %s: %w", consumerName, err)
+		return nil, fmt.Errorf("failed to create consumer %s: %w", config.Name, err)
 	}
 
 	return consumer, nil
@@ -0,0 +1,145 @@
+package phone
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// FallbackRegion regiao usada quando PHONE_DEFAULT_REGION nao esta definida
+const FallbackRegion = "BR"
+
+var (
+	// ErrInvalid numero que nao pode ser convertido para E.164
+	ErrInvalid = errors.New("invalid phone number")
+	// ErrUnknownRegion regiao sem regras de numeracao conhecidas
+	ErrUnknownRegion = errors.New("unknown phone region")
+)
+
+// region regras de numeracao nacional de um pais
+type region struct {
+	code   string // Codigo do pais (sem +)
+	trunk  string // Prefixo de discagem nacional
+	minLen int    // Digitos do numero nacional (sem prefixo)
+	maxLen int
+}
+
+var regions = map[string]region{
+	"BR": {code: "55", trunk: "0", minLen: 10, maxLen: 11},
+	"US": {code: "1", trunk: "1", minLen: 10, maxLen: 10},
+	"CA": {code: "1", trunk: "1", minLen: 10, maxLen: 10},
+	"MX": {code: "52", minLen: 10, maxLen: 10},
+	"AR": {code: "54", trunk: "0", minLen: 10, maxLen: 11},
+	"CL": {code: "56", minLen: 9, maxLen: 9},
+	"CO": {code: "57", minLen: 10, maxLen: 10},
+	"PE": {code: "51", minLen: 8, maxLen: 9},
+	"PY": {code: "595", trunk: "0", minLen: 9, maxLen: 9},
+	"UY": {code: "598", trunk: "0", minLen: 8, maxLen: 8},
+	"PT": {code: "351", minLen: 9, maxLen: 9},
+	"ES": {code: "34", minLen: 9, maxLen: 9},
+	"FR": {code: "33", trunk: "0", minLen: 9, maxLen: 9},
+	"IT": {code: "39", minLen: 6, maxLen: 11},
+	"DE": {code: "49", trunk: "0", minLen: 7, maxLen: 12},
+	"GB": {code: "44", trunk: "0", minLen: 10, maxLen: 10},
+}
+
+// DefaultRegion retorna a regiao padrao do servidor (PHONE_DEFAULT_REGION)
+func DefaultRegion() string {
+	if r := strings.ToUpper(strings.TrimSpace(os.Getenv("PHONE_DEFAULT_REGION"))); ValidRegion(r) {
+		return r
+	}
+	return FallbackRegion
+}
+
+// ValidRegion verifica se a regiao (ISO 3166-1 alpha-2) tem regras conhecidas
+func ValidRegion(r string) bool {
+	_, ok := regions[strings.ToUpper(r)]
+	return ok
+}
+
+// Normalize converte um telefone para E.164 (+5511999999999).
+// Numeros sem codigo do pais sao interpretados na regiao informada
+// (vazia usa DefaultRegion). Celulares brasileiros sem o nono digito
+// recebem o 9, para que as duas formas resultem no mesmo numero.
+func Normalize(raw, regionCode string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+
+	digits := onlyDigits(raw)
+	international := strings.HasPrefix(raw, "+")
+	if !international && strings.HasPrefix(digits, "00") {
+		digits = digits[2:]
+		international = true
+	}
+
+	if !international {
+		if regionCode == "" {
+			regionCode = DefaultRegion()
+		}
+		r, ok := regions[strings.ToUpper(regionCode)]
+		if !ok {
+			return "", ErrUnknownRegion
+		}
+		national, ok := r.national(digits)
+		if !ok {
+			return "", ErrInvalid
+		}
+		digits = r.code + national
+	}
+
+	if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return "", ErrInvalid
+	}
+	return "+" + brazilNinthDigit(digits), nil
+}
+
+// national extrai o numero nacional, aceitando prefixo de discagem
+// (e codigo de operadora no Brasil) ou o codigo do pais sem "+"
+func (r region) national(digits string) (string, bool) {
+	if r.validLen(digits) {
+		return digits, true
+	}
+	if r.trunk != "" && strings.HasPrefix(digits, r.trunk) {
+		rest := digits[len(r.trunk):]
+		if r.validLen(rest) {
+			return rest, true
+		}
+		// Brasil: 0 + operadora (2 digitos) + DDD + numero
+		if r.code == "55" && len(rest) > 2 && r.validLen(rest[2:]) {
+			return rest[2:], true
+		}
+	}
+	if strings.HasPrefix(digits, r.code) && r.validLen(digits[len(r.code):]) {
+		return digits[len(r.code):], true
+	}
+	return "", false
+}
+
+func (r region) validLen(national string) bool {
+	return len(national) >= r.minLen && len(national) <= r.maxLen
+}
+
+// brazilNinthDigit insere o nono digito em celulares brasileiros com 8 digitos
+// (55 + DDD + [6-9]XXXXXXX); fixos comecam com 2-5 e ficam como estao
+func brazilNinthDigit(digits string) string {
+	if len(digits) != 12 || !strings.HasPrefix(digits, "55") {
+		return digits
+	}
+	ddd, subscriber := digits[2:4], digits[4:]
+	if ddd[0] == '0' || ddd[1] == '0' || subscriber[0] < '6' {
+		return digits
+	}
+	return "55" + ddd + "9" + subscriber
+}
+
+func onlyDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package phone
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Setenv("PHONE_DEFAULT_REGION", "")
+
+	tests := []struct {
+		name    string
+		raw     string
+		region  string
+		want    string
+		wantErr error
+	}{
+		{name: "empty", raw: "  ", want: ""},
+		{name: "formatted mobile", raw: "(11) 99999-9999", region: "BR", want: "+5511999999999"},
+		{name: "default region", raw: "11 99999-9999", want: "+5511999999999"},
+		{name: "mobile without ninth digit", raw: "11 8888-7777", region: "BR", want: "+5511988887777"},
+		{name: "landline keeps eight digits", raw: "1133334444", region: "BR", want: "+551133334444"},
+		{name: "plus without ninth digit", raw: "+55 11 8888-7777", want: "+5511988887777"},
+		{name: "plus landline", raw: "+55 11 3333-4444", want: "+551133334444"},
+		{name: "00 international prefix", raw: "0055 11 99999-9999", region: "BR", want: "+5511999999999"},
+		{name: "trunk prefix", raw: "0 11 99999-9999", region: "BR", want: "+5511999999999"},
+		{name: "trunk and carrier", raw: "0 21 11 99999-9999", region: "BR", want: "+5511999999999"},
+		{name: "country code without plus", raw: "5511999999999", region: "BR", want: "+5511999999999"},
+		{name: "other region", raw: "(415) 555-2671", region: "us", want: "+14155552671"},
+		{name: "plus other country", raw: "+44 20 7946 0958", region: "BR", want: "+442079460958"},
+		{name: "too short", raw: "123", region: "BR", wantErr: ErrInvalid},
+		{name: "letters only", raw: "abc", region: "BR", wantErr: ErrInvalid},
+		{name: "plus too long", raw: "+1234567890123456", wantErr: ErrInvalid},
+		{name: "plus leading zero", raw: "+0123456789", wantErr: ErrInvalid},
+		{name: "unknown region", raw: "11999999999", region: "XX", wantErr: ErrUnknownRegion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.raw, tt.region)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Normalize(%q, %q) error = %v, want %v", tt.raw, tt.region, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalize(%q, %q) error = %v", tt.raw, tt.region, err)
+			}
+			if got != tt.want {
+				t.Fatalf("Normalize(%q, %q) = %q, want %q", tt.raw, tt.region, got, tt.want)
+			}
+		})
+	}
+}
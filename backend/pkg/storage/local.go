@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// LocalBackend armazena arquivos em disco local.
+// URLs assinadas apontam para a rota publica de arquivos (ver VerifySignature).
+type LocalBackend struct {
+	dir        string
+	publicURL  string
+	signingKey []byte
+}
+
+// NewLocalBackend cria backend em disco local
+func NewLocalBackend(dir, publicURL, signingKey string) *LocalBackend {
+	return &LocalBackend{
+		dir:        dir,
+		publicURL:  publicURL,
+		signingKey: []byte(signingKey),
+	}
+}
+
+// Put grava o conteudo sob a chave
+func (b *LocalBackend) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	p, err := b.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("failed to create dir: %w", err)
+	}
+
+	// Gravar em arquivo temporario e renomear: leitores nunca veem arquivo parcial
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// Get le o conteudo de uma chave
+func (b *LocalBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	p, err := b.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return data, nil
+}
+
+// Exists verifica se a chave existe
+func (b *LocalBackend) Exists(ctx context.Context, key string) (bool, error) {
+	p, err := b.path(key)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Delete remove a chave
+func (b *LocalBackend) Delete(ctx context.Context, key string) error {
+	p, err := b.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// SignedURL retorna URL da rota publica com assinatura HMAC e expiracao
+func (b *LocalBackend) SignedURL(ctx context.Context, key, fileName string, expiry time.Duration) (string, error) {
+	if _, err := b.path(key); err != nil {
+		return "", err
+	}
+
+	expires := time.Now().Add(expiry).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	if fileName != "" {
+		query.Set("name", fileName)
+	}
+	query.Set("signature", b.sign(key, fileName, expires))
+
+	return b.publicURL + "/" + key + "?" + query.Encode(), nil
+}
+
+// VerifySignature valida assinatura e expiracao de uma URL gerada por SignedURL
+func (b *LocalBackend) VerifySignature(key, fileName, expires, signature string) error {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return ErrInvalidSignature
+	}
+
+	expected := b.sign(key, fileName, exp)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (b *LocalBackend) sign(key, fileName string, expires int64) string {
+	mac := hmac.New(sha256.New, b.signingKey)
+	fmt.Fprintf(mac, "%s\n%s\n%d", key, fileName, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path resolve a chave dentro do diretorio base, impedindo path traversal
+func (b *LocalBackend) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || clean[1:] != key {
+		return "", fmt.Errorf("invalid key: %q", key)
+	}
+	return filepath.Join(b.dir, filepath.FromSlash(clean)), nil
+}
+
+// Verify interface implementation
+var _ Backend = (*LocalBackend)(nil)
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalBackendPath(t *testing.T) {
+	dir := t.TempDir()
+	backend := NewLocalBackend(dir, "http://localhost/files", "secret")
+
+	tests := []struct {
+		name    string
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{name: "simple key", key: "media/abc.jpg", want: filepath.Join(dir, "media", "abc.jpg")},
+		{name: "empty key", key: "", wantErr: true},
+		{name: "parent traversal", key: "../etc/passwd", wantErr: true},
+		{name: "nested traversal", key: "media/../../etc/passwd", wantErr: true},
+		{name: "absolute key", key: "/etc/passwd", wantErr: true},
+		{name: "dot segment", key: "media/./abc.jpg", wantErr: true},
+		{name: "trailing slash", key: "media/", wantErr: true},
+		{name: "double slash", key: "media//abc.jpg", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := backend.path(tt.key)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("path(%q) = %q, want error", tt.key, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("path(%q) error = %v", tt.key, err)
+			}
+			if got != tt.want {
+				t.Fatalf("path(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalBackendSignedURL(t *testing.T) {
+	backend := NewLocalBackend(t.TempDir(), "http://localhost/files", "secret")
+
+	signed, err := backend.SignedURL(context.Background(), "media/abc.jpg", "foto.jpg", time.Hour)
+	if err != nil {
+		t.Fatalf("SignedURL() error = %v", err)
+	}
+	if !strings.HasPrefix(signed, "http://localhost/files/media/abc.jpg?") {
+		t.Fatalf("SignedURL() = %q, unexpected base", signed)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("SignedURL() = %q, not a URL: %v", signed, err)
+	}
+	query := u.Query()
+	expires, signature := query.Get("expires"), query.Get("signature")
+	if query.Get("name") != "foto.jpg" {
+		t.Fatalf("name = %q, want foto.jpg", query.Get("name"))
+	}
+
+	expired := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		key       string
+		fileName  string
+		expires   string
+		signature string
+		backend   *LocalBackend
+		wantErr   bool
+	}{
+		{name: "valid", key: "media/abc.jpg", fileName: "foto.jpg", expires: expires, signature: signature},
+		{name: "other key", key: "media/other.jpg", fileName: "foto.jpg", expires: expires, signature: signature, wantErr: true},
+		{name: "other file name", key: "media/abc.jpg", fileName: "x.exe", expires: expires, signature: signature, wantErr: true},
+		{name: "extended expiry", key: "media/abc.jpg", fileName: "foto.jpg", expires: expires + "0", signature: signature, wantErr: true},
+		{name: "expired", key: "media/abc.jpg", fileName: "foto.jpg", expires: expired, signature: backend.sign("media/abc.jpg", "foto.jpg", time.Now().Add(-time.Minute).Unix()), wantErr: true},
+		{name: "malformed expiry", key: "media/abc.jpg", fileName: "foto.jpg", expires: "soon", signature: signature, wantErr: true},
+		{name: "other signing key", key: "media/abc.jpg", fileName: "foto.jpg", expires: expires, signature: signature, backend: NewLocalBackend(t.TempDir(), "", "other"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := backend
+			if tt.backend != nil {
+				b = tt.backend
+			}
+			err := b.VerifySignature(tt.key, tt.fileName, tt.expires, tt.signature)
+			if tt.wantErr && !errors.Is(err, ErrInvalidSignature) {
+				t.Fatalf("VerifySignature() error = %v, want %v", err, ErrInvalidSignature)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("VerifySignature() error = %v", err)
+			}
+		})
+	}
+
+	if _, err := backend.SignedURL(context.Background(), "../secret", "", time.Hour); err == nil {
+		t.Fatal("SignedURL() must reject keys outside the base dir")
+	}
+}
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Backend armazena arquivos em bucket S3 compativel (AWS S3, MinIO)
+type S3Backend struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Backend cria backend S3 e garante que o bucket exista
+func NewS3Backend(ctx context.Context, config *Config) (*S3Backend, error) {
+	if config.S3Endpoint == "" || config.S3Bucket == "" {
+		return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required for s3 storage")
+	}
+
+	client, err := minio.New(config.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.S3AccessKey, config.S3SecretKey, ""),
+		Secure: config.S3UseSSL,
+		Region: config.S3Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, config.S3Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket %s: %w", config.S3Bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, config.S3Bucket, minio.MakeBucketOptions{Region: config.S3Region}); err != nil {
+			return nil, fmt.Errorf("failed to create bucket %s: %w", config.S3Bucket, err)
+		}
+	}
+
+	return &S3Backend{
+		client: client,
+		bucket: config.S3Bucket,
+	}, nil
+}
+
+// Put grava o conteudo sob a chave
+func (b *S3Backend) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	_, err := b.client.PutObject(ctx, b.bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	return err
+}
+
+// Get le o conteudo de uma chave
+func (b *S3Backend) Get(ctx context.Context, key string) ([]byte, error) {
+	obj, err := b.client.GetObject(ctx, b.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, b.translate(err)
+	}
+	defer obj.Close()
+
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		return nil, b.translate(err)
+	}
+	return data, nil
+}
+
+// Exists verifica se a chave existe
+func (b *S3Backend) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := b.client.StatObject(ctx, b.bucket, key, minio.StatObjectOptions{})
+	if err == nil {
+		return true, nil
+	}
+	if err = b.translate(err); err == ErrNotFound {
+		return false, nil
+	}
+	return false, err
+}
+
+// Delete remove a chave
+func (b *S3Backend) Delete(ctx context.Context, key string) error {
+	return b.client.RemoveObject(ctx, b.bucket, key, minio.RemoveObjectOptions{})
+}
+
+// SignedURL retorna URL pre-assinada do S3
+func (b *S3Backend) SignedURL(ctx context.Context, key, fileName string, expiry time.Duration) (string, error) {
+	params := url.Values{}
+	if fileName != "" {
+		params.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	}
+
+	u, err := b.client.PresignedGetObject(ctx, b.bucket, key, expiry, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign %s: %w", key, err)
+	}
+	return u.String(), nil
+}
+
+func (b *S3Backend) translate(err error) error {
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return ErrNotFound
+	}
+	return err
+}
+
+// Verify interface implementation
+var _ Backend = (*S3Backend)(nil)
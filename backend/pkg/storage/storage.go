@@ -0,0 +1,236 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// BackendLocal armazena arquivos em disco local
+	BackendLocal = "local"
+	// BackendS3 armazena arquivos em bucket S3 compativel (AWS, MinIO)
+	BackendS3 = "s3"
+
+	// DefaultMaxSize tamanho maximo padrao de um arquivo (limite de midia do WhatsApp)
+	DefaultMaxSize = 16 << 20
+	// DefaultURLExpiry validade padrao das URLs assinadas
+	DefaultURLExpiry = time.Hour
+)
+
+var (
+	// ErrNotFound arquivo nao existe no storage
+	ErrNotFound = errors.New("object not found")
+	// ErrTooLarge arquivo excede o tamanho maximo
+	ErrTooLarge = errors.New("object too large")
+	// ErrInvalidSignature URL assinada invalida ou expirada
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+)
+
+// Backend interface que todo backend de armazenamento deve implementar
+type Backend interface {
+	// Put grava o conteudo sob a chave
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+
+	// Get le o conteudo de uma chave (ErrNotFound se nao existir)
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Exists verifica se a chave existe
+	Exists(ctx context.Context, key string) (bool, error)
+
+	// Delete remove a chave
+	Delete(ctx context.Context, key string) error
+
+	// SignedURL retorna URL de download que expira apos expiry
+	SignedURL(ctx context.Context, key, fileName string, expiry time.Duration) (string, error)
+}
+
+// Config configuracao do storage
+type Config struct {
+	Backend   string
+	MaxSize   int64
+	URLExpiry time.Duration
+
+	// Local
+	LocalDir   string
+	PublicURL  string // URL base da rota publica de arquivos assinados
+	SigningKey string
+
+	// S3
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+}
+
+// DefaultConfig retorna configuracao a partir do ambiente
+func DefaultConfig() *Config {
+	config := &Config{
+		Backend:     getEnv("STORAGE_BACKEND", BackendLocal),
+		MaxSize:     DefaultMaxSize,
+		URLExpiry:   DefaultURLExpiry,
+		LocalDir:    getEnv("UPLOAD_DIR", "./data/attachments"),
+		PublicURL:   strings.TrimRight(getEnv("STORAGE_PUBLIC_URL", "http://localhost:8080/api/v1/files"), "/"),
+		SigningKey:  os.Getenv("STORAGE_SIGNING_KEY"),
+		S3Endpoint:  os.Getenv("S3_ENDPOINT"),
+		S3Region:    getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:    os.Getenv("S3_BUCKET"),
+		S3AccessKey: os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey: os.Getenv("S3_SECRET_KEY"),
+		S3UseSSL:    getEnv("S3_USE_SSL", "true") == "true",
+	}
+
+	if v, err := strconv.ParseInt(os.Getenv("STORAGE_MAX_SIZE"), 10, 64); err == nil && v > 0 {
+		config.MaxSize = v
+	}
+	if v, err := time.ParseDuration(os.Getenv("STORAGE_URL_EXPIRY")); err == nil && v > 0 {
+		config.URLExpiry = v
+	}
+
+	return config
+}
+
+// Object arquivo gravado no storage
+type Object struct {
+	Key         string
+	Size        int64
+	SHA256      string
+	ContentType string
+}
+
+// Store armazenamento enderecado por conteudo sobre um Backend.
+// Arquivos iguais (mesmo SHA-256) sao gravados uma unica vez.
+type Store struct {
+	backend   Backend
+	maxSize   int64
+	urlExpiry time.Duration
+}
+
+// New cria store com o backend definido na configuracao
+func New(ctx context.Context, config *Config) (*Store, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	var backend Backend
+	switch config.Backend {
+	case BackendLocal, "":
+		signingKey := config.SigningKey
+		if signingKey == "" {
+			key, err := randomKey()
+			if err != nil {
+				return nil, err
+			}
+			signingKey = key
+			log.Printf("[Storage] STORAGE_SIGNING_KEY not set, signed URLs will not survive restarts")
+		}
+		backend = NewLocalBackend(config.LocalDir, config.PublicURL, signingKey)
+
+	case BackendS3:
+		s3, err := NewS3Backend(ctx, config)
+		if err != nil {
+			return nil, err
+		}
+		backend = s3
+
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", config.Backend)
+	}
+
+	log.Printf("[Storage] Using %s backend", config.Backend)
+	return NewStore(backend, config.MaxSize, config.URLExpiry), nil
+}
+
+// NewStore cria store sobre um backend
+func NewStore(backend Backend, maxSize int64, urlExpiry time.Duration) *Store {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	if urlExpiry <= 0 {
+		urlExpiry = DefaultURLExpiry
+	}
+	return &Store{
+		backend:   backend,
+		maxSize:   maxSize,
+		urlExpiry: urlExpiry,
+	}
+}
+
+// Backend retorna o backend subjacente
+func (s *Store) Backend() Backend {
+	return s.backend
+}
+
+// MaxSize retorna tamanho maximo aceito
+func (s *Store) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Save grava o conteudo e retorna o objeto; conteudo repetido reutiliza a mesma chave
+func (s *Store) Save(ctx context.Context, data []byte, contentType string) (*Object, error) {
+	if int64(len(data)) > s.maxSize {
+		return nil, fmt.Errorf("%w: %d bytes (max %d)", ErrTooLarge, len(data), s.maxSize)
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	obj := &Object{
+		Key:         ContentKey(hash),
+		Size:        int64(len(data)),
+		SHA256:      hash,
+		ContentType: contentType,
+	}
+
+	exists, err := s.backend.Exists(ctx, obj.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check object: %w", err)
+	}
+	if exists {
+		return obj, nil
+	}
+
+	if err := s.backend.Put(ctx, obj.Key, data, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store object: %w", err)
+	}
+	return obj, nil
+}
+
+// Load le o conteudo de uma chave
+func (s *Store) Load(ctx context.Context, key string) ([]byte, error) {
+	return s.backend.Get(ctx, key)
+}
+
+// URL retorna URL assinada de download
+func (s *Store) URL(ctx context.Context, key, fileName string) (string, error) {
+	return s.backend.SignedURL(ctx, key, fileName, s.urlExpiry)
+}
+
+// ContentKey retorna a chave de um conteudo pelo seu SHA-256
+func ContentKey(hash string) string {
+	return "sha256/" + hash[:2] + "/" + hash
+}
+
+func randomKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
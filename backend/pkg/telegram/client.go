@@ -0,0 +1,628 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAPIURL URL base oficial da Bot API
+	DefaultAPIURL = "https://api.telegram.org"
+
+	pollTimeout    = 30 * time.Second
+	pollRetryDelay = 5 * time.Second
+)
+
+// allowedUpdates tipos de update recebidos
+var allowedUpdates = []string{"message", "edited_message"}
+
+// ErrInvalidSecret secret do webhook nao confere
+var ErrInvalidSecret = errors.New("invalid webhook secret")
+
+// ErrMissingSecret modo webhook sem secret configurado
+var ErrMissingSecret = errors.New("webhook secret is required")
+
+// Config configuracao do cliente Telegram
+type Config struct {
+	// APIURL URL base da Bot API (configuravel para testes com servidor fake)
+	APIURL string
+	// WebhookBaseURL URL publica para webhooks; vazio usa long-polling
+	WebhookBaseURL string
+}
+
+// DefaultConfig retorna configuracao a partir do ambiente
+func DefaultConfig() *Config {
+	apiURL := os.Getenv("TELEGRAM_API_URL")
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	return &Config{
+		APIURL:         strings.TrimRight(apiURL, "/"),
+		WebhookBaseURL: strings.TrimRight(os.Getenv("TELEGRAM_WEBHOOK_URL"), "/"),
+	}
+}
+
+// Client cliente da Bot API do Telegram
+type Client struct {
+	token         string
+	config        *Config
+	http          *http.Client
+	handler       EventHandler
+	status        Status
+	mode          Mode
+	username      string
+	webhookSecret string
+	offset        int64
+	cancel        context.CancelFunc
+	mu            sync.RWMutex
+}
+
+// NewClient cria novo cliente Telegram
+func NewClient(token string, config *Config) *Client {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return &Client{
+		token:  token,
+		config: config,
+		http:   &http.Client{Timeout: pollTimeout + 30*time.Second},
+		status: StatusDisconnected,
+	}
+}
+
+// SetEventHandler define o handler de eventos
+func (c *Client) SetEventHandler(handler EventHandler) {
+	c.handler = handler
+}
+
+// SetWebhookSecret define o secret_token do webhook.
+// Deve ser persistido: todas as replicas e reinicios registram o mesmo valor.
+func (c *Client) SetWebhookSecret(secret string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.webhookSecret = secret
+}
+
+// Connect valida o token e inicia o recebimento de updates.
+// Se webhookURL for vazio usa long-polling, senao registra o webhook.
+func (c *Client) Connect(ctx context.Context, webhookURL string) error {
+	c.mu.Lock()
+	c.status = StatusConnecting
+	c.mu.Unlock()
+
+	me, err := c.GetMe(ctx)
+	if err != nil {
+		c.setStatus(StatusDisconnected)
+		return fmt.Errorf("failed to validate bot token: %w", err)
+	}
+
+	if webhookURL != "" {
+		c.mu.RLock()
+		secret := c.webhookSecret
+		c.mu.RUnlock()
+		if secret == "" {
+			c.setStatus(StatusDisconnected)
+			return ErrMissingSecret
+		}
+		if err := c.call(ctx, "setWebhook", map[string]interface{}{
+			"url":             webhookURL,
+			"secret_token":    secret,
+			"allowed_updates": allowedUpdates,
+		}, nil); err != nil {
+			c.setStatus(StatusDisconnected)
+			return fmt.Errorf("failed to set webhook: %w", err)
+		}
+
+		c.mu.Lock()
+		c.mode = ModeWebhook
+		c.mu.Unlock()
+	} else {
+		// getUpdates nao funciona com webhook ativo
+		if err := c.call(ctx, "deleteWebhook", nil, nil); err != nil {
+			c.setStatus(StatusDisconnected)
+			return fmt.Errorf("failed to delete webhook: %w", err)
+		}
+
+		pollCtx, cancel := context.WithCancel(context.Background())
+		c.mu.Lock()
+		c.mode = ModePolling
+		c.cancel = cancel
+		c.mu.Unlock()
+
+		go c.pollLoop(pollCtx)
+	}
+
+	c.mu.Lock()
+	c.status = StatusConnected
+	c.username = me.Username
+	c.mu.Unlock()
+
+	log.Printf("[Telegram] Connected as @%s (%s)", me.Username, c.Mode())
+
+	if c.handler != nil {
+		c.handler.OnConnected(me.Username)
+	}
+
+	return nil
+}
+
+// Disconnect interrompe o recebimento de updates
+func (c *Client) Disconnect(ctx context.Context) error {
+	c.mu.Lock()
+	cancel := c.cancel
+	mode := c.mode
+	c.cancel = nil
+	c.status = StatusDisconnected
+	c.webhookSecret = ""
+	c.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	if mode == ModeWebhook {
+		if err := c.call(ctx, "deleteWebhook", nil, nil); err != nil {
+			return fmt.Errorf("failed to delete webhook: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Status retorna status atual
+func (c *Client) Status() Status {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status
+}
+
+// Mode retorna o modo de recebimento atual
+func (c *Client) Mode() Mode {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.mode
+}
+
+// Username retorna o username do bot
+func (c *Client) Username() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.username
+}
+
+// IsConnected verifica se esta conectado
+func (c *Client) IsConnected() bool {
+	return c.Status() == StatusConnected
+}
+
+// HandleWebhook processa update recebido via webhook
+func (c *Client) HandleWebhook(secret string, update Update) error {
+	c.mu.RLock()
+	expected := c.webhookSecret
+	webhook := c.mode == ModeWebhook
+	c.mu.RUnlock()
+
+	if !webhook || expected == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(expected)) != 1 {
+		return ErrInvalidSecret
+	}
+
+	c.dispatch(update)
+	return nil
+}
+
+// GetMe retorna dados do bot
+func (c *Client) GetMe(ctx context.Context) (*User, error) {
+	var me User
+	if err := c.call(ctx, "getMe", nil, &me); err != nil {
+		return nil, err
+	}
+	return &me, nil
+}
+
+// SendText envia mensagem de texto.
+// replyTo e o ID da mensagem respondida (vazio para nenhuma).
+func (c *Client) SendText(ctx context.Context, chatID, text, replyTo string) (string, error) {
+	if !c.IsConnected() {
+		return "", fmt.Errorf("client not connected")
+	}
+
+	params := map[string]interface{}{
+		"chat_id": chatID,
+		"text":    text,
+	}
+	if err := setReply(params, replyTo); err != nil {
+		return "", err
+	}
+
+	var msg Message
+	if err := c.call(ctx, "sendMessage", params, &msg); err != nil {
+		return "", fmt.Errorf("failed to send message: %w", err)
+	}
+
+	return strconv.FormatInt(msg.MessageID, 10), nil
+}
+
+// SendMedia envia midia por upload (data) ou por URL
+func (c *Client) SendMedia(ctx context.Context, chatID string, mediaType MediaType, data []byte, url, fileName, caption, replyTo string) (string, error) {
+	if !c.IsConnected() {
+		return "", fmt.Errorf("client not connected")
+	}
+
+	method, field, err := sendMethod(mediaType)
+	if err != nil {
+		return "", err
+	}
+
+	params := map[string]string{"chat_id": chatID}
+	if caption != "" && mediaType != MediaTypeSticker {
+		params["caption"] = caption
+	}
+	if replyTo != "" {
+		reply, err := replyParameters(replyTo)
+		if err != nil {
+			return "", err
+		}
+		encoded, err := json.Marshal(reply)
+		if err != nil {
+			return "", err
+		}
+		params["reply_parameters"] = string(encoded)
+	}
+
+	var msg Message
+	if len(data) > 0 {
+		if fileName == "" {
+			fileName = field
+		}
+		err = c.callMultipart(ctx, method, params, field, fileName, data, &msg)
+	} else if url != "" {
+		body := make(map[string]interface{}, len(params)+1)
+		for k, v := range params {
+			body[k] = v
+		}
+		body[field] = url
+		err = c.call(ctx, method, body, &msg)
+	} else {
+		return "", fmt.Errorf("media requires data or url")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to send %s: %w", mediaType, err)
+	}
+
+	return strconv.FormatInt(msg.MessageID, 10), nil
+}
+
+// SendLocation envia localizacao (com nome/endereco usa sendVenue)
+func (c *Client) SendLocation(ctx context.Context, chatID string, latitude, longitude float64, title, address, replyTo string) (string, error) {
+	if !c.IsConnected() {
+		return "", fmt.Errorf("client not connected")
+	}
+
+	method := "sendLocation"
+	params := map[string]interface{}{
+		"chat_id":   chatID,
+		"latitude":  latitude,
+		"longitude": longitude,
+	}
+	if title != "" || address != "" {
+		method = "sendVenue"
+		params["title"] = title
+		params["address"] = address
+	}
+	if err := setReply(params, replyTo); err != nil {
+		return "", err
+	}
+
+	var msg Message
+	if err := c.call(ctx, method, params, &msg); err != nil {
+		return "", fmt.Errorf("failed to send location: %w", err)
+	}
+
+	return strconv.FormatInt(msg.MessageID, 10), nil
+}
+
+// SendContact envia cartao de contato
+func (c *Client) SendContact(ctx context.Context, chatID, phone, firstName, vcard, replyTo string) (string, error) {
+	if !c.IsConnected() {
+		return "", fmt.Errorf("client not connected")
+	}
+
+	params := map[string]interface{}{
+		"chat_id":      chatID,
+		"phone_number": phone,
+		"first_name":   firstName,
+	}
+	if vcard != "" {
+		params["vcard"] = vcard
+	}
+	if err := setReply(params, replyTo); err != nil {
+		return "", err
+	}
+
+	var msg Message
+	if err := c.call(ctx, "sendContact", params, &msg); err != nil {
+		return "", fmt.Errorf("failed to send contact: %w", err)
+	}
+
+	return strconv.FormatInt(msg.MessageID, 10), nil
+}
+
+// SetReaction define a reacao do bot na mensagem (emoji vazio remove)
+func (c *Client) SetReaction(ctx context.Context, chatID, messageID, emoji string) error {
+	if !c.IsConnected() {
+		return fmt.Errorf("client not connected")
+	}
+
+	id, err := strconv.ParseInt(messageID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid message id: %s", messageID)
+	}
+
+	reaction := []map[string]string{}
+	if emoji != "" {
+		reaction = append(reaction, map[string]string{"type": "emoji", "emoji": emoji})
+	}
+
+	if err := c.call(ctx, "setMessageReaction", map[string]interface{}{
+		"chat_id":    chatID,
+		"message_id": id,
+		"reaction":   reaction,
+	}, nil); err != nil {
+		return fmt.Errorf("failed to set reaction: %w", err)
+	}
+	return nil
+}
+
+// EditText edita o texto de uma mensagem enviada pelo bot
+func (c *Client) EditText(ctx context.Context, chatID, messageID, text string) error {
+	if !c.IsConnected() {
+		return fmt.Errorf("client not connected")
+	}
+
+	id, err := strconv.ParseInt(messageID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid message id: %s", messageID)
+	}
+
+	if err := c.call(ctx, "editMessageText", map[string]interface{}{
+		"chat_id":    chatID,
+		"message_id": id,
+		"text":       text,
+	}, nil); err != nil {
+		return fmt.Errorf("failed to edit message: %w", err)
+	}
+	return nil
+}
+
+// DeleteMessage apaga mensagem do chat
+func (c *Client) DeleteMessage(ctx context.Context, chatID, messageID string) error {
+	if !c.IsConnected() {
+		return fmt.Errorf("client not connected")
+	}
+
+	id, err := strconv.ParseInt(messageID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid message id: %s", messageID)
+	}
+
+	if err := c.call(ctx, "deleteMessage", map[string]interface{}{
+		"chat_id":    chatID,
+		"message_id": id,
+	}, nil); err != nil {
+		return fmt.Errorf("failed to delete message: %w", err)
+	}
+	return nil
+}
+
+// SendChatAction exibe acao do bot no chat (ex: "typing", "record_voice") por alguns segundos
+func (c *Client) SendChatAction(ctx context.Context, chatID, action string) error {
+	if !c.IsConnected() {
+		return fmt.Errorf("client not connected")
+	}
+
+	if err := c.call(ctx, "sendChatAction", map[string]interface{}{
+		"chat_id": chatID,
+		"action":  action,
+	}, nil); err != nil {
+		return fmt.Errorf("failed to send chat action: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) pollLoop(ctx context.Context) {
+	log.Printf("[Telegram] Long-polling started for @%s", c.Username())
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("[Telegram] Long-polling stopped for @%s", c.Username())
+			return
+		default:
+		}
+
+		var updates []Update
+		err := c.call(ctx, "getUpdates", map[string]interface{}{
+			"offset":          c.offset,
+			"timeout":         int(pollTimeout.Seconds()),
+			"allowed_updates": allowedUpdates,
+		}, &updates)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			log.Printf("[Telegram] getUpdates failed: %v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(pollRetryDelay):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			if update.UpdateID >= c.offset {
+				c.offset = update.UpdateID + 1
+			}
+			c.dispatch(update)
+		}
+	}
+}
+
+func (c *Client) dispatch(update Update) {
+	if c.handler == nil {
+		return
+	}
+
+	switch {
+	case update.Message != nil:
+		if event := parseMessage(update.Message); event != nil {
+			c.handler.OnMessage(*event)
+		}
+	case update.EditedMessage != nil:
+		if event := parseMessage(update.EditedMessage); event != nil {
+			event.Edited = true
+			c.handler.OnMessage(*event)
+		}
+	}
+}
+
+func (c *Client) endpoint(method string) string {
+	return fmt.Sprintf("%s/bot%s/%s", c.config.APIURL, c.token, method)
+}
+
+// call executa metodo da Bot API com corpo JSON
+func (c *Client) call(ctx context.Context, method string, params interface{}, out interface{}) error {
+	var body io.Reader
+	if params != nil {
+		payload, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to marshal params: %w", err)
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(method), body)
+	if err != nil {
+		return err
+	}
+	if params != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.do(req, out)
+}
+
+// callMultipart executa metodo da Bot API com upload de arquivo
+func (c *Client) callMultipart(ctx context.Context, method string, params map[string]string, field, fileName string, data []byte, out interface{}) error {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	for k, v := range params {
+		if err := writer.WriteField(k, v); err != nil {
+			return err
+		}
+	}
+
+	part, err := writer.CreateFormFile(field, fileName)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(method), &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return c.do(req, out)
+}
+
+func (c *Client) do(req *http.Request, out interface{}) error {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return c.redactError(err)
+	}
+	defer resp.Body.Close()
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("failed to decode response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if !apiResp.OK {
+		return fmt.Errorf("telegram api error %d: %s", apiResp.ErrorCode, apiResp.Description)
+	}
+
+	if out != nil && len(apiResp.Result) > 0 {
+		if err := json.Unmarshal(apiResp.Result, out); err != nil {
+			return fmt.Errorf("failed to decode result: %w", err)
+		}
+	}
+	return nil
+}
+
+// redactError remove o token da URL incluida nos erros de transporte (*url.Error),
+// para que falhas de rede nao exponham o token nos logs
+func (c *Client) redactError(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+	return fmt.Errorf("%s %s: %w", urlErr.Op, c.redact(urlErr.URL), urlErr.Err)
+}
+
+// redact substitui o token do bot em um texto
+func (c *Client) redact(s string) string {
+	if c.token == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, c.token, "<token>")
+}
+
+// setReply adiciona reply_parameters aos parametros se replyTo nao for vazio
+func setReply(params map[string]interface{}, replyTo string) error {
+	if replyTo == "" {
+		return nil
+	}
+	reply, err := replyParameters(replyTo)
+	if err != nil {
+		return err
+	}
+	params["reply_parameters"] = reply
+	return nil
+}
+
+func (c *Client) setStatus(status Status) {
+	c.mu.Lock()
+	c.status = status
+	c.mu.Unlock()
+}
+
+// GenerateWebhookSecret gera novo secret_token para o webhook
+func GenerateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
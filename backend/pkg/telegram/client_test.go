@@ -0,0 +1,74 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTransportErrorsDoNotLeakToken(t *testing.T) {
+	const token = "123456:SECRET-token"
+
+	// Servidor fechado: a chamada falha no transporte com *url.Error
+	srv := httptest.NewServer(nil)
+	srv.Close()
+
+	c := NewClient(token, &Config{APIURL: srv.URL})
+	err := c.call(context.Background(), "getUpdates", nil, nil)
+	if err == nil {
+		t.Fatal("call succeeded against a closed server")
+	}
+	if strings.Contains(err.Error(), token) {
+		t.Fatalf("error exposes bot token: %v", err)
+	}
+	if !strings.Contains(err.Error(), "/bot<token>/getUpdates") {
+		t.Errorf("error = %v, want redacted endpoint", err)
+	}
+}
+
+func TestHandleWebhookSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		stored  string
+		mode    Mode
+		secret  string
+		wantErr error
+	}{
+		{name: "matching secret", stored: "abc123", mode: ModeWebhook, secret: "abc123"},
+		{name: "wrong secret", stored: "abc123", mode: ModeWebhook, secret: "abc124", wantErr: ErrInvalidSecret},
+		{name: "empty secret", stored: "abc123", mode: ModeWebhook, secret: "", wantErr: ErrInvalidSecret},
+		{name: "no stored secret", mode: ModeWebhook, secret: "", wantErr: ErrInvalidSecret},
+		{name: "polling mode", stored: "abc123", mode: ModePolling, secret: "abc123", wantErr: ErrInvalidSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient("123456:token", &Config{})
+			c.SetWebhookSecret(tt.stored)
+			c.mode = tt.mode
+
+			err := c.HandleWebhook(tt.secret, Update{})
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("HandleWebhook() error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("HandleWebhook() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConnectWebhookRequiresSecret(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"username":"bot"}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("123456:token", &Config{APIURL: srv.URL})
+	if err := c.Connect(context.Background(), "https://example.com/webhook"); !errors.Is(err, ErrMissingSecret) {
+		t.Fatalf("Connect() error = %v, want %v", err, ErrMissingSecret)
+	}
+}
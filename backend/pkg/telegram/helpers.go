@@ -0,0 +1,123 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseMessage converte mensagem da Bot API em MessageEvent
+func parseMessage(msg *Message) *MessageEvent {
+	event := &MessageEvent{
+		ID:         strconv.FormatInt(msg.MessageID, 10),
+		ChatID:     strconv.FormatInt(msg.Chat.ID, 10),
+		SenderName: ChatName(msg.Chat),
+		Timestamp:  time.Unix(msg.Date, 0),
+		Raw:        msg,
+	}
+	if msg.From != nil {
+		event.SenderID = strconv.FormatInt(msg.From.ID, 10)
+		if name := UserName(*msg.From); name != "" {
+			event.SenderName = name
+		}
+	}
+	if msg.ReplyToMessage != nil {
+		event.ReplyToID = strconv.FormatInt(msg.ReplyToMessage.MessageID, 10)
+	}
+
+	switch {
+	case msg.Text != "":
+		event.Content = msg.Text
+	case len(msg.Photo) > 0:
+		// A ultima PhotoSize e a de maior resolucao
+		event.MediaType = MediaTypeImage
+		event.FileID = msg.Photo[len(msg.Photo)-1].FileID
+		event.Content = msg.Caption
+	case msg.Video != nil:
+		event.MediaType = MediaTypeVideo
+		event.FileID = msg.Video.FileID
+		event.MimeType = msg.Video.MimeType
+		event.Content = msg.Caption
+	case msg.Audio != nil:
+		event.MediaType = MediaTypeAudio
+		event.FileID = msg.Audio.FileID
+		event.FileName = msg.Audio.FileName
+		event.MimeType = msg.Audio.MimeType
+		event.Content = msg.Caption
+	case msg.Voice != nil:
+		event.MediaType = MediaTypeAudio
+		event.FileID = msg.Voice.FileID
+		event.MimeType = msg.Voice.MimeType
+	case msg.Document != nil:
+		event.MediaType = MediaTypeDocument
+		event.FileID = msg.Document.FileID
+		event.FileName = msg.Document.FileName
+		event.MimeType = msg.Document.MimeType
+		event.Content = msg.Caption
+		if event.Content == "" {
+			event.Content = msg.Document.FileName
+		}
+	case msg.Sticker != nil:
+		event.MediaType = MediaTypeSticker
+		event.FileID = msg.Sticker.FileID
+		event.Content = msg.Sticker.Emoji
+	default:
+		return nil
+	}
+
+	return event
+}
+
+// replyParameters monta reply_parameters para responder a uma mensagem
+func replyParameters(replyTo string) (map[string]interface{}, error) {
+	id, err := strconv.ParseInt(replyTo, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message id: %s", replyTo)
+	}
+	return map[string]interface{}{
+		"message_id":                  id,
+		"allow_sending_without_reply": true,
+	}, nil
+}
+
+// sendMethod retorna metodo da Bot API e campo do arquivo para o tipo de midia
+func sendMethod(mediaType MediaType) (method, field string, err error) {
+	switch mediaType {
+	case MediaTypeImage:
+		return "sendPhoto", "photo", nil
+	case MediaTypeVideo:
+		return "sendVideo", "video", nil
+	case MediaTypeAudio:
+		return "sendAudio", "audio", nil
+	case MediaTypeDocument:
+		return "sendDocument", "document", nil
+	case MediaTypeSticker:
+		return "sendSticker", "sticker", nil
+	case MediaTypeVoice:
+		return "sendVoice", "voice", nil
+	default:
+		return "", "", fmt.Errorf("unsupported media type: %s", mediaType)
+	}
+}
+
+// UserName retorna nome de exibicao do usuario
+func UserName(u User) string {
+	name := strings.TrimSpace(u.FirstName + " " + u.LastName)
+	if name == "" && u.Username != "" {
+		name = "@" + u.Username
+	}
+	return name
+}
+
+// ChatName retorna nome de exibicao do chat
+func ChatName(chat Chat) string {
+	if chat.Title != "" {
+		return chat.Title
+	}
+	name := strings.TrimSpace(chat.FirstName + " " + chat.LastName)
+	if name == "" && chat.Username != "" {
+		name = "@" + chat.Username
+	}
+	return name
+}
@@ -0,0 +1,137 @@
+package telegram
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Status do cliente Telegram
+type Status string
+
+const (
+	StatusDisconnected Status = "disconnected"
+	StatusConnecting   Status = "connecting"
+	StatusConnected    Status = "connected"
+)
+
+// Mode modo de recebimento de updates
+type Mode string
+
+const (
+	ModePolling Mode = "polling"
+	ModeWebhook Mode = "webhook"
+)
+
+// MediaType tipo de midia
+type MediaType string
+
+const (
+	MediaTypeNone     MediaType = ""
+	MediaTypeImage    MediaType = "image"
+	MediaTypeVideo    MediaType = "video"
+	MediaTypeAudio    MediaType = "audio"
+	MediaTypeDocument MediaType = "document"
+	MediaTypeSticker  MediaType = "sticker"
+	MediaTypeVoice    MediaType = "voice" // Apenas envio (sendVoice)
+)
+
+// MessageEvent mensagem recebida do Telegram
+type MessageEvent struct {
+	ID         string
+	ChatID     string
+	SenderID   string
+	SenderName string
+	Content    string
+	MediaType  MediaType
+	FileID     string
+	FileName   string
+	MimeType   string
+	ReplyToID  string // ID da mensagem respondida, se houver
+	Edited     bool   // Mensagem editada (edited_message)
+	Timestamp  time.Time
+	Raw        *Message
+}
+
+// EventHandler interface para processar eventos do cliente
+type EventHandler interface {
+	OnMessage(event MessageEvent)
+	OnConnected(username string)
+	OnDisconnected()
+}
+
+// ========== Tipos da Bot API ==========
+
+// apiResponse envelope padrao das respostas da Bot API
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	ErrorCode   int             `json:"error_code,omitempty"`
+	Description string          `json:"description,omitempty"`
+}
+
+// User usuario ou bot do Telegram
+type User struct {
+	ID        int64  `json:"id"`
+	IsBot     bool   `json:"is_bot"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name,omitempty"`
+	Username  string `json:"username,omitempty"`
+}
+
+// Chat chat do Telegram
+type Chat struct {
+	ID        int64  `json:"id"`
+	Type      string `json:"type"`
+	Title     string `json:"title,omitempty"`
+	Username  string `json:"username,omitempty"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+}
+
+// PhotoSize tamanho de uma foto
+type PhotoSize struct {
+	FileID   string `json:"file_id"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	FileSize int64  `json:"file_size,omitempty"`
+}
+
+// File arquivo generico (documento, audio, video, voz)
+type File struct {
+	FileID   string `json:"file_id"`
+	FileName string `json:"file_name,omitempty"`
+	MimeType string `json:"mime_type,omitempty"`
+	FileSize int64  `json:"file_size,omitempty"`
+}
+
+// Sticker sticker do Telegram
+type Sticker struct {
+	FileID string `json:"file_id"`
+	Emoji  string `json:"emoji,omitempty"`
+}
+
+// Message mensagem do Telegram
+type Message struct {
+	MessageID int64       `json:"message_id"`
+	From      *User       `json:"from,omitempty"`
+	Chat      Chat        `json:"chat"`
+	Date      int64       `json:"date"`
+	Text      string      `json:"text,omitempty"`
+	Caption   string      `json:"caption,omitempty"`
+	Photo     []PhotoSize `json:"photo,omitempty"`
+	Video     *File       `json:"video,omitempty"`
+	Audio     *File       `json:"audio,omitempty"`
+	Voice     *File       `json:"voice,omitempty"`
+	Document  *File       `json:"document,omitempty"`
+	Sticker   *Sticker    `json:"sticker,omitempty"`
+	EditDate  int64       `json:"edit_date,omitempty"`
+
+	ReplyToMessage *Message `json:"reply_to_message,omitempty"`
+}
+
+// Update update recebido via getUpdates ou webhook
+type Update struct {
+	UpdateID      int64    `json:"update_id"`
+	Message       *Message `json:"message,omitempty"`
+	EditedMessage *Message `json:"edited_message,omitempty"`
+}
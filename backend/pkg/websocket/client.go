@@ -0,0 +1,249 @@
+package websocket
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second    // Prazo de cada escrita no socket
+	pongWait       = 60 * time.Second    // Prazo para receber o pong
+	pingPeriod     = (pongWait * 9) / 10 // Intervalo dos pings (antes de pongWait)
+	maxMessageSize = 64 << 10            // Tamanho maximo das mensagens do cliente
+	sendBufferSize = 256                 // Mensagens pendentes antes de o cliente ser considerado lento
+)
+
+// Client conexao de um usuario autenticado com seus acessos e assinaturas.
+// Sem assinaturas o cliente recebe os eventos de todos os inboxes que pode ver.
+type Client struct {
+	conn      *websocket.Conn // nil em clientes sem socket (SSE)
+	userID    string
+	send      chan interface{} // Fila de escrita consumida por WritePump
+	done      chan struct{}    // Fechado quando o hub remove o cliente
+	closeOnce sync.Once
+
+	mu            sync.RWMutex
+	allInboxes    bool              // Admin: ve todos os inboxes
+	allowed       map[string]bool   // Inboxes em que o usuario e membro
+	inboxes       map[string]bool   // Inboxes assinados
+	conversations map[string]string // Conversas assinadas -> inbox
+}
+
+// NewClient cria cliente para a conexao do usuario (sem acesso a inboxes ate SetAccess).
+// A leitura passa a exigir pong a cada pongWait; a escrita e feita por WritePump.
+func NewClient(conn *websocket.Conn, userID string) *Client {
+	conn.SetReadLimit(maxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	client := NewStreamClient(userID)
+	client.conn = conn
+	return client
+}
+
+// NewStreamClient cria cliente sem socket (ex.: SSE); o transporte consome Messages
+func NewStreamClient(userID string) *Client {
+	return &Client{
+		userID:        userID,
+		send:          make(chan interface{}, sendBufferSize),
+		done:          make(chan struct{}),
+		allowed:       make(map[string]bool),
+		inboxes:       make(map[string]bool),
+		conversations: make(map[string]string),
+	}
+}
+
+// UserID retorna o usuario da conexao
+func (c *Client) UserID() string {
+	return c.userID
+}
+
+// SetAccess define os inboxes visiveis e descarta assinaturas que deixaram de ser permitidas
+func (c *Client) SetAccess(all bool, inboxIDs []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.allInboxes = all
+	c.allowed = make(map[string]bool, len(inboxIDs))
+	for _, id := range inboxIDs {
+		c.allowed[id] = true
+	}
+	for id := range c.inboxes {
+		if !c.canAccess(id) {
+			delete(c.inboxes, id)
+		}
+	}
+	for id, inboxID := range c.conversations {
+		if !c.canAccess(inboxID) {
+			delete(c.conversations, id)
+		}
+	}
+}
+
+// access retorna se o cliente ve todos os inboxes e os inboxes permitidos
+func (c *Client) access() (all bool, inboxIDs []string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.allInboxes {
+		return true, nil
+	}
+	inboxIDs = make([]string, 0, len(c.allowed))
+	for id := range c.allowed {
+		inboxIDs = append(inboxIDs, id)
+	}
+	return false, inboxIDs
+}
+
+// CanAccess verifica se o usuario pode ver eventos do inbox
+func (c *Client) CanAccess(inboxID string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.canAccess(inboxID)
+}
+
+func (c *Client) canAccess(inboxID string) bool {
+	return c.allInboxes || c.allowed[inboxID]
+}
+
+// SubscribeInbox assina os eventos de um inbox; false se o usuario nao tem acesso
+func (c *Client) SubscribeInbox(inboxID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.canAccess(inboxID) {
+		return false
+	}
+	c.inboxes[inboxID] = true
+	return true
+}
+
+// UnsubscribeInbox cancela a assinatura de um inbox
+func (c *Client) UnsubscribeInbox(inboxID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.inboxes, inboxID)
+}
+
+// SubscribeConversation assina os eventos de uma conversa do inbox; false se o usuario nao tem acesso
+func (c *Client) SubscribeConversation(conversationID, inboxID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.canAccess(inboxID) {
+		return false
+	}
+	c.conversations[conversationID] = inboxID
+	return true
+}
+
+// UnsubscribeConversation cancela a assinatura de uma conversa
+func (c *Client) UnsubscribeConversation(conversationID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.conversations, conversationID)
+}
+
+// Subscriptions retorna inboxes e conversas assinados
+func (c *Client) Subscriptions() (inboxIDs, conversationIDs []string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	inboxIDs = make([]string, 0, len(c.inboxes))
+	for id := range c.inboxes {
+		inboxIDs = append(inboxIDs, id)
+	}
+	conversationIDs = make([]string, 0, len(c.conversations))
+	for id := range c.conversations {
+		conversationIDs = append(conversationIDs, id)
+	}
+	return inboxIDs, conversationIDs
+}
+
+// Send enfileira mensagem para o cliente; false se a fila esta cheia ou o cliente foi removido
+func (c *Client) Send(v interface{}) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+	select {
+	case c.send <- v:
+		return true
+	default:
+		return false
+	}
+}
+
+// Messages fila de mensagens do cliente, para transportes sem WritePump
+func (c *Client) Messages() <-chan interface{} {
+	return c.send
+}
+
+// Done fechado quando o hub remove o cliente (desconexao ou lentidao)
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// close sinaliza o WritePump para encerrar a conexao
+func (c *Client) close() {
+	c.closeOnce.Do(func() { close(c.done) })
+}
+
+// WritePump escreve a fila do cliente no socket e envia pings de keepalive.
+// Encerra a conexao quando o hub remove o cliente ou uma escrita falha,
+// o que tambem interrompe a leitura do handler.
+func (c *Client) WritePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case <-c.done:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return
+
+		case msg := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				log.Printf("[WebSocket] Error sending to user %s: %v", c.userID, err)
+				return
+			}
+
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Wants verifica se o evento deve ser entregue ao cliente
+func (c *Client) Wants(event Event) bool {
+	if event.UserID != "" {
+		return event.UserID == c.userID
+	}
+	if event.InboxID == "" {
+		return true
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.canAccess(event.InboxID) {
+		return false
+	}
+	if len(c.inboxes) == 0 && len(c.conversations) == 0 {
+		return true
+	}
+	if c.inboxes[event.InboxID] {
+		return true
+	}
+	_, ok := c.conversations[event.ConversationID]
+	return event.ConversationID != "" && ok
+}
@@ -1,17 +1,21 @@
 package websocket
 
 import (
+	"context"
 	"log"
 	"sync"
-
-	"github.com/gorilla/websocket"
+	"sync/atomic"
+	"time"
 )
 
 // Event evento para broadcast
 type Event struct {
-	Type    string      `json:"type"`
-	InboxID string      `json:"inbox_id,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
+	ID             uint64      `json:"id"` // Sequencia crescente atribuida pelo hub
+	Type           string      `json:"type"`
+	InboxID        string      `json:"inbox_id,omitempty"`
+	ConversationID string      `json:"conversation_id,omitempty"`
+	UserID         string      `json:"user_id,omitempty"` // Destinatario (eventos pessoais)
+	Data           interface{} `json:"data,omitempty"`
 }
 
 // Message mensagem do WebSocket
@@ -20,22 +24,98 @@ type Message struct {
 	Payload interface{} `json:"payload"`
 }
 
-// Hub gerencia conexoes WebSocket
+// HubStats contadores de entrega do hub
+type HubStats struct {
+	Clients          int    `json:"clients"`
+	Delivered        uint64 `json:"delivered"`         // Mensagens enfileiradas para clientes
+	Dropped          uint64 `json:"dropped"`           // Mensagens descartadas por fila do cliente cheia
+	Evicted          uint64 `json:"evicted"`           // Clientes lentos desconectados
+	BroadcastDropped uint64 `json:"broadcast_dropped"` // Eventos descartados com o canal do hub cheio
+	Replayed         uint64 `json:"replayed"`          // Eventos reenviados em reconexoes
+	Resyncs          uint64 `json:"resyncs"`           // Reconexoes sem reenvio possivel (resync_required)
+	LastEventID      uint64 `json:"last_event_id"`
+}
+
+// StreamPosition ultimo ID do stream, enviado em "connected" e em "resync_required".
+// Com resync_required os eventos perdidos nao estao mais disponiveis e o cliente
+// deve recarregar o estado pela API antes de seguir a partir de LastEventID.
+type StreamPosition struct {
+	LastEventID uint64 `json:"last_event_id"`
+}
+
+// Relay replica os eventos locais para as demais instancias do servidor
+type Relay interface {
+	Publish(event Event)
+	// SetInterest informa os eventos das demais instancias que os clientes locais podem receber
+	SetInterest(interest Interest)
+}
+
+// Interest inboxes e usuarios com clientes conectados nesta instancia
+type Interest struct {
+	AllInboxes bool     // Algum cliente ve todos os inboxes (admin)
+	InboxIDs   []string // Inboxes visiveis aos clientes (ignorado com AllInboxes)
+	UserIDs    []string // Usuarios conectados (eventos pessoais)
+}
+
+// AccessLoader carrega os inboxes em que o usuario e membro
+type AccessLoader func(ctx context.Context, userID string) ([]string, error)
+
+// EventAccessChanged membros de algum inbox do usuario mudaram; os clientes
+// dele recarregam os acessos e recebem o evento para atualizar a lista de inboxes
+const EventAccessChanged = "inbox_access_changed"
+
+// accessLoadTimeout tempo maximo para recarregar os acessos de um usuario
+const accessLoadTimeout = 10 * time.Second
+
+// registration pedido de entrada de um cliente, opcionalmente retomando apos lastEventID
+type registration struct {
+	client      *Client
+	resume      bool
+	lastEventID uint64
+}
+
+// Hub gerencia conexoes WebSocket.
+// A entrega nunca bloqueia: cada cliente tem sua fila e quem nao a esvazia
+// a tempo e desconectado; ao reconectar ele retoma pelo ultimo ID recebido.
+//
+// Os IDs sao locais a cada instancia: com varias replicas a retomada so reenvia
+// eventos quando o cliente reconecta na mesma instancia (sticky sessions).
+// Em outra instancia, ou apos reinicio, o ID pertence a outra sequencia e o
+// cliente recebe resync_required.
 type Hub struct {
-	clients    map[*websocket.Conn]string
-	register   chan *websocket.Conn
-	unregister chan *websocket.Conn
+	clients    map[*Client]bool
+	register   chan registration
+	unregister chan *Client
 	broadcast  chan Event
+	access     chan struct{} // Acessos de algum cliente mudaram
+	relay      Relay
+	loader     AccessLoader
 	mu         sync.RWMutex
+
+	// Usados apenas pelo loop do hub. A sequencia carrega nos bits altos uma
+	// marca aleatoria da instancia (ver newSequence), identificando IDs de outra origem.
+	seq    uint64
+	replay *replayBuffer
+
+	delivered        atomic.Uint64
+	dropped          atomic.Uint64
+	evicted          atomic.Uint64
+	broadcastDropped atomic.Uint64
+	replayed         atomic.Uint64
+	resyncs          atomic.Uint64
+	lastEventID      atomic.Uint64
 }
 
 // NewHub cria novo hub
 func NewHub() *Hub {
 	return &Hub{
-		clients:    make(map[*websocket.Conn]string),
-		register:   make(chan *websocket.Conn),
-		unregister: make(chan *websocket.Conn),
-		broadcast:  make(chan Event, 256),
+		clients:    make(map[*Client]bool),
+		register:   make(chan registration),
+		unregister: make(chan *Client),
+		broadcast:  make(chan Event, 1024),
+		access:     make(chan struct{}, 1),
+		seq:        newSequence(),
+		replay:     newReplayBuffer(replayBufferSize),
 	}
 }
 
@@ -43,58 +123,247 @@ func NewHub() *Hub {
 func (h *Hub) Run() {
 	for {
 		select {
-		case conn := <-h.register:
-			h.mu.Lock()
-			h.clients[conn] = "default-user"
-			h.mu.Unlock()
-			log.Printf("[WebSocket] Client connected, total: %d", len(h.clients))
-
-		case conn := <-h.unregister:
-			h.mu.Lock()
-			if _, ok := h.clients[conn]; ok {
-				delete(h.clients, conn)
-				conn.Close()
-			}
-			h.mu.Unlock()
-			log.Printf("[WebSocket] Client disconnected, total: %d", len(h.clients))
+		case reg := <-h.register:
+			h.add(reg)
+
+		case client := <-h.unregister:
+			h.remove(client)
+
+		case <-h.access:
+			h.updateInterest()
 
 		case event := <-h.broadcast:
-			h.mu.RLock()
-			for conn := range h.clients {
-				msg := Message{
-					Type:    event.Type,
-					Payload: event,
-				}
-				if err := conn.WriteJSON(msg); err != nil {
-					log.Printf("[WebSocket] Error sending: %v", err)
-					conn.Close()
-					delete(h.clients, conn)
-				}
+			h.seq++
+			event.ID = h.seq
+			h.lastEventID.Store(h.seq)
+			h.replay.add(event)
+			if event.Type == EventAccessChanged {
+				h.reloadAccess(event.UserID)
 			}
-			h.mu.RUnlock()
+			h.deliver(event)
 		}
 	}
 }
 
+// add registra o cliente e reenvia os eventos perdidos antes de qualquer evento novo
+func (h *Hub) add(reg registration) {
+	client := reg.client
+	if reg.resume {
+		h.resume(client, reg.lastEventID)
+	}
+
+	client.Send(Message{Type: "connected", Payload: StreamPosition{LastEventID: h.seq}})
+
+	h.mu.Lock()
+	h.clients[client] = true
+	total := len(h.clients)
+	h.mu.Unlock()
+	h.updateInterest()
+	log.Printf("[WebSocket] Client connected (user %s), total: %d", client.userID, total)
+}
+
+// resume enfileira os eventos posteriores a lastEventID visiveis ao cliente,
+// ou resync_required se eles ja sairam do buffer ou nao cabem na fila do cliente
+func (h *Hub) resume(client *Client, lastEventID uint64) {
+	if lastEventID == h.seq {
+		return
+	}
+
+	var missed []Event
+	ok := lastEventID < h.seq && sameSequence(lastEventID, h.seq)
+	if ok {
+		var events []Event
+		events, ok = h.replay.since(lastEventID)
+		for _, event := range events {
+			if client.Wants(event) {
+				missed = append(missed, event)
+			}
+		}
+		ok = ok && len(missed) < sendBufferSize
+	}
+
+	if !ok {
+		h.resyncs.Add(1)
+		client.Send(Message{Type: "resync_required", Payload: StreamPosition{LastEventID: h.seq}})
+		return
+	}
+	for _, event := range missed {
+		client.Send(Message{Type: event.Type, Payload: event})
+	}
+	h.replayed.Add(uint64(len(missed)))
+}
+
+// deliver enfileira o evento para os clientes autorizados e assinantes
+func (h *Hub) deliver(event Event) {
+	msg := Message{
+		Type:    event.Type,
+		Payload: event,
+	}
+
+	h.mu.RLock()
+	var slow []*Client
+	for client := range h.clients {
+		if !client.Wants(event) {
+			continue
+		}
+		if client.Send(msg) {
+			h.delivered.Add(1)
+		} else {
+			h.dropped.Add(1)
+			slow = append(slow, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, client := range slow {
+		log.Printf("[WebSocket] Evicting slow client (user %s)", client.userID)
+		if h.remove(client) {
+			h.evicted.Add(1)
+		}
+	}
+}
+
+// remove tira o cliente do hub e encerra sua conexao
+func (h *Hub) remove(client *Client) bool {
+	h.mu.Lock()
+	_, ok := h.clients[client]
+	delete(h.clients, client)
+	total := len(h.clients)
+	h.mu.Unlock()
+
+	client.close()
+	if ok {
+		h.updateInterest()
+		log.Printf("[WebSocket] Client disconnected, total: %d", total)
+	}
+	return ok
+}
+
+// reloadAccess recarrega em segundo plano os inboxes dos clientes do usuario (admins veem todos)
+func (h *Hub) reloadAccess(userID string) {
+	if h.loader == nil || userID == "" {
+		return
+	}
+
+	var clients []*Client
+	h.mu.RLock()
+	for client := range h.clients {
+		if all, _ := client.access(); client.userID == userID && !all {
+			clients = append(clients, client)
+		}
+	}
+	h.mu.RUnlock()
+	if len(clients) == 0 {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), accessLoadTimeout)
+		defer cancel()
+		inboxIDs, err := h.loader(ctx, userID)
+		if err != nil {
+			log.Printf("[WebSocket] Failed to reload inboxes of user %s: %v", userID, err)
+			return
+		}
+		for _, client := range clients {
+			h.SetAccess(client, false, inboxIDs)
+		}
+	}()
+}
+
+// updateInterest repassa ao relay os inboxes e usuarios dos clientes conectados
+func (h *Hub) updateInterest() {
+	if h.relay == nil {
+		return
+	}
+
+	var interest Interest
+	inboxes := make(map[string]bool)
+	users := make(map[string]bool)
+	h.mu.RLock()
+	for client := range h.clients {
+		users[client.userID] = true
+		all, inboxIDs := client.access()
+		interest.AllInboxes = interest.AllInboxes || all
+		for _, id := range inboxIDs {
+			inboxes[id] = true
+		}
+	}
+	h.mu.RUnlock()
+
+	for id := range users {
+		interest.UserIDs = append(interest.UserIDs, id)
+	}
+	if !interest.AllInboxes {
+		for id := range inboxes {
+			interest.InboxIDs = append(interest.InboxIDs, id)
+		}
+	}
+	h.relay.SetInterest(interest)
+}
+
 // Register registra nova conexao
-func (h *Hub) Register(conn *websocket.Conn) {
-	h.register <- conn
+func (h *Hub) Register(client *Client) {
+	h.register <- registration{client: client}
+}
+
+// Resume registra uma conexao retomada, reenviando os eventos com ID maior que lastEventID
+func (h *Hub) Resume(client *Client, lastEventID uint64) {
+	h.register <- registration{client: client, resume: true, lastEventID: lastEventID}
 }
 
 // Unregister remove conexao
-func (h *Hub) Unregister(conn *websocket.Conn) {
-	h.unregister <- conn
+func (h *Hub) Unregister(client *Client) {
+	h.unregister <- client
+}
+
+// SetAccess define os inboxes visiveis ao cliente (ver Client.SetAccess) e
+// atualiza os eventos que esta instancia recebe das demais
+func (h *Hub) SetAccess(client *Client, all bool, inboxIDs []string) {
+	client.SetAccess(all, inboxIDs)
+	select {
+	case h.access <- struct{}{}:
+	default: // Atualizacao ja pendente
+	}
+}
+
+// SetRelay define o relay entre instancias (chamar antes de Run)
+func (h *Hub) SetRelay(relay Relay) {
+	h.relay = relay
 }
 
-// Broadcast envia evento para todos os clientes
+// SetAccessLoader define como recarregar os inboxes de um usuario em EventAccessChanged (chamar antes de Run)
+func (h *Hub) SetAccessLoader(loader AccessLoader) {
+	h.loader = loader
+}
+
+// Broadcast envia evento aos clientes que podem recebe-lo, nesta e nas demais instancias
 func (h *Hub) Broadcast(event Event) {
+	h.BroadcastLocal(event)
+	if h.relay != nil {
+		h.relay.Publish(event)
+	}
+}
+
+// BroadcastLocal envia evento apenas aos clientes desta instancia (ex.: recebido pelo relay).
+// O ID e atribuido aqui, na sequencia local.
+func (h *Hub) BroadcastLocal(event Event) {
 	select {
 	case h.broadcast <- event:
 	default:
-		log.Printf("[WebSocket] Broadcast channel full")
+		h.broadcastDropped.Add(1)
+		log.Printf("[WebSocket] Broadcast channel full, dropping %s event", event.Type)
 	}
 }
 
+// BroadcastAccessChanged avisa os clientes do usuario que seus inboxes mudaram
+func (h *Hub) BroadcastAccessChanged(userID string) {
+	h.Broadcast(Event{
+		Type:   EventAccessChanged,
+		UserID: userID,
+	})
+}
+
 // BroadcastQRCode envia QR code
 func (h *Hub) BroadcastQRCode(inboxID, qrCode string) {
 	h.Broadcast(Event{
@@ -114,23 +383,107 @@ func (h *Hub) BroadcastConnectionStatus(inboxID, status, phone string) {
 }
 
 // BroadcastMessage envia nova mensagem
-func (h *Hub) BroadcastMessage(inboxID string, message interface{}) {
+func (h *Hub) BroadcastMessage(inboxID, conversationID string, message interface{}) {
 	h.Broadcast(Event{
-		Type:    "message",
-		InboxID: inboxID,
-		Data:    message,
+		Type:           "message",
+		InboxID:        inboxID,
+		ConversationID: conversationID,
+		Data:           message,
 	})
 }
 
 // BroadcastConversationUpdate envia atualizacao de conversa
-func (h *Hub) BroadcastConversationUpdate(inboxID string, conversation interface{}) {
+func (h *Hub) BroadcastConversationUpdate(inboxID, conversationID string, conversation interface{}) {
+	h.Broadcast(Event{
+		Type:           "conversation_update",
+		InboxID:        inboxID,
+		ConversationID: conversationID,
+		Data:           conversation,
+	})
+}
+
+// BroadcastMessageUpdate envia mensagem editada
+func (h *Hub) BroadcastMessageUpdate(inboxID, conversationID string, message interface{}) {
+	h.Broadcast(Event{
+		Type:           "message_updated",
+		InboxID:        inboxID,
+		ConversationID: conversationID,
+		Data:           message,
+	})
+}
+
+// BroadcastMessageDeleted envia mensagem apagada
+func (h *Hub) BroadcastMessageDeleted(inboxID, conversationID string, message interface{}) {
+	h.Broadcast(Event{
+		Type:           "message_deleted",
+		InboxID:        inboxID,
+		ConversationID: conversationID,
+		Data:           message,
+	})
+}
+
+// BroadcastReaction envia reacao adicionada ou removida
+func (h *Hub) BroadcastReaction(inboxID, conversationID string, reaction interface{}) {
+	h.Broadcast(Event{
+		Type:           "reaction",
+		InboxID:        inboxID,
+		ConversationID: conversationID,
+		Data:           reaction,
+	})
+}
+
+// BroadcastNotification envia notificacao destinada a um usuario
+func (h *Hub) BroadcastNotification(userID string, notification interface{}) {
 	h.Broadcast(Event{
-		Type:    "conversation_update",
+		Type:   "notification",
+		UserID: userID,
+		Data:   notification,
+	})
+}
+
+// BroadcastHistorySync envia andamento da importacao de historico
+func (h *Hub) BroadcastHistorySync(inboxID string, progress interface{}) {
+	h.Broadcast(Event{
+		Type:    "history_sync",
 		InboxID: inboxID,
-		Data:    conversation,
+		Data:    progress,
+	})
+}
+
+// BroadcastTyping envia contato digitando em uma conversa
+func (h *Hub) BroadcastTyping(inboxID, conversationID string, typing interface{}) {
+	h.Broadcast(Event{
+		Type:           "typing",
+		InboxID:        inboxID,
+		ConversationID: conversationID,
+		Data:           typing,
 	})
 }
 
+// BroadcastPresence envia contato online/offline
+func (h *Hub) BroadcastPresence(inboxID, conversationID string, presence interface{}) {
+	h.Broadcast(Event{
+		Type:           "presence",
+		InboxID:        inboxID,
+		ConversationID: conversationID,
+		Data:           presence,
+	})
+}
+
+// Stats retorna os contadores de entrega
+func (h *Hub) Stats() HubStats {
+	return HubStats{
+		Clients:          h.ClientCount(),
+		Delivered:        h.delivered.Load(),
+		Dropped:          h.dropped.Load(),
+		Evicted:          h.evicted.Load(),
+		BroadcastDropped: h.broadcastDropped.Load(),
+		Replayed:         h.replayed.Load(),
+		Resyncs:          h.resyncs.Load(),
+		LastEventID:      h.lastEventID.Load(),
+	}
+}
+
 // ClientCount retorna numero de clientes conectados
 func (h *Hub) ClientCount() int {
 	h.mu.RLock()
@@ -0,0 +1,67 @@
+package websocket
+
+import "math/rand/v2"
+
+// replayBufferSize eventos recentes mantidos para reenvio apos reconexao
+const replayBufferSize = 4096
+
+// IDs de evento: marca da instancia nos bits 40-51 e contador nos 40 bits baixos.
+// Ficam abaixo de 2^53, exatos como number em JavaScript.
+const (
+	sequenceCounterBits = 40
+	sequenceTags        = 1 << 12
+)
+
+// newSequence inicio da sequencia desta instancia, com marca aleatoria.
+// IDs de outra replica ou de um processo anterior tem outra marca
+// (salvo coincidencia, 1 em 4096) e nao sao retomados.
+func newSequence() uint64 {
+	return rand.Uint64N(sequenceTags) << sequenceCounterBits
+}
+
+// sameSequence verifica se os dois IDs tem a mesma marca de instancia
+func sameSequence(a, b uint64) bool {
+	return a>>sequenceCounterBits == b>>sequenceCounterBits
+}
+
+// replayBuffer anel com os ultimos eventos em ordem de ID.
+// Acessado apenas pelo loop do hub.
+type replayBuffer struct {
+	events []Event
+	start  int // Posicao do evento mais antigo
+	count  int
+}
+
+func newReplayBuffer(size int) *replayBuffer {
+	return &replayBuffer{events: make([]Event, size)}
+}
+
+// add guarda o evento, descartando o mais antigo com o anel cheio
+func (b *replayBuffer) add(event Event) {
+	size := len(b.events)
+	if b.count < size {
+		b.events[(b.start+b.count)%size] = event
+		b.count++
+		return
+	}
+	b.events[b.start] = event
+	b.start = (b.start + 1) % size
+}
+
+// since retorna os eventos com ID maior que lastID.
+// false se algum evento posterior a lastID ja saiu do anel.
+func (b *replayBuffer) since(lastID uint64) ([]Event, bool) {
+	if b.count == 0 || b.events[b.start].ID > lastID+1 {
+		return nil, false
+	}
+
+	size := len(b.events)
+	var missed []Event
+	for i := 0; i < b.count; i++ {
+		event := b.events[(b.start+i)%size]
+		if event.ID > lastID {
+			missed = append(missed, event)
+		}
+	}
+	return missed, true
+}
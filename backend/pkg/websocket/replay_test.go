@@ -0,0 +1,73 @@
+package websocket
+
+import "testing"
+
+func TestReplayBufferSince(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		added  []uint64
+		lastID uint64
+		want   []uint64
+		wantOK bool
+	}{
+		{name: "empty buffer", size: 4, lastID: 0, wantOK: false},
+		{name: "all events", size: 4, added: []uint64{1, 2, 3}, lastID: 0, want: []uint64{1, 2, 3}, wantOK: true},
+		{name: "up to date", size: 4, added: []uint64{1, 2, 3}, lastID: 3, want: nil, wantOK: true},
+		{name: "wraparound keeps order", size: 4, added: []uint64{1, 2, 3, 4, 5, 6}, lastID: 2, want: []uint64{3, 4, 5, 6}, wantOK: true},
+		{name: "wraparound partial", size: 4, added: []uint64{1, 2, 3, 4, 5, 6}, lastID: 5, want: []uint64{6}, wantOK: true},
+		{name: "gap after eviction", size: 4, added: []uint64{1, 2, 3, 4, 5, 6}, lastID: 1, wantOK: false},
+		{name: "seeded sequence", size: 4, added: []uint64{1<<40 + 1, 1<<40 + 2}, lastID: 1 << 40, want: []uint64{1<<40 + 1, 1<<40 + 2}, wantOK: true},
+		{name: "seeded sequence gap", size: 4, added: []uint64{1<<40 + 5}, lastID: 1 << 40, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := newReplayBuffer(tt.size)
+			for _, id := range tt.added {
+				buf.add(Event{ID: id})
+			}
+
+			got, ok := buf.since(tt.lastID)
+			if ok != tt.wantOK {
+				t.Fatalf("since(%d) ok = %v, want %v", tt.lastID, ok, tt.wantOK)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("since(%d) returned %d events, want %d", tt.lastID, len(got), len(tt.want))
+			}
+			for i, event := range got {
+				if event.ID != tt.want[i] {
+					t.Fatalf("since(%d)[%d] = %d, want %d", tt.lastID, i, event.ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSequence(t *testing.T) {
+	seq := newSequence()
+	if seq&(1<<sequenceCounterBits-1) != 0 {
+		t.Fatalf("newSequence() = %d, counter bits must start at zero", seq)
+	}
+	if seq >= 1<<53 {
+		t.Fatalf("newSequence() = %d, must stay below 2^53", seq)
+	}
+
+	tests := []struct {
+		name string
+		a, b uint64
+		want bool
+	}{
+		{name: "same instance", a: seq + 1, b: seq + 1000, want: true},
+		{name: "other instance", a: 1<<40 + 1, b: 2<<40 + 1, want: false},
+		{name: "unseeded id", a: 5, b: 3<<40 + 5, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameSequence(tt.a, tt.b); got != tt.want {
+				t.Fatalf("sameSequence(%d, %d) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
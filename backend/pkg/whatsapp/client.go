@@ -3,6 +3,7 @@ package whatsapp
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -18,6 +19,24 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// mediaDownloadTimeout tempo maximo para baixar midia recebida
+const mediaDownloadTimeout = 2 * time.Minute
+
+// DefaultMediaMaxSize tamanho maximo padrao de midia recebida baixada
+const DefaultMediaMaxSize = 16 << 20
+
+// messageQueueSize mensagens recebidas aguardando o worker; cheia, o evento do whatsmeow espera
+const messageQueueSize = 64
+
+// pairClientName nome exibido no aparelho ao parear por codigo ("Navegador (SO)")
+const pairClientName = "Chrome (Linux)"
+
+// ErrAlreadyPaired dispositivo ja possui sessao pareada
+var ErrAlreadyPaired = errors.New("device already paired")
+
+// ErrInvalidPhone telefone invalido para pareamento por codigo
+var ErrInvalidPhone = errors.New("invalid phone number")
+
 // Client wrapper do whatsmeow.Client
 type Client struct {
 	wa       *whatsmeow.Client
@@ -25,7 +44,19 @@ type Client struct {
 	handler  EventHandler
 	status   Status
 	qrCode   string
-	killChan chan bool
+	pairCode string        // Codigo de pareamento por telefone em uso
+	qrReady  chan struct{} // Fechado ao receber o primeiro QR (websocket de login pronto)
+	history  HistoryConfig
+	presence map[types.JID]bool // Contatos com presenca assinada na conexao atual (nil sem announce)
+	announce bool               // Marcar o dispositivo como online ao conectar
+	policy   ReconnectPolicy
+	mediaMax int64                // Midia maior que isso nao e baixada
+	messages chan *events.Message // Fila de mensagens recebidas (download de midia fora do evento)
+	working  bool                 // Worker da fila em execucao
+	health   Health
+	stop     chan struct{} // Fechado por Disconnect: encerra a supervisao
+	dropped  chan struct{} // Sinaliza queda da conexao ao supervisor
+	terminal string        // Motivo que impede reconectar (logout, sessao substituida)
 	mu       sync.RWMutex
 }
 
@@ -36,12 +67,17 @@ func NewClient(device *store.Device) *Client {
 	store.DeviceProps.Os = proto.String("Zyntra")
 
 	wa := whatsmeow.NewClient(device, nil)
+	// Reconexao feita pelo supervisor (backoff exponencial com jitter)
+	wa.EnableAutoReconnect = false
 
 	client := &Client{
 		wa:       wa,
 		device:   device,
 		status:   StatusDisconnected,
-		killChan: make(chan bool, 1),
+		policy:   DefaultReconnectPolicy,
+		mediaMax: DefaultMediaMaxSize,
+		messages: make(chan *events.Message, messageQueueSize),
+		dropped:  make(chan struct{}, 1),
 	}
 
 	wa.AddEventHandler(client.handleEvent)
@@ -54,47 +90,80 @@ func (c *Client) SetEventHandler(handler EventHandler) {
 	c.handler = handler
 }
 
+// SetMediaMaxSize define o tamanho maximo de midia recebida que e baixada
+func (c *Client) SetMediaMaxSize(size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if size > 0 {
+		c.mediaMax = size
+	}
+}
+
 // Connect inicia a conexao com WhatsApp
 // Retorna canal de eventos QR se precisar de autenticacao
 func (c *Client) Connect(ctx context.Context) (<-chan QREvent, error) {
 	c.mu.Lock()
+	if c.stop != nil {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("client already started")
+	}
 	c.status = StatusConnecting
+	c.terminal = ""
+	c.health = Health{}
+	c.stop = make(chan struct{})
+	stop := c.stop
 	c.mu.Unlock()
 
 	// Se ja tem sessao, conectar direto
 	if c.wa.Store.ID != nil {
-		go c.connectExisting()
+		go c.supervise(stop)
 		return nil, nil
 	}
 
-	// Novo login - precisa de QR code
+	// Novo login - precisa de QR code (ou codigo de pareamento)
+	c.mu.Lock()
+	c.qrReady = make(chan struct{})
+	c.pairCode = ""
+	c.mu.Unlock()
+
 	qrChan := make(chan QREvent, 10)
-	go c.handleQRFlow(qrChan)
+	go c.handleQRFlow(qrChan, stop)
 	return qrChan, nil
 }
 
-func (c *Client) handleQRFlow(qrChan chan<- QREvent) {
-	defer close(qrChan)
-
+func (c *Client) handleQRFlow(qrChan chan<- QREvent, stop chan struct{}) {
 	waQRChan, err := c.wa.GetQRChannel(context.Background())
 	if err != nil {
+		close(qrChan)
 		if err == whatsmeow.ErrQRStoreContainsID {
 			log.Printf("[WhatsApp] Already logged in, connecting...")
-			c.connectExisting()
+			c.supervise(stop)
 			return
 		}
 		log.Printf("[WhatsApp] Failed to get QR channel: %v", err)
+		c.setStatus(StatusDisconnected)
+		c.releaseStop(stop)
 		return
 	}
 
 	if err := c.wa.Connect(); err != nil {
+		close(qrChan)
 		log.Printf("[WhatsApp] Failed to connect: %v", err)
+		c.setStatus(StatusDisconnected)
+		c.releaseStop(stop)
 		return
 	}
 
 	for evt := range waQRChan {
 		switch evt.Event {
 		case "code":
+			c.markQRReady()
+
+			// Pareamento por codigo em andamento: novos QR nao sao exibidos
+			if c.GetPairCode() != "" {
+				continue
+			}
+
 			image, err := qrcode.Encode(evt.Code, qrcode.Medium, 256)
 			if err != nil {
 				log.Printf("[WhatsApp] Failed to generate QR image: %v", err)
@@ -122,14 +191,17 @@ func (c *Client) handleQRFlow(qrChan chan<- QREvent) {
 			c.mu.Lock()
 			c.status = StatusDisconnected
 			c.qrCode = ""
+			c.pairCode = ""
 			c.mu.Unlock()
 
 			qrChan <- QREvent{Event: "timeout"}
+			close(qrChan)
 
 			if c.handler != nil {
 				c.handler.OnDisconnected()
 			}
 			c.wa.Disconnect()
+			c.releaseStop(stop)
 			return
 
 		case "success":
@@ -137,57 +209,117 @@ func (c *Client) handleQRFlow(qrChan chan<- QREvent) {
 			c.mu.Lock()
 			c.status = StatusConnected
 			c.qrCode = ""
+			c.pairCode = ""
 			c.mu.Unlock()
 
 			qrChan <- QREvent{Event: "success"}
 		}
 	}
+	close(qrChan)
 
-	c.keepAliveLoop()
+	// Pareado: a partir daqui a sessao e mantida pelo supervisor
+	if c.wa.Store.ID != nil {
+		c.supervise(stop)
+		return
+	}
+	c.releaseStop(stop)
 }
 
-func (c *Client) connectExisting() {
-	log.Printf("[WhatsApp] Connecting existing session...")
+// PairPhone gera codigo de 8 caracteres para parear pelo numero de telefone,
+// alternativa ao QR code. Exige Connect com novo login em andamento.
+func (c *Client) PairPhone(ctx context.Context, phone string) (string, error) {
+	if c.wa.Store.ID != nil {
+		return "", ErrAlreadyPaired
+	}
 
-	if err := c.wa.Connect(); err != nil {
-		log.Printf("[WhatsApp] Failed to connect: %v", err)
-		c.mu.Lock()
-		c.status = StatusDisconnected
-		c.mu.Unlock()
-		return
+	digits := onlyDigits(phone)
+	if !IsValidPhone(digits) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidPhone, phone)
 	}
 
-	c.keepAliveLoop()
-}
+	c.mu.RLock()
+	ready := c.qrReady
+	c.mu.RUnlock()
+	if ready == nil {
+		return "", fmt.Errorf("pairing not started")
+	}
 
-func (c *Client) keepAliveLoop() {
-	for {
-		select {
-		case <-c.killChan:
-			log.Printf("[WhatsApp] Kill signal received")
-			c.wa.Disconnect()
-			return
-		default:
-			time.Sleep(1 * time.Second)
-		}
+	// O codigo so pode ser pedido com o websocket de login conectado
+	select {
+	case <-ready:
+	case <-ctx.Done():
+		return "", fmt.Errorf("login connection not ready: %w", ctx.Err())
 	}
+
+	code, err := c.wa.PairPhone(ctx, digits, true, whatsmeow.PairClientChrome, pairClientName)
+	if err != nil {
+		return "", fmt.Errorf("failed to request pairing code: %w", err)
+	}
+
+	c.mu.Lock()
+	c.pairCode = code
+	c.qrCode = ""
+	c.status = StatusQRCode
+	c.mu.Unlock()
+
+	log.Printf("[WhatsApp] Pairing code generated for +%s", digits)
+	return code, nil
 }
 
-// Disconnect desconecta do WhatsApp
-func (c *Client) Disconnect() error {
+// GetPairCode retorna codigo de pareamento atual
+func (c *Client) GetPairCode() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.pairCode
+}
+
+// markQRReady sinaliza que o websocket de login esta pronto
+func (c *Client) markQRReady() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.qrReady == nil {
+		return
+	}
 	select {
-	case c.killChan <- true:
+	case <-c.qrReady:
 	default:
+		close(c.qrReady)
 	}
+}
 
+// Disconnect encerra a supervisao e desconecta do WhatsApp
+func (c *Client) Disconnect() error {
 	c.mu.Lock()
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
 	c.status = StatusDisconnected
 	c.qrCode = ""
+	c.pairCode = ""
+	c.health.NextRetryAt = time.Time{}
+	c.presence = nil
 	c.mu.Unlock()
 
+	c.wa.Disconnect()
 	return nil
 }
 
+// releaseStop libera o cliente para um novo Connect apos o login expirar
+func (c *Client) releaseStop(stop chan struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stop == stop {
+		c.stop = nil
+	}
+}
+
+func (c *Client) setStatus(status Status) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status = status
+}
+
 // Logout faz logout e remove sessao
 func (c *Client) Logout(ctx context.Context) error {
 	return c.wa.Logout(ctx)
@@ -232,42 +364,63 @@ func (c *Client) GetPhone() string {
 	return ""
 }
 
-// SendText envia mensagem de texto
-func (c *Client) SendText(ctx context.Context, to, content string) (string, error) {
-	if !c.IsConnected() {
-		return "", fmt.Errorf("client not connected")
-	}
+// Quote mensagem citada em uma resposta
+type Quote struct {
+	ID        string // ID da mensagem citada
+	SenderJID string // Remetente da mensagem citada (vazio = enviada por nos)
+	Content   string // Texto citado
+}
 
-	jid := PhoneToJID(to)
+// SendText envia mensagem de texto
+func (c *Client) SendText(ctx context.Context, to, content string, quote *Quote) (string, error) {
 	msg := &waProto.Message{
 		Conversation: proto.String(content),
 	}
-
-	resp, err := c.wa.SendMessage(ctx, jid, msg)
-	if err != nil {
-		return "", fmt.Errorf("failed to send message: %w", err)
+	if quote != nil {
+		// Conversation nao aceita ContextInfo
+		msg = &waProto.Message{
+			ExtendedTextMessage: &waProto.ExtendedTextMessage{Text: proto.String(content)},
+		}
 	}
 
-	return resp.ID, nil
+	return c.send(ctx, to, msg, quote, "message")
 }
 
 // SendImage envia imagem
-func (c *Client) SendImage(ctx context.Context, to string, data []byte, caption, mimeType string) (string, error) {
-	if !c.IsConnected() {
-		return "", fmt.Errorf("client not connected")
+func (c *Client) SendImage(ctx context.Context, to string, data []byte, caption, mimeType string, quote *Quote) (string, error) {
+	uploaded, err := c.upload(ctx, data, whatsmeow.MediaImage, "image")
+	if err != nil {
+		return "", err
 	}
 
-	jid := PhoneToJID(to)
+	msg := &waProto.Message{
+		ImageMessage: &waProto.ImageMessage{
+			Caption:       proto.String(caption),
+			Mimetype:      proto.String(mimeType),
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uint64(len(data))),
+		},
+	}
 
-	uploaded, err := c.wa.Upload(ctx, data, whatsmeow.MediaImage)
+	return c.send(ctx, to, msg, quote, "image")
+}
+
+// SendDocument envia documento
+func (c *Client) SendDocument(ctx context.Context, to string, data []byte, filename, caption, mimeType string, quote *Quote) (string, error) {
+	uploaded, err := c.upload(ctx, data, whatsmeow.MediaDocument, "document")
 	if err != nil {
-		return "", fmt.Errorf("failed to upload image: %w", err)
+		return "", err
 	}
 
 	msg := &waProto.Message{
-		ImageMessage: &waProto.ImageMessage{
+		DocumentMessage: &waProto.DocumentMessage{
 			Caption:       proto.String(caption),
 			Mimetype:      proto.String(mimeType),
+			FileName:      proto.String(filename),
 			URL:           proto.String(uploaded.URL),
 			DirectPath:    proto.String(uploaded.DirectPath),
 			MediaKey:      uploaded.MediaKey,
@@ -277,32 +430,73 @@ func (c *Client) SendImage(ctx context.Context, to string, data []byte, caption,
 		},
 	}
 
-	resp, err := c.wa.SendMessage(ctx, jid, msg)
+	return c.send(ctx, to, msg, quote, "document")
+}
+
+// SendAudio envia audio; com ptt=true e enviado como mensagem de voz (ogg/opus)
+func (c *Client) SendAudio(ctx context.Context, to string, data []byte, mimeType string, ptt bool, quote *Quote) (string, error) {
+	if ptt && (mimeType == "" || mimeType == "audio/ogg") {
+		mimeType = "audio/ogg; codecs=opus"
+	}
+
+	uploaded, err := c.upload(ctx, data, whatsmeow.MediaAudio, "audio")
 	if err != nil {
-		return "", fmt.Errorf("failed to send image: %w", err)
+		return "", err
 	}
 
-	return resp.ID, nil
+	msg := &waProto.Message{
+		AudioMessage: &waProto.AudioMessage{
+			Mimetype:      proto.String(mimeType),
+			PTT:           proto.Bool(ptt),
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uint64(len(data))),
+		},
+	}
+
+	return c.send(ctx, to, msg, quote, "audio")
 }
 
-// SendDocument envia documento
-func (c *Client) SendDocument(ctx context.Context, to string, data []byte, filename, caption, mimeType string) (string, error) {
-	if !c.IsConnected() {
-		return "", fmt.Errorf("client not connected")
+// SendVideo envia video
+func (c *Client) SendVideo(ctx context.Context, to string, data []byte, caption, mimeType string, quote *Quote) (string, error) {
+	uploaded, err := c.upload(ctx, data, whatsmeow.MediaVideo, "video")
+	if err != nil {
+		return "", err
 	}
 
-	jid := PhoneToJID(to)
+	msg := &waProto.Message{
+		VideoMessage: &waProto.VideoMessage{
+			Caption:       proto.String(caption),
+			Mimetype:      proto.String(mimeType),
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uint64(len(data))),
+		},
+	}
+
+	return c.send(ctx, to, msg, quote, "video")
+}
 
-	uploaded, err := c.wa.Upload(ctx, data, whatsmeow.MediaDocument)
+// SendSticker envia figurinha (webp)
+func (c *Client) SendSticker(ctx context.Context, to string, data []byte, mimeType string, quote *Quote) (string, error) {
+	if mimeType == "" {
+		mimeType = "image/webp"
+	}
+
+	uploaded, err := c.upload(ctx, data, whatsmeow.MediaImage, "sticker")
 	if err != nil {
-		return "", fmt.Errorf("failed to upload document: %w", err)
+		return "", err
 	}
 
 	msg := &waProto.Message{
-		DocumentMessage: &waProto.DocumentMessage{
-			Caption:       proto.String(caption),
+		StickerMessage: &waProto.StickerMessage{
 			Mimetype:      proto.String(mimeType),
-			FileName:      proto.String(filename),
 			URL:           proto.String(uploaded.URL),
 			DirectPath:    proto.String(uploaded.DirectPath),
 			MediaKey:      uploaded.MediaKey,
@@ -312,14 +506,252 @@ func (c *Client) SendDocument(ctx context.Context, to string, data []byte, filen
 		},
 	}
 
-	resp, err := c.wa.SendMessage(ctx, jid, msg)
+	return c.send(ctx, to, msg, quote, "sticker")
+}
+
+// SendLocation envia localizacao
+func (c *Client) SendLocation(ctx context.Context, to string, latitude, longitude float64, name, address string, quote *Quote) (string, error) {
+	location := &waProto.LocationMessage{
+		DegreesLatitude:  proto.Float64(latitude),
+		DegreesLongitude: proto.Float64(longitude),
+	}
+	if name != "" {
+		location.Name = proto.String(name)
+	}
+	if address != "" {
+		location.Address = proto.String(address)
+	}
+
+	return c.send(ctx, to, &waProto.Message{LocationMessage: location}, quote, "location")
+}
+
+// SendContact envia cartao de contato (vCard)
+func (c *Client) SendContact(ctx context.Context, to, displayName, vcard string, quote *Quote) (string, error) {
+	msg := &waProto.Message{
+		ContactMessage: &waProto.ContactMessage{
+			DisplayName: proto.String(displayName),
+			Vcard:       proto.String(vcard),
+		},
+	}
+
+	return c.send(ctx, to, msg, quote, "contact")
+}
+
+// SendReaction reage a uma mensagem; emoji vazio remove a reacao.
+// senderJID e o remetente da mensagem alvo (vazio = enviada por nos).
+func (c *Client) SendReaction(ctx context.Context, to, messageID, senderJID, emoji string) error {
+	if !c.IsConnected() {
+		return fmt.Errorf("client not connected")
+	}
+
+	chat := PhoneToJID(to)
+	sender := types.EmptyJID
+	if senderJID != "" {
+		sender = PhoneToJID(senderJID)
+	}
+
+	if _, err := c.wa.SendMessage(ctx, chat, c.wa.BuildReaction(chat, sender, messageID, emoji)); err != nil {
+		return fmt.Errorf("failed to send reaction: %w", err)
+	}
+	return nil
+}
+
+// EditMessage altera o texto de uma mensagem enviada por nos
+func (c *Client) EditMessage(ctx context.Context, to, messageID, content string) error {
+	if !c.IsConnected() {
+		return fmt.Errorf("client not connected")
+	}
+
+	chat := PhoneToJID(to)
+	edit := c.wa.BuildEdit(chat, messageID, &waProto.Message{Conversation: proto.String(content)})
+	if _, err := c.wa.SendMessage(ctx, chat, edit); err != nil {
+		return fmt.Errorf("failed to edit message: %w", err)
+	}
+	return nil
+}
+
+// RevokeMessage apaga para todos uma mensagem enviada por nos
+func (c *Client) RevokeMessage(ctx context.Context, to, messageID string) error {
+	if !c.IsConnected() {
+		return fmt.Errorf("client not connected")
+	}
+
+	chat := PhoneToJID(to)
+	if _, err := c.wa.SendMessage(ctx, chat, c.wa.BuildRevoke(chat, types.EmptyJID, messageID)); err != nil {
+		return fmt.Errorf("failed to revoke message: %w", err)
+	}
+	return nil
+}
+
+// MarkRead envia confirmacao de leitura (tiques azuis) das mensagens recebidas.
+// senderJID e o remetente das mensagens (participante em grupos; vazio = o proprio chat).
+func (c *Client) MarkRead(ctx context.Context, to string, messageIDs []string, senderJID string) error {
+	if !c.IsConnected() {
+		return fmt.Errorf("client not connected")
+	}
+	if len(messageIDs) == 0 {
+		return nil
+	}
+
+	chat := PhoneToJID(to)
+	sender := chat
+	if senderJID != "" {
+		sender = PhoneToJID(senderJID)
+	}
+
+	ids := make([]types.MessageID, len(messageIDs))
+	for i, id := range messageIDs {
+		ids[i] = types.MessageID(id)
+	}
+	if err := c.wa.MarkRead(ctx, ids, time.Now(), chat, sender); err != nil {
+		return fmt.Errorf("failed to mark messages as read: %w", err)
+	}
+	return nil
+}
+
+// SendChatPresence exibe "digitando..." ou "gravando audio..." no chat.
+// state: "composing", "recording" ou "paused".
+func (c *Client) SendChatPresence(ctx context.Context, to, state string) error {
+	if !c.IsConnected() {
+		return fmt.Errorf("client not connected")
+	}
+
+	presence := types.ChatPresenceComposing
+	media := types.ChatPresenceMediaText
+	switch state {
+	case "composing":
+	case "recording":
+		media = types.ChatPresenceMediaAudio
+	case "paused":
+		presence = types.ChatPresencePaused
+	default:
+		return fmt.Errorf("invalid chat presence: %s", state)
+	}
+
+	if err := c.wa.SendChatPresence(ctx, PhoneToJID(to), presence, media); err != nil {
+		return fmt.Errorf("failed to send chat presence: %w", err)
+	}
+	return nil
+}
+
+// upload envia a midia para os servidores do WhatsApp
+func (c *Client) upload(ctx context.Context, data []byte, mediaType whatsmeow.MediaType, kind string) (whatsmeow.UploadResponse, error) {
+	if !c.IsConnected() {
+		return whatsmeow.UploadResponse{}, fmt.Errorf("client not connected")
+	}
+
+	uploaded, err := c.wa.Upload(ctx, data, mediaType)
 	if err != nil {
-		return "", fmt.Errorf("failed to send document: %w", err)
+		return whatsmeow.UploadResponse{}, fmt.Errorf("failed to upload %s: %w", kind, err)
+	}
+	return uploaded, nil
+}
+
+// send aplica a citacao (se houver) e envia a mensagem
+func (c *Client) send(ctx context.Context, to string, msg *waProto.Message, quote *Quote, kind string) (string, error) {
+	if !c.IsConnected() {
+		return "", fmt.Errorf("client not connected")
+	}
+
+	if quote != nil {
+		setContextInfo(msg, c.quoteContext(quote))
+	}
+
+	resp, err := c.wa.SendMessage(ctx, PhoneToJID(to), msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to send %s: %w", kind, err)
 	}
 
 	return resp.ID, nil
 }
 
+// quoteContext monta o ContextInfo que cita outra mensagem
+func (c *Client) quoteContext(quote *Quote) *waProto.ContextInfo {
+	participant := quote.SenderJID
+	if participant == "" && c.wa.Store.ID != nil {
+		participant = c.wa.Store.ID.ToNonAD().String()
+	} else if participant != "" {
+		participant = PhoneToJID(participant).String()
+	}
+
+	return &waProto.ContextInfo{
+		StanzaID:      proto.String(quote.ID),
+		Participant:   proto.String(participant),
+		QuotedMessage: &waProto.Message{Conversation: proto.String(quote.Content)},
+	}
+}
+
+// GetGroupInfo busca metadados e participantes de um grupo
+func (c *Client) GetGroupInfo(ctx context.Context, groupJID string) (*GroupInfo, error) {
+	if !c.IsConnected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+
+	jid, err := types.ParseJID(groupJID)
+	if err != nil || jid.Server != types.GroupServer {
+		return nil, fmt.Errorf("invalid group jid: %s", groupJID)
+	}
+
+	info, err := c.wa.GetGroupInfo(ctx, jid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group info: %w", err)
+	}
+	return c.convertGroupInfo(info), nil
+}
+
+// GetJoinedGroups lista os grupos dos quais o numero participa
+func (c *Client) GetJoinedGroups(ctx context.Context) ([]*GroupInfo, error) {
+	if !c.IsConnected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+
+	groups, err := c.wa.GetJoinedGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get joined groups: %w", err)
+	}
+
+	result := make([]*GroupInfo, 0, len(groups))
+	for _, info := range groups {
+		result = append(result, c.convertGroupInfo(info))
+	}
+	return result, nil
+}
+
+// convertGroupInfo converte metadados do whatsmeow, resolvendo LIDs para telefone
+func (c *Client) convertGroupInfo(info *types.GroupInfo) *GroupInfo {
+	group := &GroupInfo{
+		JID:       info.JID.String(),
+		Name:      info.Name,
+		Topic:     info.Topic,
+		Announce:  info.IsAnnounce,
+		CreatedAt: info.GroupCreated,
+	}
+	if !info.OwnerPN.IsEmpty() {
+		group.OwnerJID = info.OwnerPN.String()
+	} else if !info.OwnerJID.IsEmpty() {
+		group.OwnerJID = c.resolveJID(info.OwnerJID).String()
+	}
+
+	for _, p := range info.Participants {
+		jid := p.PhoneNumber
+		if jid.IsEmpty() {
+			jid = c.resolveJID(p.JID)
+		}
+		participant := GroupParticipant{
+			JID:          jid.String(),
+			Phone:        PhoneFromJID(jid.String()),
+			Name:         p.DisplayName,
+			IsAdmin:      p.IsAdmin,
+			IsSuperAdmin: p.IsSuperAdmin,
+		}
+		if participant.Name == "" {
+			participant.Name = c.GetContactName(jid)
+		}
+		group.Participants = append(group.Participants, participant)
+	}
+	return group
+}
+
 // GetContactName busca nome do contato
 func (c *Client) GetContactName(jid types.JID) string {
 	ctx := context.Background()
@@ -348,8 +780,14 @@ func (c *Client) handleEvent(evt interface{}) {
 		c.mu.Lock()
 		c.status = StatusConnected
 		c.qrCode = ""
+		c.health.Attempt = 0
+		c.health.LastError = ""
+		c.health.NextRetryAt = time.Time{}
+		c.health.ConnectedSince = time.Now()
 		c.mu.Unlock()
 
+		c.announceAvailable()
+
 		if c.handler != nil {
 			c.handler.OnConnected(c.GetPhone(), c.GetJID())
 		}
@@ -366,33 +804,84 @@ func (c *Client) handleEvent(evt interface{}) {
 		}
 
 	case *events.Disconnected:
+		// Queda transitoria: o supervisor reconecta
 		log.Printf("[WhatsApp] Disconnected")
-		c.mu.Lock()
-		c.status = StatusDisconnected
-		c.mu.Unlock()
+		c.setStatus(StatusDisconnected)
+		c.clearPresence()
+		c.signalDropped()
 
 		if c.handler != nil {
 			c.handler.OnDisconnected()
 		}
 
 	case *events.LoggedOut:
-		log.Printf("[WhatsApp] LoggedOut")
-		c.mu.Lock()
-		c.status = StatusDisconnected
-		c.mu.Unlock()
+		// Sessao removida no aparelho: o store ja foi apagado, exige novo pareamento
+		log.Printf("[WhatsApp] LoggedOut: %s", v.Reason)
+		c.setTerminal("logged out")
 
 		if c.handler != nil {
 			c.handler.OnLoggedOut()
 		}
 
+	case *events.StreamReplaced:
+		// Outra conexao assumiu a mesma sessao: reconectar derrubaria a outra
+		log.Printf("[WhatsApp] Stream replaced by another connection")
+		c.setTerminal("stream replaced")
+
+		if c.handler != nil {
+			c.handler.OnDisconnected()
+		}
+
+	case *events.ClientOutdated:
+		log.Printf("[WhatsApp] Client outdated: update whatsmeow to reconnect")
+		c.setTerminal("client outdated")
+
+		if c.handler != nil {
+			c.handler.OnDisconnected()
+		}
+
+	case *events.ConnectFailure:
+		// Falha desconhecida no login: tentar novamente com backoff
+		log.Printf("[WhatsApp] Connect failure: %d %s", v.Reason, v.Message)
+		c.setStatus(StatusDisconnected)
+		c.signalDropped()
+
+	case *events.TemporaryBan:
+		log.Printf("[WhatsApp] Temporary ban: %s", v.String())
+		c.setTerminal("temporary ban: " + v.String())
+
+		if c.handler != nil {
+			c.handler.OnDisconnected()
+		}
+
 	case *events.Message:
 		if c.handler != nil {
-			event := c.parseMessage(v)
-			if event != nil {
-				c.handler.OnMessage(*event)
-			}
+			c.queueMessage(v)
+		}
+		if !v.Info.IsFromMe && !v.Info.IsGroup {
+			c.subscribePresence(c.resolveJID(v.Info.Chat))
+		}
+
+	case *events.GroupInfo:
+		if c.handler != nil {
+			c.handler.OnGroupUpdate(GroupEvent{JID: v.JID.String(), Timestamp: v.Timestamp})
+		}
+
+	case *events.JoinedGroup:
+		log.Printf("[WhatsApp] Joined group %s", v.JID.String())
+		if c.handler != nil {
+			c.handler.OnGroupUpdate(GroupEvent{JID: v.JID.String(), Timestamp: time.Now()})
 		}
 
+	case *events.HistorySync:
+		c.handleHistorySync(v)
+
+	case *events.ChatPresence:
+		c.handleChatPresence(v)
+
+	case *events.Presence:
+		c.handlePresence(v)
+
 	case *events.Receipt:
 		if c.handler != nil {
 			receiptType := ReceiptTypeDelivered
@@ -410,6 +899,40 @@ func (c *Client) handleEvent(evt interface{}) {
 	}
 }
 
+// queueMessage entrega a mensagem ao worker, que baixa a midia fora do evento do whatsmeow.
+// A ordem de chegada e mantida; o worker termina quando a fila esvazia.
+func (c *Client) queueMessage(evt *events.Message) {
+	c.messages <- evt
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.working {
+		c.working = true
+		go c.processMessages()
+	}
+}
+
+// processMessages converte e entrega as mensagens da fila
+func (c *Client) processMessages() {
+	for {
+		select {
+		case evt := <-c.messages:
+			if event := c.parseMessage(evt); event != nil {
+				c.downloadMedia(event, evt.Message)
+				c.handler.OnMessage(*event)
+			}
+		default:
+			c.mu.Lock()
+			if len(c.messages) == 0 {
+				c.working = false
+				c.mu.Unlock()
+				return
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
 func (c *Client) parseMessage(evt *events.Message) *MessageEvent {
 	senderJID := c.resolveJID(evt.Info.Sender)
 	chatJID := c.resolveJID(evt.Info.Chat)
@@ -420,32 +943,65 @@ func (c *Client) parseMessage(evt *events.Message) *MessageEvent {
 		SenderJID:  senderJID.String(),
 		SenderName: c.GetContactName(senderJID),
 		IsFromMe:   evt.Info.IsFromMe,
+		IsGroup:    evt.Info.IsGroup,
 		Timestamp:  evt.Info.Timestamp,
 		RawMessage: evt.Message,
 	}
+	// Remetente fora da agenda (comum em grupos): usar o push name
+	if !evt.Info.IsFromMe && evt.Info.PushName != "" && event.SenderName == JIDToPhone(senderJID) {
+		event.SenderName = evt.Info.PushName
+	}
 
-	if evt.Message.GetConversation() != "" {
-		event.Content = evt.Message.GetConversation()
-	} else if evt.Message.GetExtendedTextMessage() != nil {
-		event.Content = evt.Message.GetExtendedTextMessage().GetText()
-	} else if evt.Message.GetImageMessage() != nil {
-		event.MediaType = MediaTypeImage
-		event.Content = evt.Message.GetImageMessage().GetCaption()
-	} else if evt.Message.GetVideoMessage() != nil {
-		event.MediaType = MediaTypeVideo
-		event.Content = evt.Message.GetVideoMessage().GetCaption()
-	} else if evt.Message.GetAudioMessage() != nil {
-		event.MediaType = MediaTypeAudio
-	} else if evt.Message.GetDocumentMessage() != nil {
-		event.MediaType = MediaTypeDocument
-		event.Content = evt.Message.GetDocumentMessage().GetFileName()
-	} else {
+	if !parseContent(event, evt.Message) {
 		return nil
 	}
+	if event.Type == MessageTypeUnsupported {
+		log.Printf("[WhatsApp] Unsupported message %s (%s) stored as placeholder", event.ID, event.UnsupportedType)
+	}
 
 	return event
 }
 
+// downloadMedia baixa a midia da mensagem; acima do limite ou em caso de falha
+// a mensagem segue sem conteudo
+func (c *Client) downloadMedia(event *MessageEvent, msg *waProto.Message) {
+	var media whatsmeow.DownloadableMessage
+	switch {
+	case msg.GetImageMessage() != nil:
+		media = msg.GetImageMessage()
+	case msg.GetVideoMessage() != nil:
+		media = msg.GetVideoMessage()
+	case msg.GetPtvMessage() != nil:
+		media = msg.GetPtvMessage()
+	case msg.GetAudioMessage() != nil:
+		media = msg.GetAudioMessage()
+	case msg.GetDocumentMessage() != nil:
+		media = msg.GetDocumentMessage()
+	case msg.GetStickerMessage() != nil:
+		media = msg.GetStickerMessage()
+	default:
+		return
+	}
+
+	c.mu.RLock()
+	maxSize := c.mediaMax
+	c.mu.RUnlock()
+	if sized, ok := media.(interface{ GetFileLength() uint64 }); ok && sized.GetFileLength() > uint64(maxSize) {
+		log.Printf("[WhatsApp] Media of message %s not downloaded: %d bytes exceeds limit of %d", event.ID, sized.GetFileLength(), maxSize)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mediaDownloadTimeout)
+	defer cancel()
+
+	data, err := c.wa.Download(ctx, media)
+	if err != nil {
+		log.Printf("[WhatsApp] Failed to download media for message %s: %v", event.ID, err)
+		return
+	}
+	event.MediaData = data
+}
+
 func (c *Client) resolveJID(jid types.JID) types.JID {
 	if jid.Server == "lid" {
 		ctx := context.Background()
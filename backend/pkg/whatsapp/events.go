@@ -21,16 +21,143 @@ type QREvent struct {
 
 // MessageEvent mensagem recebida do WhatsApp
 type MessageEvent struct {
-	ID          string
-	ChatJID     string
-	SenderJID   string
-	SenderName  string
-	Content     string
-	MediaType   MediaType
-	MediaURL    string
-	IsFromMe    bool
-	Timestamp   time.Time
-	RawMessage  interface{}
+	ID         string
+	ChatJID    string
+	SenderJID  string
+	SenderName string
+	Type       MessageType
+	Content    string
+	MediaType  MediaType
+	MediaURL   string
+	MediaData  []byte // Conteudo baixado (vazio se o download falhar)
+	MimeType   string
+	FileName   string
+	IsFromMe   bool
+	IsGroup    bool // ChatJID e um grupo; SenderJID e o participante
+	Timestamp  time.Time
+	RawMessage interface{}
+
+	// Dados estruturados conforme o Type
+	Location        *Location
+	Contacts        []Contact
+	Poll            *Poll
+	Reply           *InteractiveReply
+	UnsupportedType string // Campo do proto nao suportado (Type == MessageTypeUnsupported)
+
+	// Respostas, reacoes, edicoes e remocoes
+	QuotedID string // Mensagem citada
+	TargetID string // Mensagem alvo (reaction, edit, revoke)
+	Reaction string // Emoji (vazio remove a reacao)
+}
+
+// MessageType tipo da mensagem recebida
+type MessageType string
+
+const (
+	MessageTypeText             MessageType = "text"
+	MessageTypeMedia            MessageType = "media"
+	MessageTypeLocation         MessageType = "location"
+	MessageTypeLiveLocation     MessageType = "live_location"
+	MessageTypeContact          MessageType = "contact"
+	MessageTypePoll             MessageType = "poll"
+	MessageTypeInteractiveReply MessageType = "interactive_reply"
+	MessageTypeUnsupported      MessageType = "unsupported"
+	MessageTypeReaction         MessageType = "reaction"
+	MessageTypeEdit             MessageType = "edit"
+	MessageTypeRevoke           MessageType = "revoke"
+)
+
+// Location localizacao (fixa ou em tempo real)
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Name      string  `json:"name,omitempty"`
+	Address   string  `json:"address,omitempty"`
+	URL       string  `json:"url,omitempty"`
+	Accuracy  uint32  `json:"accuracy,omitempty"` // Metros (tempo real)
+	Speed     float32 `json:"speed,omitempty"`    // m/s (tempo real)
+	Sequence  int64   `json:"sequence,omitempty"` // Numero da atualizacao (tempo real)
+	Live      bool    `json:"live,omitempty"`
+}
+
+// Contact cartao de contato recebido
+type Contact struct {
+	Name  string `json:"name"`
+	Phone string `json:"phone,omitempty"`
+	VCard string `json:"vcard,omitempty"`
+}
+
+// Poll enquete
+type Poll struct {
+	Question        string   `json:"question"`
+	Options         []string `json:"options"`
+	SelectableCount uint32   `json:"selectable_count,omitempty"`
+}
+
+// InteractiveReply resposta a botoes, listas ou fluxos
+type InteractiveReply struct {
+	Type        string `json:"type"` // button_reply, template_button_reply, list_reply, native_flow_reply
+	ID          string `json:"id,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Params      string `json:"params,omitempty"` // JSON de resposta de fluxo nativo
+}
+
+// GroupInfo metadados de um grupo
+type GroupInfo struct {
+	JID          string
+	Name         string
+	Topic        string
+	OwnerJID     string
+	Announce     bool // Apenas admins enviam mensagens
+	Participants []GroupParticipant
+	CreatedAt    time.Time
+}
+
+// GroupParticipant participante de um grupo
+type GroupParticipant struct {
+	JID          string
+	Phone        string // Vazio se o numero nao for conhecido (LID)
+	Name         string
+	IsAdmin      bool
+	IsSuperAdmin bool
+}
+
+// GroupEvent alteracao de um grupo (metadados, participantes ou entrada no grupo)
+type GroupEvent struct {
+	JID       string
+	Timestamp time.Time
+}
+
+// ChatPresenceEvent contato digitando ou gravando audio em um chat
+type ChatPresenceEvent struct {
+	ChatJID   string
+	SenderJID string
+	IsGroup   bool
+	State     string // composing, recording, paused
+}
+
+// PresenceEvent contato ficou online ou offline
+type PresenceEvent struct {
+	JID      string
+	Online   bool
+	LastSeen time.Time // Zero se o contato oculta o visto por ultimo
+}
+
+// HistorySyncEvent lote de historico enviado pelo aparelho apos o pareamento
+type HistorySyncEvent struct {
+	SyncType      string // initial_bootstrap, recent, full, on_demand
+	ChunkOrder    uint32
+	Progress      uint32 // Percentual informado pelo aparelho (0-100)
+	Conversations int    // Conversas presentes no lote
+	Messages      []MessageEvent
+}
+
+// HistoryConfig limites da importacao de historico
+type HistoryConfig struct {
+	Enabled         bool
+	Days            int // Mensagens mais antigas sao ignoradas (0 = sem limite)
+	MessagesPerChat int // Mensagens mais recentes por conversa em cada lote (0 = sem limite)
 }
 
 // MediaType tipo de midia
@@ -76,5 +203,10 @@ type EventHandler interface {
 	OnQRCode(event QREvent)
 	OnConnected(phone, jid string)
 	OnDisconnected()
+	OnReconnecting(event ReconnectEvent)
 	OnLoggedOut()
+	OnGroupUpdate(event GroupEvent)
+	OnHistorySync(event HistorySyncEvent)
+	OnChatPresence(event ChatPresenceEvent)
+	OnPresence(event PresenceEvent)
 }
@@ -39,6 +39,20 @@ func JIDToPhone(jid types.JID) string {
 	return "+" + jid.User
 }
 
+// PhoneFromJID retorna o telefone de um JID de usuario (vazio para grupos e LIDs)
+func PhoneFromJID(jidStr string) string {
+	jid := PhoneToJID(jidStr)
+	if jid.Server != types.DefaultUserServer {
+		return ""
+	}
+	return JIDToPhone(jid)
+}
+
+// IsGroupJID verifica se o JID e de um grupo
+func IsGroupJID(jidStr string) bool {
+	return strings.HasSuffix(jidStr, "@"+types.GroupServer)
+}
+
 // ParseJID faz parse de string JID
 func ParseJID(jidStr string) (types.JID, error) {
 	return types.ParseJID(jidStr)
@@ -60,3 +74,34 @@ func IsValidPhone(phone string) bool {
 	}
 	return true
 }
+
+// BuildVCard monta vCard 3.0 de um contato; o waid permite abrir a conversa no WhatsApp
+func BuildVCard(name, phone, email, organization string) string {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCARD\nVERSION:3.0\n")
+	b.WriteString("FN:" + vcardEscape(name) + "\n")
+	if organization != "" {
+		b.WriteString("ORG:" + vcardEscape(organization) + ";\n")
+	}
+	if digits := onlyDigits(phone); digits != "" {
+		b.WriteString("TEL;type=CELL;type=VOICE;waid=" + digits + ":+" + digits + "\n")
+	}
+	if email != "" {
+		b.WriteString("EMAIL;type=INTERNET:" + vcardEscape(email) + "\n")
+	}
+	b.WriteString("END:VCARD")
+	return b.String()
+}
+
+func vcardEscape(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "\n", "\\n", ",", "\\,", ";", "\\;").Replace(s)
+}
+
+func onlyDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
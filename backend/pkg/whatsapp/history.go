@@ -0,0 +1,89 @@
+package whatsapp
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waHistorySync"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// SetHistoryConfig define se e como o historico recebido no pareamento e importado
+func (c *Client) SetHistoryConfig(config HistoryConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.history = config
+}
+
+// handleHistorySync converte um lote de historico em mensagens, respeitando os limites
+func (c *Client) handleHistorySync(evt *events.HistorySync) {
+	c.mu.RLock()
+	config := c.history
+	c.mu.RUnlock()
+
+	data := evt.Data
+	if !config.Enabled || c.handler == nil || data == nil {
+		return
+	}
+
+	// Lotes apenas com push names, configuracoes etc. nao trazem mensagens
+	switch data.GetSyncType() {
+	case waHistorySync.HistorySync_INITIAL_BOOTSTRAP, waHistorySync.HistorySync_RECENT,
+		waHistorySync.HistorySync_FULL, waHistorySync.HistorySync_ON_DEMAND:
+	default:
+		return
+	}
+
+	var cutoff time.Time
+	if config.Days > 0 {
+		cutoff = time.Now().AddDate(0, 0, -config.Days)
+	}
+
+	event := HistorySyncEvent{
+		SyncType:   strings.ToLower(data.GetSyncType().String()),
+		ChunkOrder: data.GetChunkOrder(),
+		Progress:   data.GetProgress(),
+	}
+
+	for _, conv := range data.GetConversations() {
+		chatJID, err := types.ParseJID(conv.GetID())
+		if err != nil || chatJID.Server == types.BroadcastServer {
+			continue
+		}
+
+		count := 0
+		// Mensagens chegam da mais recente para a mais antiga
+		for _, item := range conv.GetMessages() {
+			if config.MessagesPerChat > 0 && count >= config.MessagesPerChat {
+				break
+			}
+
+			msg, err := c.wa.ParseWebMessage(chatJID, item.GetMessage())
+			if err != nil {
+				log.Printf("[WhatsApp] Failed to parse history message in %s: %v", chatJID, err)
+				continue
+			}
+			if !cutoff.IsZero() && msg.Info.Timestamp.Before(cutoff) {
+				break
+			}
+
+			// Midia do historico nao e baixada: a mensagem fica apenas com o tipo/legenda
+			parsed := c.parseMessage(msg)
+			if parsed == nil {
+				continue
+			}
+			event.Messages = append(event.Messages, *parsed)
+			count++
+		}
+		if count > 0 {
+			event.Conversations++
+		}
+	}
+
+	log.Printf("[WhatsApp] History sync %s chunk %d (%d%%): %d messages in %d conversations",
+		event.SyncType, event.ChunkOrder, event.Progress, len(event.Messages), event.Conversations)
+
+	c.handler.OnHistorySync(event)
+}
@@ -0,0 +1,313 @@
+package whatsapp
+
+import (
+	"strings"
+
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ignoredFields campos do proto que nao sao conteudo para o usuario
+// (controle de criptografia, protocolo, reacoes e votos)
+var ignoredFields = map[protoreflect.Name]bool{
+	"senderKeyDistributionMessage":               true,
+	"fastRatchetKeySenderKeyDistributionMessage": true,
+	"messageContextInfo":                         true,
+	"protocolMessage":                            true,
+	"reactionMessage":                            true,
+	"encReactionMessage":                         true,
+	"pollUpdateMessage":                          true,
+	"keepInChatMessage":                          true,
+	"pinInChatMessage":                           true,
+	"stickerSyncRmrMessage":                      true,
+	"encEventResponseMessage":                    true,
+	"encCommentMessage":                          true,
+}
+
+// parseContent preenche tipo, conteudo e dados estruturados da mensagem.
+// Retorna false para mensagens sem conteudo para o usuario.
+func parseContent(event *MessageEvent, msg *waProto.Message) bool {
+	if msg == nil {
+		return false
+	}
+
+	if reaction := msg.GetReactionMessage(); reaction != nil {
+		event.Type = MessageTypeReaction
+		event.TargetID = reaction.GetKey().GetID()
+		event.Reaction = reaction.GetText()
+		return event.TargetID != ""
+	}
+
+	if pm := msg.GetProtocolMessage(); pm != nil {
+		return parseProtocol(event, pm)
+	}
+
+	if info := quotedContext(msg); info != nil {
+		event.QuotedID = info.GetStanzaID()
+	}
+
+	switch {
+	case msg.GetConversation() != "":
+		event.Type = MessageTypeText
+		event.Content = msg.GetConversation()
+
+	case msg.GetExtendedTextMessage() != nil:
+		event.Type = MessageTypeText
+		event.Content = msg.GetExtendedTextMessage().GetText()
+
+	case msg.GetImageMessage() != nil:
+		img := msg.GetImageMessage()
+		event.Type = MessageTypeMedia
+		event.MediaType = MediaTypeImage
+		event.Content = img.GetCaption()
+		event.MimeType = img.GetMimetype()
+
+	case msg.GetVideoMessage() != nil || msg.GetPtvMessage() != nil:
+		video := msg.GetVideoMessage()
+		if video == nil {
+			video = msg.GetPtvMessage()
+		}
+		event.Type = MessageTypeMedia
+		event.MediaType = MediaTypeVideo
+		event.Content = video.GetCaption()
+		event.MimeType = video.GetMimetype()
+
+	case msg.GetAudioMessage() != nil:
+		event.Type = MessageTypeMedia
+		event.MediaType = MediaTypeAudio
+		event.MimeType = msg.GetAudioMessage().GetMimetype()
+
+	case msg.GetDocumentMessage() != nil:
+		doc := msg.GetDocumentMessage()
+		event.Type = MessageTypeMedia
+		event.MediaType = MediaTypeDocument
+		event.Content = doc.GetCaption()
+		if event.Content == "" {
+			event.Content = doc.GetFileName()
+		}
+		event.MimeType = doc.GetMimetype()
+		event.FileName = doc.GetFileName()
+
+	case msg.GetStickerMessage() != nil:
+		event.Type = MessageTypeMedia
+		event.MediaType = MediaTypeSticker
+		event.MimeType = msg.GetStickerMessage().GetMimetype()
+
+	case msg.GetLocationMessage() != nil:
+		loc := msg.GetLocationMessage()
+		event.Type = MessageTypeLocation
+		event.Content = loc.GetComment()
+		event.Location = &Location{
+			Latitude:  loc.GetDegreesLatitude(),
+			Longitude: loc.GetDegreesLongitude(),
+			Name:      loc.GetName(),
+			Address:   loc.GetAddress(),
+			URL:       loc.GetURL(),
+		}
+
+	case msg.GetLiveLocationMessage() != nil:
+		loc := msg.GetLiveLocationMessage()
+		event.Type = MessageTypeLiveLocation
+		event.Content = loc.GetCaption()
+		event.Location = &Location{
+			Latitude:  loc.GetDegreesLatitude(),
+			Longitude: loc.GetDegreesLongitude(),
+			Accuracy:  loc.GetAccuracyInMeters(),
+			Speed:     loc.GetSpeedInMps(),
+			Sequence:  loc.GetSequenceNumber(),
+			Live:      true,
+		}
+
+	case msg.GetContactMessage() != nil:
+		contact := msg.GetContactMessage()
+		event.Type = MessageTypeContact
+		event.Content = contact.GetDisplayName()
+		event.Contacts = []Contact{parseContact(contact)}
+
+	case msg.GetContactsArrayMessage() != nil:
+		array := msg.GetContactsArrayMessage()
+		event.Type = MessageTypeContact
+		event.Content = array.GetDisplayName()
+		for _, contact := range array.GetContacts() {
+			event.Contacts = append(event.Contacts, parseContact(contact))
+		}
+
+	case pollCreation(msg) != nil:
+		poll := pollCreation(msg)
+		event.Type = MessageTypePoll
+		event.Content = poll.GetName()
+		event.Poll = &Poll{
+			Question:        poll.GetName(),
+			SelectableCount: poll.GetSelectableOptionsCount(),
+		}
+		for _, opt := range poll.GetOptions() {
+			event.Poll.Options = append(event.Poll.Options, opt.GetOptionName())
+		}
+
+	case msg.GetButtonsResponseMessage() != nil:
+		resp := msg.GetButtonsResponseMessage()
+		event.Type = MessageTypeInteractiveReply
+		event.Content = resp.GetSelectedDisplayText()
+		event.Reply = &InteractiveReply{
+			Type:  "button_reply",
+			ID:    resp.GetSelectedButtonID(),
+			Title: resp.GetSelectedDisplayText(),
+		}
+
+	case msg.GetTemplateButtonReplyMessage() != nil:
+		resp := msg.GetTemplateButtonReplyMessage()
+		event.Type = MessageTypeInteractiveReply
+		event.Content = resp.GetSelectedDisplayText()
+		event.Reply = &InteractiveReply{
+			Type:  "template_button_reply",
+			ID:    resp.GetSelectedID(),
+			Title: resp.GetSelectedDisplayText(),
+		}
+
+	case msg.GetListResponseMessage() != nil:
+		resp := msg.GetListResponseMessage()
+		event.Type = MessageTypeInteractiveReply
+		event.Content = resp.GetTitle()
+		event.Reply = &InteractiveReply{
+			Type:        "list_reply",
+			ID:          resp.GetSingleSelectReply().GetSelectedRowID(),
+			Title:       resp.GetTitle(),
+			Description: resp.GetDescription(),
+		}
+
+	case msg.GetInteractiveResponseMessage() != nil:
+		resp := msg.GetInteractiveResponseMessage()
+		flow := resp.GetNativeFlowResponseMessage()
+		event.Type = MessageTypeInteractiveReply
+		event.Content = resp.GetBody().GetText()
+		event.Reply = &InteractiveReply{
+			Type:   "native_flow_reply",
+			ID:     flow.GetName(),
+			Title:  resp.GetBody().GetText(),
+			Params: flow.GetParamsJSON(),
+		}
+
+	default:
+		field := contentField(msg)
+		if field == "" {
+			return false
+		}
+		event.Type = MessageTypeUnsupported
+		event.UnsupportedType = field
+	}
+
+	return true
+}
+
+// parseProtocol trata remocao ("apagar para todos") e edicao de mensagens
+func parseProtocol(event *MessageEvent, pm *waProto.ProtocolMessage) bool {
+	switch pm.GetType() {
+	case waProto.ProtocolMessage_REVOKE:
+		event.Type = MessageTypeRevoke
+	case waProto.ProtocolMessage_MESSAGE_EDIT:
+		edited := &MessageEvent{}
+		if !parseContent(edited, pm.GetEditedMessage()) {
+			return false
+		}
+		event.Type = MessageTypeEdit
+		event.Content = edited.Content
+	default:
+		return false
+	}
+
+	event.TargetID = pm.GetKey().GetID()
+	return event.TargetID != ""
+}
+
+// quotedContext retorna o ContextInfo que cita outra mensagem, se houver
+func quotedContext(msg *waProto.Message) *waProto.ContextInfo {
+	var info *waProto.ContextInfo
+	msg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() != protoreflect.MessageKind {
+			return true
+		}
+		field := fd.Message().Fields().ByName("contextInfo")
+		if field == nil || !v.Message().Has(field) {
+			return true
+		}
+		if ci, ok := v.Message().Get(field).Message().Interface().(*waProto.ContextInfo); ok && ci.GetStanzaID() != "" {
+			info = ci
+			return false
+		}
+		return true
+	})
+	return info
+}
+
+// setContextInfo define o ContextInfo no conteudo da mensagem
+func setContextInfo(msg *waProto.Message, info *waProto.ContextInfo) {
+	msg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() != protoreflect.MessageKind {
+			return true
+		}
+		field := fd.Message().Fields().ByName("contextInfo")
+		if field == nil {
+			return true
+		}
+		v.Message().Set(field, protoreflect.ValueOfMessage(info.ProtoReflect()))
+		return false
+	})
+}
+
+// pollCreation retorna a enquete em qualquer das versoes do proto
+func pollCreation(msg *waProto.Message) *waProto.PollCreationMessage {
+	for _, poll := range []*waProto.PollCreationMessage{
+		msg.GetPollCreationMessage(),
+		msg.GetPollCreationMessageV2(),
+		msg.GetPollCreationMessageV3(),
+		msg.GetPollCreationMessageV5(),
+	} {
+		if poll != nil {
+			return poll
+		}
+	}
+	return nil
+}
+
+// parseContact extrai nome, telefone e vCard
+func parseContact(contact *waProto.ContactMessage) Contact {
+	return Contact{
+		Name:  contact.GetDisplayName(),
+		Phone: vcardPhone(contact.GetVcard()),
+		VCard: contact.GetVcard(),
+	}
+}
+
+// vcardPhone retorna o primeiro telefone do vCard (preferindo o waid)
+func vcardPhone(vcard string) string {
+	for _, line := range strings.Split(vcard, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(strings.ToUpper(line), "TEL") {
+			continue
+		}
+		if i := strings.Index(line, "waid="); i >= 0 {
+			if digits := onlyDigits(strings.SplitN(line[i+5:], ":", 2)[0]); digits != "" {
+				return "+" + digits
+			}
+		}
+		if i := strings.LastIndex(line, ":"); i >= 0 {
+			if digits := onlyDigits(line[i+1:]); digits != "" {
+				return "+" + digits
+			}
+		}
+	}
+	return ""
+}
+
+// contentField retorna o nome do primeiro campo de conteudo preenchido
+func contentField(msg *waProto.Message) string {
+	var field string
+	msg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if ignoredFields[fd.Name()] {
+			return true
+		}
+		field = string(fd.Name())
+		return false
+	})
+	return field
+}
@@ -0,0 +1,102 @@
+package whatsapp
+
+import (
+	"context"
+	"log"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// maxPresenceSubscriptions contatos com presenca assinada por conexao
+const maxPresenceSubscriptions = 1000
+
+// SetAnnounceOnline define se o dispositivo se marca como online ao conectar (padrao: nao).
+// Online, o WhatsApp envia digitacao e presenca dos contatos, mas deixa de
+// enviar notificacoes push ao aparelho pareado enquanto a conexao durar.
+func (c *Client) SetAnnounceOnline(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.announce = enabled
+}
+
+// announceAvailable marca o dispositivo como online, se habilitado;
+// sem isso o WhatsApp nao envia digitacao nem presenca dos contatos
+func (c *Client) announceAvailable() {
+	c.mu.RLock()
+	enabled := c.announce
+	c.mu.RUnlock()
+	if !enabled {
+		return
+	}
+
+	if err := c.wa.SendPresence(context.Background(), types.PresenceAvailable); err != nil {
+		log.Printf("[WhatsApp] Failed to send available presence: %v", err)
+	}
+
+	c.mu.Lock()
+	c.presence = make(map[types.JID]bool)
+	c.mu.Unlock()
+}
+
+// clearPresence descarta as assinaturas de presenca (validas apenas na conexao atual)
+func (c *Client) clearPresence() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.presence = nil
+}
+
+// subscribePresence assina online/visto por ultimo do contato (uma vez por conexao,
+// ate maxPresenceSubscriptions contatos)
+func (c *Client) subscribePresence(jid types.JID) {
+	if jid.Server != types.DefaultUserServer && jid.Server != types.HiddenUserServer {
+		return
+	}
+
+	c.mu.Lock()
+	if c.presence == nil || c.presence[jid] || len(c.presence) >= maxPresenceSubscriptions {
+		c.mu.Unlock()
+		return
+	}
+	c.presence[jid] = true
+	c.mu.Unlock()
+
+	if err := c.wa.SubscribePresence(context.Background(), jid); err != nil {
+		log.Printf("[WhatsApp] Failed to subscribe presence of %s: %v", jid, err)
+	}
+}
+
+// handleChatPresence repassa digitacao/gravacao do contato
+func (c *Client) handleChatPresence(evt *events.ChatPresence) {
+	if c.handler == nil || evt.IsFromMe {
+		return
+	}
+
+	state := "paused"
+	if evt.State == types.ChatPresenceComposing {
+		state = "composing"
+		if evt.Media == types.ChatPresenceMediaAudio {
+			state = "recording"
+		}
+	}
+
+	c.handler.OnChatPresence(ChatPresenceEvent{
+		ChatJID:   c.resolveJID(evt.Chat).String(),
+		SenderJID: c.resolveJID(evt.Sender).String(),
+		IsGroup:   evt.IsGroup,
+		State:     state,
+	})
+}
+
+// handlePresence repassa online/offline do contato
+func (c *Client) handlePresence(evt *events.Presence) {
+	if c.handler == nil {
+		return
+	}
+
+	c.handler.OnPresence(PresenceEvent{
+		JID:      c.resolveJID(evt.From).String(),
+		Online:   !evt.Unavailable,
+		LastSeen: evt.LastSeen,
+	})
+}
@@ -0,0 +1,148 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// ErrNotOnWhatsApp numero nao possui conta no WhatsApp
+var ErrNotOnWhatsApp = errors.New("number is not registered on WhatsApp")
+
+// NumberInfo identidade de um numero no WhatsApp
+type NumberInfo struct {
+	JID          string // JID canonico (telefone@s.whatsapp.net)
+	LID          string // Vazio se o LID ainda nao e conhecido
+	Phone        string
+	BusinessName string // Nome verificado de contas comerciais
+}
+
+// ContactProfile foto e perfil publico de um contato
+type ContactProfile struct {
+	JID            string
+	PictureID      string // Vazio se o contato nao tem foto ou a esconde
+	PictureURL     string // Preenchido apenas quando a foto mudou em relacao a knownPictureID
+	PictureChanged bool
+	About          string
+	Business       *BusinessProfile // nil para contas pessoais
+}
+
+// BusinessProfile perfil de uma conta WhatsApp Business
+type BusinessProfile struct {
+	Name       string
+	Address    string
+	Email      string
+	Categories []string
+	Website    string
+	Timezone   string
+}
+
+// GetContactProfile busca foto e perfil comercial de um contato (ou a foto de um grupo).
+// knownPictureID evita baixar de novo uma foto que nao mudou.
+func (c *Client) GetContactProfile(ctx context.Context, jidStr, knownPictureID string) (*ContactProfile, error) {
+	if !c.IsConnected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+
+	jid, err := types.ParseJID(jidStr)
+	if err != nil || jid.Server == types.BroadcastServer {
+		return nil, fmt.Errorf("invalid contact jid: %s", jidStr)
+	}
+	jid = jid.ToNonAD()
+
+	profile := &ContactProfile{JID: jid.String()}
+
+	// Grupos tem apenas foto; recado e perfil comercial sao de usuarios
+	var info types.UserInfo
+	var found bool
+	if jid.Server != types.GroupServer {
+		infos, err := c.wa.GetUserInfo(ctx, []types.JID{jid})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user info: %w", err)
+		}
+		if info, found = infos[jid]; found {
+			profile.About = info.Status
+		}
+	}
+
+	picture, err := c.wa.GetProfilePictureInfo(ctx, jid, &whatsmeow.GetProfilePictureParams{ExistingID: knownPictureID})
+	switch {
+	case errors.Is(err, whatsmeow.ErrProfilePictureNotSet), errors.Is(err, whatsmeow.ErrProfilePictureUnauthorized):
+		profile.PictureChanged = knownPictureID != ""
+	case err != nil:
+		return nil, fmt.Errorf("failed to get profile picture: %w", err)
+	case picture == nil:
+		// Foto igual a knownPictureID
+		profile.PictureID = knownPictureID
+	default:
+		profile.PictureID = picture.ID
+		profile.PictureURL = picture.URL
+		profile.PictureChanged = picture.ID != knownPictureID
+	}
+
+	if found && info.VerifiedName != nil {
+		business, err := c.wa.GetBusinessProfile(ctx, jid)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get business profile: %w", err)
+		}
+		profile.Business = convertBusinessProfile(business)
+		if details := info.VerifiedName.Details; details != nil {
+			profile.Business.Name = details.GetVerifiedName()
+		}
+	}
+
+	return profile, nil
+}
+
+// convertBusinessProfile converte perfil comercial do whatsmeow
+func convertBusinessProfile(bp *types.BusinessProfile) *BusinessProfile {
+	business := &BusinessProfile{
+		Address:  bp.Address,
+		Email:    bp.Email,
+		Website:  bp.ProfileOptions["website"],
+		Timezone: bp.BusinessHoursTimeZone,
+	}
+	for _, category := range bp.Categories {
+		if category.Name != "" {
+			business.Categories = append(business.Categories, category.Name)
+		}
+	}
+	return business
+}
+
+// CheckNumber verifica se o numero tem WhatsApp e resolve seu JID e LID
+func (c *Client) CheckNumber(ctx context.Context, phone string) (*NumberInfo, error) {
+	if !c.IsConnected() {
+		return nil, fmt.Errorf("client not connected")
+	}
+
+	digits := onlyDigits(phone)
+	if !IsValidPhone(digits) {
+		return nil, fmt.Errorf("invalid phone number: %s", phone)
+	}
+
+	results, err := c.wa.IsOnWhatsApp(ctx, []string{"+" + digits})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check number: %w", err)
+	}
+	if len(results) == 0 || !results[0].IsIn || results[0].JID.IsEmpty() {
+		return nil, ErrNotOnWhatsApp
+	}
+
+	result := results[0]
+	jid := result.JID.ToNonAD()
+	info := &NumberInfo{
+		JID:   jid.String(),
+		Phone: JIDToPhone(jid),
+	}
+	if lid, err := c.wa.Store.LIDs.GetLIDForPN(ctx, jid); err == nil && !lid.IsEmpty() {
+		info.LID = lid.String()
+	}
+	if result.VerifiedName != nil && result.VerifiedName.Details != nil {
+		info.BusinessName = result.VerifiedName.Details.GetVerifiedName()
+	}
+	return info, nil
+}
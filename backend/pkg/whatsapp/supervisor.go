@@ -0,0 +1,176 @@
+package whatsapp
+
+import (
+	"errors"
+	"log"
+	"math"
+	"math/rand"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+)
+
+// errConnectionLost queda da conexao apos a sessao ter sido estabelecida
+var errConnectionLost = errors.New("connection lost")
+
+// ReconnectPolicy backoff exponencial com jitter entre tentativas de reconexao
+type ReconnectPolicy struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	Jitter     float64 // Fracao aleatoria (+/-) aplicada ao atraso
+}
+
+// DefaultReconnectPolicy politica usada pelos clientes
+var DefaultReconnectPolicy = ReconnectPolicy{
+	Initial:    2 * time.Second,
+	Max:        5 * time.Minute,
+	Multiplier: 2,
+	Jitter:     0.2,
+}
+
+// Delay atraso antes da tentativa (a partir de 1)
+func (p ReconnectPolicy) Delay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := float64(p.Initial) * math.Pow(p.Multiplier, float64(attempt-1))
+	if delay > float64(p.Max) {
+		delay = float64(p.Max)
+	}
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+// Health estado da conexao mantido pelo supervisor
+type Health struct {
+	Status         Status
+	Attempt        int // Tentativas de reconexao consecutivas
+	LastError      string
+	NextRetryAt    time.Time
+	ConnectedSince time.Time
+	LastDisconnect time.Time
+}
+
+// ReconnectEvent tentativa de reconexao agendada pelo supervisor
+type ReconnectEvent struct {
+	Attempt int
+	Delay   time.Duration
+	Error   error
+}
+
+// Health retorna o estado atual da conexao
+func (c *Client) Health() Health {
+	status := c.Status()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	health := c.health
+	health.Status = status
+	return health
+}
+
+// supervise mantem a sessao conectada ate Disconnect, logout ou sessao substituida,
+// reconectando com backoff exponencial e jitter apos quedas transitorias
+func (c *Client) supervise(stop <-chan struct{}) {
+	attempt := 0
+	for {
+		err := c.connectOnce()
+		if err == nil {
+			select {
+			case <-stop:
+				c.wa.Disconnect()
+				return
+			case <-c.dropped:
+			}
+
+			// Queda de uma sessao estabelecida recomeca a contagem
+			c.mu.Lock()
+			if !c.health.ConnectedSince.IsZero() {
+				attempt = 0
+			}
+			c.health.ConnectedSince = time.Time{}
+			c.health.LastDisconnect = time.Now()
+			c.mu.Unlock()
+			err = errConnectionLost
+		}
+
+		if reason := c.terminalReason(); reason != "" {
+			log.Printf("[WhatsApp] Supervisor stopped: %s", reason)
+			return
+		}
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		attempt++
+		delay := c.policy.Delay(attempt)
+
+		c.mu.Lock()
+		c.status = StatusConnecting
+		c.health.Attempt = attempt
+		c.health.LastError = err.Error()
+		c.health.NextRetryAt = time.Now().Add(delay)
+		c.mu.Unlock()
+
+		log.Printf("[WhatsApp] Reconnecting in %s (attempt %d): %v", delay.Round(time.Millisecond), attempt, err)
+		if c.handler != nil {
+			c.handler.OnReconnecting(ReconnectEvent{Attempt: attempt, Delay: delay, Error: err})
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// connectOnce abre o websocket da sessao salva
+func (c *Client) connectOnce() error {
+	// Descartar sinais de queda anteriores a esta tentativa
+	select {
+	case <-c.dropped:
+	default:
+	}
+
+	if err := c.wa.Connect(); err != nil && !errors.Is(err, whatsmeow.ErrAlreadyConnected) {
+		return err
+	}
+	return nil
+}
+
+// signalDropped acorda o supervisor apos queda ou encerramento da sessao
+func (c *Client) signalDropped() {
+	select {
+	case c.dropped <- struct{}{}:
+	default:
+	}
+}
+
+// setTerminal encerra a supervisao: a sessao nao pode ser retomada automaticamente
+func (c *Client) setTerminal(reason string) {
+	c.mu.Lock()
+	c.terminal = reason
+	c.status = StatusDisconnected
+	c.health.ConnectedSince = time.Time{}
+	c.health.LastDisconnect = time.Now()
+	c.health.LastError = reason
+	c.health.NextRetryAt = time.Time{}
+	c.presence = nil
+	c.mu.Unlock()
+
+	c.signalDropped()
+}
+
+func (c *Client) terminalReason() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.terminal
+}
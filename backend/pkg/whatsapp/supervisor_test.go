@@ -0,0 +1,54 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconnectPolicyDelay(t *testing.T) {
+	policy := ReconnectPolicy{Initial: 2 * time.Second, Max: time.Minute, Multiplier: 2}
+
+	tests := []struct {
+		name    string
+		attempt int
+		want    time.Duration
+	}{
+		{name: "attempt below one", attempt: 0, want: 2 * time.Second},
+		{name: "first attempt", attempt: 1, want: 2 * time.Second},
+		{name: "second attempt", attempt: 2, want: 4 * time.Second},
+		{name: "fifth attempt", attempt: 5, want: 32 * time.Second},
+		{name: "capped", attempt: 6, want: time.Minute},
+		{name: "large attempt stays capped", attempt: 1000, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Delay(tt.attempt); got != tt.want {
+				t.Fatalf("Delay(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconnectPolicyJitter(t *testing.T) {
+	policy := ReconnectPolicy{Initial: 10 * time.Second, Max: time.Minute, Multiplier: 2, Jitter: 0.2}
+
+	tests := []struct {
+		name     string
+		attempt  int
+		min, max time.Duration
+	}{
+		{name: "first attempt", attempt: 1, min: 8 * time.Second, max: 12 * time.Second},
+		{name: "capped", attempt: 10, min: 48 * time.Second, max: 72 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				if got := policy.Delay(tt.attempt); got < tt.min || got > tt.max {
+					t.Fatalf("Delay(%d) = %v, want between %v and %v", tt.attempt, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}